@@ -11,6 +11,7 @@ import (
 	"os/signal"
 	"shared/kafka"
 	"shared/mongodb"
+	"shared/storage"
 	"shared/utils"
 	"strings"
 	"syscall"
@@ -67,6 +68,7 @@ func main() {
 	corsConfig.AllowOrigins = allowedOrigins
 	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
 	corsConfig.AllowHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+	corsConfig.ExposeHeaders = []string{"X-Total-Count"}
 	corsConfig.AllowCredentials = true
 
 	r.Use(cors.New(corsConfig))
@@ -83,10 +85,16 @@ func main() {
 	}
 	defer producer.Close()
 
+	fileStorage, err := storage.NewFileStorage()
+	if err != nil {
+		log.Fatalf("Failed to create file storage: %v", err)
+	}
+
 	// Setup routes
 	params := types.RouteParams{
 		MongoService:  mongoService,
 		KafkaProducer: producer,
+		FileStorage:   fileStorage,
 	}
 	routes.SetupRoutes(r, &params)
 
@@ -3,6 +3,7 @@ package auth
 import (
 	"api/internal/middlewares"
 	"api/internal/types"
+	"log"
 	"net/http"
 	"shared/models"
 	"shared/mongodb"
@@ -19,6 +20,11 @@ import (
 func RegisterRoutes(r *gin.RouterGroup, params *types.RouteParams) {
 	r.POST("/login", loginUser(params))
 	r.POST("/register", registerUser(params))
+
+	// Opened straight from the email sent to the address, so it can't require a login. Opening the link only asks
+	// for confirmation, link scanners open links in emails too, the responses are linked when the form is posted.
+	r.GET("/link_anonymous_responses", linkAnonymousResponsesPageHandler())
+	r.POST("/link_anonymous_responses", linkAnonymousResponsesHandler(params))
 	r.DELETE("/delete", middlewares.JWTAuthMiddleware(), deleteUser(params))
 }
 
@@ -111,6 +117,14 @@ func registerUser(params *types.RouteParams) gin.HandlerFunc {
 			PasswordHash: string(hash),
 		}
 
+		// Responses submitted without an account using this email aren't linked until the address confirms it,
+		// registering doesn't show the user owns it. The event listener emails the link.
+		anonymousResponses, err := params.MongoService.CountResponses(c, mongodb.UnlinkedAnonymousFilter(req.Email))
+		if err != nil {
+			log.Printf("Failed to count anonymous responses for a new user: %v", err)
+		}
+		newUser.AnonymousLinkRequested = anonymousResponses > 0
+
 		// Insert the new user into the database
 		_, err = params.MongoService.InsertUser(c, newUser)
 		if err != nil {
@@ -121,6 +135,7 @@ func registerUser(params *types.RouteParams) gin.HandlerFunc {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register user"})
 			return
 		}
+
 		c.JSON(http.StatusOK, gin.H{"message": "User registered successfully, please login"})
 	}
 }
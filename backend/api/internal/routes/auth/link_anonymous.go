@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"api/internal/types"
+	"bytes"
+	"html/template"
+	"net/http"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// linkAnonymousPage is shown when the link emailed to a new account's address is opened
+var linkAnonymousPage = template.Must(template.New("link_anonymous").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Add your responses</title></head>
+<body style="font-family:sans-serif;max-width:32rem;margin:4rem auto;padding:0 1rem">
+{{if .Done}}<p>The responses you sent as {{.Email}} have been added to your account.</p>
+{{else}}<p>Add the responses you sent without an account as {{.Email}} to the account registered with this address?</p>
+<form method="post" action="?token={{.Token}}">
+<button type="submit">Add my responses</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+// renderLinkAnonymousPage writes the confirmation page, before the responses are linked or once they are
+func renderLinkAnonymousPage(c *gin.Context, email string, done bool) {
+	var page bytes.Buffer
+	if err := linkAnonymousPage.Execute(&page, gin.H{"Token": c.Query("token"), "Email": email, "Done": done}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render the page"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// linkAnonymousResponsesPageHandler asks the owner of the address to confirm before anything is linked
+func linkAnonymousResponsesPageHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, email, err := utils.VerifyAnonymousLinkToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link"})
+			return
+		}
+
+		renderLinkAnonymousPage(c, email, false)
+	}
+}
+
+// linkAnonymousResponsesHandler gives the account the anonymous responses sent with its email. The token was only
+// sent to that address, so posting it shows the account's owner also owns the address.
+func linkAnonymousResponsesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, email, err := utils.VerifyAnonymousLinkToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid link"})
+			return
+		}
+
+		// The account may have been deleted since, its ID isn't reused for another with the same email
+		user, err := params.MongoService.FindUserByID(c, userID)
+		if err != nil || user == nil || !strings.EqualFold(user.Email, email) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This link is for an account that no longer exists"})
+			return
+		}
+
+		if _, err := params.MongoService.LinkAnonymousResponses(c, email, userID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add the responses to your account"})
+			return
+		}
+
+		renderLinkAnonymousPage(c, email, true)
+	}
+}
@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"api/internal/types"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// anonymousStore has anonymous responses for every email and records the users registered and linked
+type anonymousStore struct {
+	*mongodb.MockMongoService
+	registered *models.User
+	linked     []string
+}
+
+func (s *anonymousStore) CountResponses(ctx context.Context, filter bson.M) (int64, error) {
+	return 1, nil
+}
+
+func (s *anonymousStore) InsertUser(ctx context.Context, user models.User) (*mongo.InsertOneResult, error) {
+	user.ID = primitive.NewObjectID()
+	s.registered = &user
+	return &mongo.InsertOneResult{InsertedID: user.ID}, nil
+}
+
+func (s *anonymousStore) FindUserByID(ctx context.Context, userID primitive.ObjectID) (*models.User, error) {
+	if s.registered == nil || s.registered.ID != userID {
+		return nil, mongo.ErrNoDocuments
+	}
+	return s.registered, nil
+}
+
+func (s *anonymousStore) LinkAnonymousResponses(ctx context.Context, email string, userID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	s.linked = append(s.linked, email)
+	return &mongo.UpdateResult{ModifiedCount: 1}, nil
+}
+
+func TestLinkAnonymousResponses(t *testing.T) {
+	store := &anonymousStore{MockMongoService: mongodb.NewMockMongoService()}
+	r := gin.New()
+	RegisterRoutes(r.Group("/auth"), &types.RouteParams{MongoService: store})
+
+	// Registering with the address only asks for the link to be emailed
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewReader([]byte(
+		`{"email":"ada@example.com","password":"myPassword123!333#","firstName":"Ada","lastName":"Lovelace","birthday":"01/01/1995"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, store.registered)
+	assert.Empty(t, store.linked)
+
+	user := store.registered
+	assert.True(t, user.AnonymousLinkRequested)
+
+	token, err := utils.GenerateAnonymousLinkToken(user.ID, user.Email)
+	require.NoError(t, err)
+	link := "/auth/link_anonymous_responses?token=" + url.QueryEscape(token)
+
+	// Opening the link only shows the confirmation
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, link, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<form method="post"`)
+	assert.Empty(t, store.linked)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, link, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, []string{"ada@example.com"}, store.linked)
+
+	// A link for another account's ID or a tampered token links nothing
+	otherToken, err := utils.GenerateAnonymousLinkToken(primitive.NewObjectID(), user.Email)
+	require.NoError(t, err)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/auth/link_anonymous_responses?token="+url.QueryEscape(otherToken), nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, link+"x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, store.linked, 1)
+}
@@ -0,0 +1,175 @@
+package emails
+
+import (
+	"api/internal/middlewares"
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func RegisterEmailCampaignRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	r.GET(":campaign_id", middlewares.JWTAuthMiddleware(), getEmailCampaign(params))
+	r.POST("", middlewares.JWTAuthMiddleware(), createEmailCampaign(params))
+	r.POST("preview", middlewares.JWTAuthMiddleware(), previewEmailCampaign(params))
+	r.POST(":campaign_id/cancel", middlewares.JWTAuthMiddleware(), cancelEmailCampaign(params))
+}
+
+func getEmailCampaign(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		campaignID, err := primitive.ObjectIDFromHex(c.Param("campaign_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+			return
+		}
+
+		campaign, err := params.MongoService.GetEmailCampaign(c, campaignID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email campaign not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailCampaign(c, params.MongoService, authenticatedUser, campaignID, campaign) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this email campaign"})
+			return
+		}
+
+		c.JSON(http.StatusOK, campaign)
+	}
+}
+
+type previewEmailCampaignRequest struct {
+	EventID primitive.ObjectID    `json:"eventID" validate:"required"`
+	Filter  models.ResponseFilter `json:"filter" validate:"required"`
+}
+
+// previewEmailCampaign returns how many responses a campaign filter currently matches
+func previewEmailCampaign(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req previewEmailCampaignRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if !canUserTargetForm(c, params, authenticatedUser, req.EventID, req.Filter.FormID) {
+			return
+		}
+
+		count, err := params.MongoService.CountResponses(c, mongodb.ResponseFilterToBSON(req.Filter))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count recipients"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"recipientCount": count})
+	}
+}
+
+func createEmailCampaign(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req models.EmailCampaign
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if !canUserTargetForm(c, params, authenticatedUser, req.EventID, req.Filter.FormID) {
+			return
+		}
+
+		template, err := params.MongoService.GetEmailTemplate(c, req.EmailTemplateID)
+		if err != nil || template.EventID != req.EventID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email template does not exist on this event"})
+			return
+		}
+
+		req.CreatedBy = authenticatedUser.ID
+		campaignID, err := params.MongoService.CreateEmailCampaign(c, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create email campaign"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": campaignID})
+	}
+}
+
+func cancelEmailCampaign(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		campaignID, err := primitive.ObjectIDFromHex(c.Param("campaign_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailCampaign(c, params.MongoService, authenticatedUser, campaignID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to cancel this email campaign"})
+			return
+		}
+
+		result, err := params.MongoService.CancelEmailCampaign(c, campaignID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel email campaign"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Email campaign has already finished"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email campaign cancelled successfully"})
+	}
+}
+
+// canUserTargetForm checks the user organizes the event and that the form belongs to it, writing the error response if not
+func canUserTargetForm(c *gin.Context, params *types.RouteParams, user *models.User, eventID primitive.ObjectID, formID primitive.ObjectID) bool {
+	if !mongodb.CanUserModifyEvent(c, params.MongoService, user, eventID, nil) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to email applicants of this event"})
+		return false
+	}
+
+	form, err := params.MongoService.GetForm(c, formID, true)
+	if err != nil || form.EventID != eventID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist on this event"})
+		return false
+	}
+
+	return true
+}
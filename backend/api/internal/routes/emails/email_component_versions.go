@@ -0,0 +1,81 @@
+package emails
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/mongodb"
+	"shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// loadVersionedComponent parses the component ID, loads the component and checks the user can modify it, writing the error response if not
+func loadVersionedComponent(c *gin.Context, params *types.RouteParams) (primitive.ObjectID, bool) {
+	authenticatedUser, ok := utils.GetUserFromContext(c, true)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	componentID, err := primitive.ObjectIDFromHex(c.Param("component_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+		return primitive.NilObjectID, false
+	}
+
+	component, err := params.MongoService.GetEmailComponent(c, componentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Email component not found"})
+		return primitive.NilObjectID, false
+	}
+
+	if !mongodb.CanUserModifyEmailComponent(c, params.MongoService, authenticatedUser, componentID, component) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this email component"})
+		return primitive.NilObjectID, false
+	}
+
+	return componentID, true
+}
+
+// listComponentVersions lists every saved version of a layout or partial, pipeline runs and campaigns
+// record which ones they were rendered with
+func listComponentVersions(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		componentID, ok := loadVersionedComponent(c, params)
+		if !ok {
+			return
+		}
+
+		versions, err := params.MongoService.ListEmailComponentVersions(c, componentID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving email component versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"versions": versions})
+	}
+}
+
+func getComponentVersion(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		componentID, ok := loadVersionedComponent(c, params)
+		if !ok {
+			return
+		}
+
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil || version < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component version"})
+			return
+		}
+
+		componentVersion, err := params.MongoService.GetEmailComponentVersion(c, componentID, version)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email component version not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, componentVersion)
+	}
+}
@@ -0,0 +1,257 @@
+package emails
+
+import (
+	"api/internal/middlewares"
+	"api/internal/types"
+	"fmt"
+	"net/http"
+	"regexp"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// partialNameRegex matches the names that can be used in a {{> name}} include
+var partialNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+func RegisterEmailComponentRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	r.GET(":component_id", middlewares.JWTAuthMiddleware(), getEmailComponent(params))
+	r.GET(":component_id/versions", middlewares.JWTAuthMiddleware(), listComponentVersions(params))
+	r.GET(":component_id/versions/:version", middlewares.JWTAuthMiddleware(), getComponentVersion(params))
+	r.POST("", middlewares.JWTAuthMiddleware(), createEmailComponent(params))
+	r.PUT(":component_id", middlewares.JWTAuthMiddleware(), updateEmailComponent(params))
+	r.DELETE(":component_id", middlewares.JWTAuthMiddleware(), deleteEmailComponent(params))
+}
+
+func getEmailComponent(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		componentID, err := primitive.ObjectIDFromHex(c.Param("component_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+			return
+		}
+
+		component, err := params.MongoService.GetEmailComponent(c, componentID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email component not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailComponent(c, params.MongoService, authenticatedUser, componentID, component) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this email component"})
+			return
+		}
+
+		c.JSON(http.StatusOK, component)
+	}
+}
+
+func createEmailComponent(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req models.EmailComponent
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, req.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to modify this event"})
+			return
+		}
+
+		if !validateEmailComponent(c, params, req, primitive.NilObjectID) {
+			return
+		}
+
+		req.UpdatedAt = time.Now()
+		req.UpdatedBy = authenticatedUser.ID
+		componentID, err := params.MongoService.CreateEmailComponent(c, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create email component"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": componentID})
+	}
+}
+
+func updateEmailComponent(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		componentID, err := primitive.ObjectIDFromHex(c.Param("component_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+			return
+		}
+
+		var req models.EmailComponent
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		existing, err := params.MongoService.GetEmailComponent(c, componentID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email component not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailComponent(c, params.MongoService, authenticatedUser, componentID, existing) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to update this email component"})
+			return
+		}
+
+		// Templates reference layouts by ID and partials by name, so neither can change kind or event
+		req.ID = componentID
+		req.EventID = existing.EventID
+		req.Kind = existing.Kind
+
+		if !validateEmailComponent(c, params, req, componentID) {
+			return
+		}
+
+		if req.Name != existing.Name && !checkComponentUnused(c, params, *existing) {
+			return
+		}
+
+		req.UpdatedAt = time.Now()
+		req.UpdatedBy = authenticatedUser.ID
+		if _, err := params.MongoService.UpdateEmailComponent(c, req, componentID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update email component"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email component updated successfully"})
+	}
+}
+
+func deleteEmailComponent(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		componentID, err := primitive.ObjectIDFromHex(c.Param("component_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid component ID"})
+			return
+		}
+
+		component, err := params.MongoService.GetEmailComponent(c, componentID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email component not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailComponent(c, params.MongoService, authenticatedUser, componentID, component) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to delete this email component"})
+			return
+		}
+
+		if !checkComponentUnused(c, params, *component) {
+			return
+		}
+
+		if _, err := params.MongoService.DeleteEmailComponent(c, componentID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete email component"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email component deleted successfully"})
+	}
+}
+
+// validateEmailComponent checks the component's fields and that its name is unique within the event, writing the error response if not
+func validateEmailComponent(c *gin.Context, params *types.RouteParams, component models.EmailComponent, componentID primitive.ObjectID) bool {
+	if errors := utils.ValidateStruct(utils.Validator, component); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+		return false
+	}
+
+	if component.Kind == models.EmailComponentLayout && !utils.LayoutHasContentSlot(component.Body) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.ErrLayoutMissingContent.Error()})
+		return false
+	}
+
+	if component.Kind == models.EmailComponentPartial && !partialNameRegex.MatchString(component.Name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Partial names can only contain letters, numbers, - and _"})
+		return false
+	}
+
+	existing, err := params.MongoService.ListEmailComponents(c, bson.M{"eventID": component.EventID, "kind": component.Kind, "name": component.Name})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating email component"})
+		return false
+	}
+
+	for _, other := range existing {
+		if other.ID != componentID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A %s named %s already exists on this event", component.Kind, component.Name)})
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkComponentUnused makes sure no template, layout or partial depends on the component before it's
+// deleted or a partial is renamed, writing the error response if one does
+func checkComponentUnused(c *gin.Context, params *types.RouteParams, component models.EmailComponent) bool {
+	templates, err := params.MongoService.ListEmailTemplates(c, bson.M{"eventID": component.EventID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking where the email component is used"})
+		return false
+	}
+
+	var usedBy []string
+	for _, template := range templates {
+		if component.Kind == models.EmailComponentLayout && template.LayoutID == component.ID {
+			usedBy = append(usedBy, template.Name)
+		}
+		if component.Kind == models.EmailComponentPartial && utils.StringInSlice(component.Name, utils.ExtractTemplatePartials(template.Body)) {
+			usedBy = append(usedBy, template.Name)
+		}
+	}
+
+	if component.Kind == models.EmailComponentPartial {
+		components, err := params.MongoService.ListEmailComponents(c, bson.M{"eventID": component.EventID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking where the email component is used"})
+			return false
+		}
+
+		for _, other := range components {
+			if other.ID != component.ID && utils.StringInSlice(component.Name, utils.ExtractTemplatePartials(other.Body)) {
+				usedBy = append(usedBy, other.Name)
+			}
+		}
+	}
+
+	if len(usedBy) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is used by %s", component.Name, strings.Join(usedBy, ", "))})
+		return false
+	}
+
+	return true
+}
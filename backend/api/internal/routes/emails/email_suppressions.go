@@ -0,0 +1,169 @@
+package emails
+
+import (
+	"api/internal/middlewares"
+	"api/internal/types"
+	"bytes"
+	"html/template"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// unsubscribePage is shown when an unsubscribe link is opened. Opening the link only asks for confirmation, link
+// scanners and prefetchers open links in emails too, the address is suppressed when the form is posted.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><meta name="viewport" content="width=device-width, initial-scale=1"><title>Unsubscribe</title></head>
+<body style="font-family:sans-serif;max-width:32rem;margin:4rem auto;padding:0 1rem">
+{{if .Done}}<p>{{.Email}} has been unsubscribed.</p>
+{{else}}<p>Stop sending emails like this one to {{.Email}}?</p>
+<form method="post" action="?token={{.Token}}">
+<p><label><input type="checkbox" name="scope" value="all"{{if .All}} checked{{end}}> Unsubscribe from every event's emails</label></p>
+<button type="submit">Unsubscribe</button>
+</form>
+{{end}}<p style="font-size:12px;color:#666">You'll still get emails about your own responses, like confirmations and decisions.</p>
+</body>
+</html>
+`))
+
+func RegisterEmailSuppressionRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	// Unsubscribe links are opened straight from an email so they can't require a login,
+	// POST is used by the confirmation page and by mail clients for one-click unsubscribe (RFC 8058)
+	r.GET("unsubscribe", unsubscribePageHandler())
+	r.POST("unsubscribe", unsubscribeHandler(params))
+
+	r.POST("", middlewares.JWTAuthMiddleware(), createEmailSuppression(params))
+	r.DELETE(":suppression_id", middlewares.JWTAuthMiddleware(), deleteEmailSuppression(params))
+}
+
+// renderUnsubscribePage writes the unsubscribe page, before the address is suppressed or once it is
+func renderUnsubscribePage(c *gin.Context, email string, all bool, done bool) {
+	var page bytes.Buffer
+	if err := unsubscribePage.Execute(&page, gin.H{"Token": c.Query("token"), "Email": email, "All": all, "Done": done}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render the unsubscribe page"})
+		return
+	}
+
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// unsubscribePageHandler asks the recipient to confirm before anything is suppressed
+func unsubscribePageHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, _, err := utils.VerifyUnsubscribeToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unsubscribe link"})
+			return
+		}
+
+		renderUnsubscribePage(c, email, c.Query("scope") == "all", false)
+	}
+}
+
+// unsubscribeHandler suppresses the address in a signed unsubscribe token.
+// By default only the event that sent the email is suppressed, scope=all suppresses every event's marketing emails.
+// Transactional emails, like decisions, are still sent to addresses that unsubscribed.
+func unsubscribeHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, eventID, err := utils.VerifyUnsubscribeToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unsubscribe link"})
+			return
+		}
+
+		suppression := models.EmailSuppression{
+			EventID: eventID,
+			Email:   email,
+			Reason:  models.EmailSuppressionUnsubscribed,
+		}
+		all := c.PostForm("scope") == "all"
+		if all {
+			suppression.EventID = primitive.NilObjectID
+		}
+
+		if _, err := params.MongoService.CreateEmailSuppression(c, suppression); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to unsubscribe"})
+			return
+		}
+
+		renderUnsubscribePage(c, email, all, true)
+	}
+}
+
+func createEmailSuppression(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req models.EmailSuppression
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		// Organizers can only manage their own event's list, the global list is only written by recipients
+		if req.IsGlobal() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "eventID is required"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, req.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to modify this event's suppression list"})
+			return
+		}
+
+		req.CreatedBy = authenticatedUser.ID
+		if _, err := params.MongoService.CreateEmailSuppression(c, req); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add email to the suppression list"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email added to the suppression list"})
+	}
+}
+
+func deleteEmailSuppression(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		suppressionID, err := primitive.ObjectIDFromHex(c.Param("suppression_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid suppression ID"})
+			return
+		}
+
+		suppression, err := params.MongoService.GetEmailSuppression(c, suppressionID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Suppression not found"})
+			return
+		}
+
+		if suppression.IsGlobal() || !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, suppression.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to delete this suppression"})
+			return
+		}
+
+		if _, err := params.MongoService.DeleteEmailSuppression(c, suppressionID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete suppression"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email removed from the suppression list"})
+	}
+}
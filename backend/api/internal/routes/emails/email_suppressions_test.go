@@ -0,0 +1,72 @@
+package emails
+
+import (
+	"api/internal/types"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// suppressionStore records the suppressions created through the unsubscribe routes
+type suppressionStore struct {
+	*mongodb.MockMongoService
+	created []models.EmailSuppression
+}
+
+func (s *suppressionStore) CreateEmailSuppression(ctx context.Context, suppression models.EmailSuppression) (*mongo.UpdateResult, error) {
+	s.created = append(s.created, suppression)
+	return &mongo.UpdateResult{UpsertedCount: 1}, nil
+}
+
+func TestUnsubscribe(t *testing.T) {
+	store := &suppressionStore{MockMongoService: mongodb.NewMockMongoService()}
+	r := gin.New()
+	RegisterEmailSuppressionRoutes(r.Group("/email_suppressions"), &types.RouteParams{MongoService: store})
+
+	eventID := primitive.NewObjectID()
+	token, err := utils.GenerateUnsubscribeToken("ada@example.com", eventID)
+	require.NoError(t, err)
+	link := "/email_suppressions/unsubscribe?token=" + url.QueryEscape(token)
+
+	// Opening the link, like a link scanner would, only shows the confirmation
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, link, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `<form method="post"`)
+	assert.Empty(t, store.created)
+
+	// One-click unsubscribe from the mail client only suppresses the event
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, link, strings.NewReader("List-Unsubscribe=One-Click"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, store.created, 1)
+	assert.Equal(t, eventID, store.created[0].EventID)
+	assert.Equal(t, models.EmailSuppressionUnsubscribed, store.created[0].Reason)
+
+	// The confirmation page can unsubscribe from every event
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, link, strings.NewReader("scope=all"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(w, req)
+	require.Len(t, store.created, 2)
+	assert.True(t, store.created[1].IsGlobal())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, link+"x", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Len(t, store.created, 2)
+}
@@ -0,0 +1,233 @@
+package emails
+
+import (
+	"api/internal/types"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// templateFieldChange is a template field other than the body that differs between two versions
+type templateFieldChange struct {
+	Field  string      `json:"field"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type templateDiff struct {
+	From   int                   `json:"from"`
+	To     int                   `json:"to"`
+	Fields []templateFieldChange `json:"fields"`
+	Body   []utils.DiffLine      `json:"body"`
+}
+
+// templateDiffIgnoredFields change on every save so they aren't shown in a diff
+var templateDiffIgnoredFields = []string{"id", "body", "version", "updatedAt", "updatedBy"}
+
+// diffTemplates compares every field of two templates, the body is diffed line by line
+func diffTemplates(before models.EmailTemplate, after models.EmailTemplate) ([]templateFieldChange, []utils.DiffLine, error) {
+	beforeFields, err := templateToMap(before)
+	if err != nil {
+		return nil, nil, err
+	}
+	afterFields, err := templateToMap(after)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	for key := range afterFields {
+		if !utils.StringInSlice(key, templateDiffIgnoredFields) {
+			keys = append(keys, key)
+		}
+	}
+	for key := range beforeFields {
+		if _, ok := afterFields[key]; !ok && !utils.StringInSlice(key, templateDiffIgnoredFields) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []templateFieldChange{}
+	for _, key := range keys {
+		if !reflect.DeepEqual(beforeFields[key], afterFields[key]) {
+			changes = append(changes, templateFieldChange{Field: key, Before: beforeFields[key], After: afterFields[key]})
+		}
+	}
+
+	return changes, utils.DiffLines(before.Body, after.Body), nil
+}
+
+// templateToMap converts a template to its JSON fields so they can be compared generically
+func templateToMap(template models.EmailTemplate) (map[string]interface{}, error) {
+	raw, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]interface{}{}
+	err = json.Unmarshal(raw, &fields)
+	return fields, err
+}
+
+// loadVersionedTemplate parses the template ID, loads the template and checks the user can modify it, writing the error response if not
+func loadVersionedTemplate(c *gin.Context, params *types.RouteParams) (*models.User, *models.EmailTemplate, bool) {
+	authenticatedUser, ok := utils.GetUserFromContext(c, true)
+	if !ok {
+		return nil, nil, false
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(c.Param("template_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID"})
+		return nil, nil, false
+	}
+
+	template, err := params.MongoService.GetEmailTemplate(c, templateID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Email template not found"})
+		return nil, nil, false
+	}
+
+	if !mongodb.CanUserModifyEmailTemplate(c, params.MongoService, authenticatedUser, templateID, template) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this email template"})
+		return nil, nil, false
+	}
+
+	return authenticatedUser, template, true
+}
+
+// loadTemplateVersion loads the version named by a route or query parameter, writing the error response if it doesn't exist
+func loadTemplateVersion(c *gin.Context, params *types.RouteParams, templateID primitive.ObjectID, versionParam string) (*models.EmailTemplateVersion, bool) {
+	version, err := strconv.Atoi(versionParam)
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template version"})
+		return nil, false
+	}
+
+	templateVersion, err := params.MongoService.GetEmailTemplateVersion(c, templateID, version)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Email template version not found"})
+		return nil, false
+	}
+
+	return templateVersion, true
+}
+
+func listTemplateVersions(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, template, ok := loadVersionedTemplate(c, params)
+		if !ok {
+			return
+		}
+
+		versions, err := params.MongoService.ListEmailTemplateVersions(c, template.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving email template versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"versions": versions})
+	}
+}
+
+func getTemplateVersion(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, template, ok := loadVersionedTemplate(c, params)
+		if !ok {
+			return
+		}
+
+		version, ok := loadTemplateVersion(c, params, template.ID, c.Param("version"))
+		if !ok {
+			return
+		}
+
+		c.JSON(http.StatusOK, version)
+	}
+}
+
+// diffTemplateVersion compares a version with ?against=, which defaults to the version before it
+func diffTemplateVersion(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, template, ok := loadVersionedTemplate(c, params)
+		if !ok {
+			return
+		}
+
+		to, ok := loadTemplateVersion(c, params, template.ID, c.Param("version"))
+		if !ok {
+			return
+		}
+
+		against := c.Query("against")
+		if against == "" {
+			// The first version is compared against an empty template
+			against = strconv.Itoa(to.Version - 1)
+		}
+
+		from := &models.EmailTemplateVersion{}
+		if against != "0" {
+			if from, ok = loadTemplateVersion(c, params, template.ID, against); !ok {
+				return
+			}
+		}
+
+		fields, body, err := diffTemplates(from.Template, to.Template)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error comparing email template versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, templateDiff{From: from.Version, To: to.Version, Fields: fields, Body: body})
+	}
+}
+
+// rollbackTemplateVersion saves an old version as the newest one, the versions in between are kept
+func rollbackTemplateVersion(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, template, ok := loadVersionedTemplate(c, params)
+		if !ok {
+			return
+		}
+
+		version, ok := loadTemplateVersion(c, params, template.ID, c.Param("version"))
+		if !ok {
+			return
+		}
+
+		restored := version.Template
+		restored.ID = template.ID
+		restored.EventID = template.EventID
+
+		// The form, layout or SMTP config may have changed since the version was saved
+		validation, err := loadAndValidateTemplate(c, params, restored, template.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating email template"})
+			return
+		}
+
+		if len(validation.Errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validation.errorMessage(), "validation": validation})
+			return
+		}
+
+		restored.UpdatedAt = time.Now()
+		restored.UpdatedBy = authenticatedUser.ID
+		if _, err := params.MongoService.UpdateEmailTemplate(c, restored, template.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to roll back email template"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Email template rolled back successfully", "warnings": validation.Warnings})
+	}
+}
@@ -16,8 +16,14 @@ import (
 func RegisterEmailTemplateRoutes(r *gin.RouterGroup, params *types.RouteParams) {
 	r.GET(":template_id", middlewares.JWTAuthMiddleware(), getEmailTemplate(params))
 	r.POST("", middlewares.JWTAuthMiddleware(), createNewTemplate(params))
+	r.POST("validate", middlewares.JWTAuthMiddleware(), validateTemplateHandler(params))
 	r.PUT(":template_id", middlewares.JWTAuthMiddleware(), updateTemplate(params))
 	r.DELETE(":template_id", middlewares.JWTAuthMiddleware(), deleteTemplate(params))
+
+	r.GET(":template_id/versions", middlewares.JWTAuthMiddleware(), listTemplateVersions(params))
+	r.GET(":template_id/versions/:version", middlewares.JWTAuthMiddleware(), getTemplateVersion(params))
+	r.GET(":template_id/versions/:version/diff", middlewares.JWTAuthMiddleware(), diffTemplateVersion(params))
+	r.POST(":template_id/versions/:version/rollback", middlewares.JWTAuthMiddleware(), rollbackTemplateVersion(params))
 }
 
 func getEmailTemplate(params *types.RouteParams) gin.HandlerFunc {
@@ -76,14 +82,26 @@ func createNewTemplate(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
+		validation, err := loadAndValidateTemplate(c, params, template, primitive.NilObjectID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating email template"})
+			return
+		}
+
+		if len(validation.Errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validation.errorMessage(), "validation": validation})
+			return
+		}
+
 		template.UpdatedAt = time.Now()
+		template.UpdatedBy = authenticatedUser.ID
 		templateID, err := params.MongoService.CreateEmailTemplate(c, template)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error creating email template"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"id": templateID})
+		c.JSON(http.StatusOK, gin.H{"id": templateID, "warnings": validation.Warnings})
 	}
 }
 
@@ -106,19 +124,75 @@ func updateTemplate(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		if !mongodb.CanUserModifyEmailTemplate(c, params.MongoService, authenticatedUser, templateID, nil) {
+		existingTemplate, err := params.MongoService.GetEmailTemplate(c, templateID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Email template not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEmailTemplate(c, params.MongoService, authenticatedUser, templateID, existingTemplate) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to update this pipeline"})
 			return
 		}
 
+		// A template can't be moved to another event
+		req.ID = templateID
+		req.EventID = existingTemplate.EventID
+
+		validation, err := loadAndValidateTemplate(c, params, req, templateID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating email template"})
+			return
+		}
+
+		if len(validation.Errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": validation.errorMessage(), "validation": validation})
+			return
+		}
+
 		req.UpdatedAt = time.Now()
+		req.UpdatedBy = authenticatedUser.ID
 		_, err = params.MongoService.UpdateEmailTemplate(c, req, templateID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pipeline configuration"})
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Pipeline configuration updated successfully"})
+		c.JSON(http.StatusOK, gin.H{"message": "Pipeline configuration updated successfully", "warnings": validation.Warnings})
+	}
+}
+
+type validateTemplateRequest struct {
+	Template   models.EmailTemplate `json:"template"`
+	TemplateID primitive.ObjectID   `json:"templateID"`
+}
+
+// validateTemplateHandler validates a template without saving it, so the editor can show problems as they're made
+func validateTemplateHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req validateTemplateRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, req.Template.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		validation, err := loadAndValidateTemplate(c, params, req.Template, req.TemplateID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating email template"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"validation": validation})
 	}
 }
 
@@ -0,0 +1,190 @@
+package emails
+
+import (
+	"api/internal/types"
+	"errors"
+	"fmt"
+	"net/mail"
+	"shared/models"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// templateIssue is a single problem found with an email template
+type templateIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// templateValidation is returned by the API, errors block saving the template while warnings don't
+type templateValidation struct {
+	Errors   []templateIssue `json:"errors"`
+	Warnings []templateIssue `json:"warnings"`
+}
+
+func (v *templateValidation) addError(field string, format string, args ...interface{}) {
+	v.Errors = append(v.Errors, templateIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *templateValidation) addWarning(field string, format string, args ...interface{}) {
+	v.Warnings = append(v.Warnings, templateIssue{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// errorMessage joins the errors into the single string the API returns under "error"
+func (v *templateValidation) errorMessage() string {
+	messages := make([]string, len(v.Errors))
+	for i, issue := range v.Errors {
+		messages[i] = issue.Message
+	}
+	return strings.Join(messages, "\n")
+}
+
+// loadAndValidateTemplate loads everything a template depends on and validates it.
+// templateID is zero for a template that hasn't been created yet.
+func loadAndValidateTemplate(c *gin.Context, params *types.RouteParams, template models.EmailTemplate, templateID primitive.ObjectID) (templateValidation, error) {
+	var form *models.FormStructure
+	if !template.DataFromFormID.IsZero() {
+		f, err := params.MongoService.GetForm(c, template.DataFromFormID, true)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return templateValidation{}, err
+		}
+		form = f
+	}
+
+	// Not stripped since we compare against the username, nothing from it is returned
+	var smtpConfig *models.EmailSecret
+	secrets, err := params.MongoService.GetEventSecrets(c, bson.M{"eventID": template.EventID}, false)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return templateValidation{}, err
+	}
+	if secrets != nil {
+		smtpConfig = secrets.Email
+	}
+
+	var pipelines []models.PipelineConfiguration
+	if !templateID.IsZero() {
+		pipelines, err = params.MongoService.ListPipelines(c, bson.M{"eventID": template.EventID, "actions.sendEmail.emailTemplateID": templateID})
+		if err != nil {
+			return templateValidation{}, err
+		}
+	}
+
+	components, err := params.MongoService.ListEmailComponents(c, bson.M{"eventID": template.EventID})
+	if err != nil {
+		return templateValidation{}, err
+	}
+
+	return validateTemplate(template, templateID, form, smtpConfig, pipelines, components), nil
+}
+
+// validateTemplate checks the template's placeholders against its source form, its From address against
+// the event's SMTP config, and the email fields of the pipelines that send it.
+// The body is checked after it's composed with its layout and partials from components.
+func validateTemplate(template models.EmailTemplate, templateID primitive.ObjectID, form *models.FormStructure, smtpConfig *models.EmailSecret, pipelines []models.PipelineConfiguration, components []models.EmailComponent) templateValidation {
+	result := templateValidation{Errors: []templateIssue{}, Warnings: []templateIssue{}}
+
+	for _, e := range utils.ValidateStruct(utils.Validator, template) {
+		result.addError("", e)
+	}
+
+	if !template.DataFromFormID.IsZero() && (form == nil || form.EventID != template.EventID) {
+		result.addError("dataFromFormID", "Form %s does not exist on this event", template.DataFromFormID.Hex())
+		form = nil
+	}
+
+	// Placeholders
+	fieldKeys := map[string]bool{}
+	if form != nil {
+		for _, attr := range form.Attrs {
+			fieldKeys[attr.Key] = true
+		}
+	}
+
+	body, err := utils.ComposeEmailTemplateBody(template, components)
+	if err != nil {
+		field := "body"
+		if errors.Is(err, utils.ErrLayoutNotFound) || errors.Is(err, utils.ErrLayoutMissingContent) {
+			field = "layoutID"
+		}
+		result.addError(field, "%s", err.Error())
+		body = template.Body
+	}
+
+	for _, field := range []string{"subject", "body"} {
+		text := template.Subject
+		if field == "body" {
+			text = body
+		}
+
+		for _, placeholder := range utils.ExtractTemplatePlaceholders(text) {
+			// Links filled in when the email is sent rather than from form data
+			if placeholder == utils.FormInviteLinkPlaceholder || placeholder == utils.ResponseConfirmLinkPlaceholder {
+				continue
+			}
+
+			if template.DataFromFormID.IsZero() {
+				result.addError(field, "${%s} is used but the template has no form to take data from", placeholder)
+			} else if form != nil && !fieldKeys[placeholder] {
+				result.addError(field, "${%s} is not a field on form %s", placeholder, form.Name)
+			}
+		}
+	}
+
+	// Pipelines sending this template need their email field to exist on the form too
+	if form != nil {
+		for _, pipeline := range pipelines {
+			for _, action := range pipeline.Actions {
+				if action.SendEmail == nil || action.SendEmail.EmailTemplateID != templateID {
+					continue
+				}
+
+				if !fieldKeys[action.SendEmail.EmailFieldID] {
+					result.addError("dataFromFormID", "Pipeline %s sends to email field %s which is not on form %s", pipeline.Name, action.SendEmail.EmailFieldID, form.Name)
+				}
+			}
+		}
+	}
+
+	// From address
+	if template.From != "" {
+		validateFromAddress(&result, template.From, smtpConfig)
+	}
+
+	return result
+}
+
+func validateFromAddress(result *templateValidation, from string, smtpConfig *models.EmailSecret) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		result.addError("from", "%s is not a valid email address", from)
+		return
+	}
+	fromEmail := strings.ToLower(address.Address)
+	fromDomain := fromEmail[strings.LastIndex(fromEmail, "@"):]
+
+	if smtpConfig == nil {
+		result.addWarning("from", "The event has no email configuration, this template can't be sent until one is added")
+		return
+	}
+
+	if len(smtpConfig.AllowedFrom) > 0 {
+		for _, allowed := range smtpConfig.AllowedFrom {
+			allowed = strings.ToLower(strings.TrimSpace(allowed))
+			if allowed == fromEmail || allowed == fromDomain {
+				return
+			}
+		}
+		result.addError("from", "%s is not an allowed From address for this event's email configuration", fromEmail)
+		return
+	}
+
+	// Without an explicit list most SMTP providers only accept the authenticated user's domain
+	if username := strings.ToLower(smtpConfig.Username); strings.Contains(username, "@") && !strings.HasSuffix(username, fromDomain) {
+		result.addWarning("from", "%s does not match the SMTP username's domain, the email server may reject it", fromEmail)
+	}
+}
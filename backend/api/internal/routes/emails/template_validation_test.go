@@ -0,0 +1,136 @@
+package emails
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateTemplate(t *testing.T) {
+	eventID := primitive.NewObjectID()
+	templateID := primitive.NewObjectID()
+	form := &models.FormStructure{
+		ID:      primitive.NewObjectID(),
+		EventID: eventID,
+		Name:    "Application",
+		Attrs: []models.FormField{
+			{Key: "name", Question: "Name", Type: "text"},
+			{Key: "email", Question: "Email", Type: "text"},
+		},
+	}
+	smtpConfig := &models.EmailSecret{Username: "team@hackathon.dev"}
+
+	baseTemplate := func() models.EmailTemplate {
+		return models.EmailTemplate{
+			EventID:        eventID,
+			Name:           "Accepted",
+			From:           "Hackathon <team@hackathon.dev>",
+			Subject:        "Welcome ${name}",
+			Body:           "Hi ${name}!",
+			DataFromFormID: form.ID,
+		}
+	}
+
+	t.Run("valid template", func(t *testing.T) {
+		result := validateTemplate(baseTemplate(), templateID, form, smtpConfig, nil, nil)
+		assert.Empty(t, result.Errors)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("unknown placeholder", func(t *testing.T) {
+		template := baseTemplate()
+		template.Body = "Hi ${nmae}!"
+
+		result := validateTemplate(template, templateID, form, smtpConfig, nil, nil)
+		assert.Equal(t, []templateIssue{{Field: "body", Message: "${nmae} is not a field on form Application"}}, result.Errors)
+	})
+
+	t.Run("placeholder without a source form", func(t *testing.T) {
+		template := baseTemplate()
+		template.DataFromFormID = primitive.NilObjectID
+
+		result := validateTemplate(template, templateID, nil, smtpConfig, nil, nil)
+		assert.Len(t, result.Errors, 2)
+	})
+
+	t.Run("invite link placeholder", func(t *testing.T) {
+		template := baseTemplate()
+		template.Body = "Hi ${name}, apply here: ${invite_link}"
+
+		result := validateTemplate(template, templateID, form, smtpConfig, nil, nil)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("form from another event", func(t *testing.T) {
+		otherForm := *form
+		otherForm.EventID = primitive.NewObjectID()
+
+		result := validateTemplate(baseTemplate(), templateID, &otherForm, smtpConfig, nil, nil)
+		assert.Equal(t, "dataFromFormID", result.Errors[0].Field)
+	})
+
+	t.Run("pipeline email field missing from form", func(t *testing.T) {
+		pipelines := []models.PipelineConfiguration{{
+			Name: "Accept",
+			Actions: []models.PipelineAction{{
+				Type:      "SendEmail",
+				SendEmail: &models.SendEmail{EmailTemplateID: templateID, EmailFieldID: "emial"},
+			}},
+		}}
+
+		result := validateTemplate(baseTemplate(), templateID, form, smtpConfig, pipelines, nil)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "dataFromFormID", result.Errors[0].Field)
+		assert.Empty(t, result.Warnings)
+	})
+
+	t.Run("from address not allowed", func(t *testing.T) {
+		restricted := &models.EmailSecret{AllowedFrom: []string{"@other.dev"}}
+
+		result := validateTemplate(baseTemplate(), templateID, form, restricted, nil, nil)
+		assert.Equal(t, "from", result.Errors[0].Field)
+
+		restricted.AllowedFrom = []string{"@hackathon.dev"}
+		result = validateTemplate(baseTemplate(), templateID, form, restricted, nil, nil)
+		assert.Empty(t, result.Errors)
+	})
+
+	t.Run("from address on another domain than the smtp user", func(t *testing.T) {
+		template := baseTemplate()
+		template.From = "someone@gmail.com"
+
+		result := validateTemplate(template, templateID, form, smtpConfig, nil, nil)
+		assert.Empty(t, result.Errors)
+		assert.Equal(t, "from", result.Warnings[0].Field)
+	})
+
+	t.Run("no smtp config", func(t *testing.T) {
+		result := validateTemplate(baseTemplate(), templateID, form, nil, nil, nil)
+		assert.Empty(t, result.Errors)
+		assert.Len(t, result.Warnings, 1)
+	})
+
+	t.Run("layout and partials", func(t *testing.T) {
+		layout := models.EmailComponent{ID: primitive.NewObjectID(), EventID: eventID, Kind: models.EmailComponentLayout, Name: "Main", Body: "{{content}}{{> footer}}"}
+		footer := models.EmailComponent{ID: primitive.NewObjectID(), EventID: eventID, Kind: models.EmailComponentPartial, Name: "footer", Body: "Sent to ${email}"}
+
+		template := baseTemplate()
+		template.LayoutID = layout.ID
+
+		result := validateTemplate(template, templateID, form, smtpConfig, nil, []models.EmailComponent{layout, footer})
+		assert.Empty(t, result.Errors)
+
+		// Placeholders in the layout's partials are checked against the form too
+		footer.Body = "Sent to ${phone}"
+		result = validateTemplate(template, templateID, form, smtpConfig, nil, []models.EmailComponent{layout, footer})
+		assert.Equal(t, []templateIssue{{Field: "body", Message: "${phone} is not a field on form Application"}}, result.Errors)
+
+		result = validateTemplate(template, templateID, form, smtpConfig, nil, []models.EmailComponent{footer})
+		assert.Equal(t, "layoutID", result.Errors[0].Field)
+
+		result = validateTemplate(template, templateID, form, smtpConfig, nil, []models.EmailComponent{layout})
+		assert.Equal(t, []templateIssue{{Field: "body", Message: "partial footer does not exist"}}, result.Errors)
+	})
+}
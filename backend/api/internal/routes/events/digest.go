@@ -0,0 +1,95 @@
+package events
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getDigestSubscriptionHandler returns the authenticated organizer's digest settings for the event
+func getDigestSubscriptionHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, err := primitive.ObjectIDFromHex(c.Param("event_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		subscription, err := params.MongoService.GetDigestSubscription(c, eventID, authenticatedUser.ID)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusOK, models.DigestSubscription{EventID: eventID, UserID: authenticatedUser.ID, Frequency: models.DigestOff})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving digest settings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, subscription)
+	}
+}
+
+type updateDigestSubscriptionRequest struct {
+	Frequency models.DigestFrequency `json:"frequency" validate:"required,oneof=off hourly daily"`
+}
+
+// updateDigestSubscriptionHandler sets how often the authenticated organizer gets a digest for the event
+func updateDigestSubscriptionHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventID, err := primitive.ObjectIDFromHex(c.Param("event_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req updateDigestSubscriptionRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		subscription := models.DigestSubscription{
+			EventID:   eventID,
+			UserID:    authenticatedUser.ID,
+			Frequency: req.Frequency,
+		}
+		if _, err := params.MongoService.UpsertDigestSubscription(c, subscription); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update digest settings"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Digest settings updated successfully"})
+	}
+}
@@ -28,6 +28,12 @@ func RegisterRoutes(r *gin.RouterGroup, params *types.RouteParams) {
 	r.GET(":event_id/forms", middlewares.JWTAuthMiddleware(), getEventFormsHandler(params))
 	r.GET(":event_id/pipelines", middlewares.JWTAuthMiddleware(), getEventPipelinesHandler(params))
 	r.GET(":event_id/email_templates", middlewares.JWTAuthMiddleware(), getEventEmailTemplatesHandler(params))
+	r.GET(":event_id/email_campaigns", middlewares.JWTAuthMiddleware(), getEventEmailCampaignsHandler(params))
+	r.GET(":event_id/email_suppressions", middlewares.JWTAuthMiddleware(), getEventEmailSuppressionsHandler(params))
+	r.GET(":event_id/email_components", middlewares.JWTAuthMiddleware(), getEventEmailComponentsHandler(params))
+	r.GET(":event_id/selector_sources", middlewares.JWTAuthMiddleware(), getEventSelectorSourcesHandler(params))
+	r.GET(":event_id/digest", middlewares.JWTAuthMiddleware(), getDigestSubscriptionHandler(params))
+	r.PUT(":event_id/digest", middlewares.JWTAuthMiddleware(), updateDigestSubscriptionHandler(params))
 
 	// Register the secrets routes
 	secrets.RegisterRoutes(r.Group(":event_id/secrets"), params)
@@ -299,3 +305,124 @@ func getEventEmailTemplatesHandler(params *types.RouteParams) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"email_templates": emailTemplates})
 	}
 }
+
+func getEventEmailCampaignsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventParam := c.Param("event_id")
+		eventID, err := primitive.ObjectIDFromHex(eventParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		campaigns, err := params.MongoService.ListEmailCampaigns(c, bson.M{"eventID": eventID})
+		if err != nil {
+			log.Printf("Error retrieving event email campaigns: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving event email campaigns"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"email_campaigns": campaigns})
+	}
+}
+
+func getEventEmailSuppressionsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventParam := c.Param("event_id")
+		eventID, err := primitive.ObjectIDFromHex(eventParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		// Only the event's own list, global suppressions are not shared with organizers
+		suppressions, err := params.MongoService.ListEmailSuppressions(c, bson.M{"eventID": eventID})
+		if err != nil {
+			log.Printf("Error retrieving event email suppressions: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving event email suppressions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"email_suppressions": suppressions})
+	}
+}
+
+func getEventEmailComponentsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventParam := c.Param("event_id")
+		eventID, err := primitive.ObjectIDFromHex(eventParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		components, err := params.MongoService.ListEmailComponents(c, bson.M{"eventID": eventID})
+		if err != nil {
+			log.Printf("Error retrieving event email components: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving event email components"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"email_components": components})
+	}
+}
+
+func getEventSelectorSourcesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		eventParam := c.Param("event_id")
+		eventID, err := primitive.ObjectIDFromHex(eventParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event ID"})
+			return
+		}
+
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, eventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not allowed to modify this event"})
+			return
+		}
+
+		sources, err := params.MongoService.ListSelectorSources(c, bson.M{"eventID": eventID})
+		if err != nil {
+			log.Printf("Error retrieving event selector sources: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error retrieving event selector sources"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"selector_sources": sources})
+	}
+}
@@ -2,14 +2,12 @@ package forms
 
 import (
 	"api/internal/types"
-	"bufio"
-	"errors"
-	"fmt"
 	"net/http"
-	"shared/models"
-	"time"
+	"shared/selectors"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -17,121 +15,63 @@ func RegisterDefaultSelectorValues(r *gin.RouterGroup, params *types.RouteParams
 	r.GET("default_selector_values/:source_name", valuesFromSource(params))
 }
 
-func getMLHSchools() ([]string, error) {
-	mlhSchoolUrl := "https://raw.githubusercontent.com/MLH/mlh-policies/main/schools.csv"
-
-	// Make HTTP GET request
-	resp, err := http.Get(mlhSchoolUrl)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	// Check if the request was successful
-	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("request failed")
-	}
-
-	// Read the response body
-	scanner := bufio.NewScanner(resp.Body)
-	schools := []string{}
-	for scanner.Scan() {
-		line := scanner.Text()
-		schools = append(schools, line)
-	}
-
-	// Get rid of first element (header row)
-	schools = schools[1:]
-
-	return schools, nil
-}
-
+// valuesFromSource returns a selector source's options as a JSON array, with the number of matches in the
+// X-Total-Count header. Built-ins are available to every form, ?event_id= is needed for an event's own sources.
+// ?search= filters the options and ?offset= and ?limit= page through them, without a limit every match is returned.
+// Remote sources are only ever read from the cache, the event listener keeps it fresh.
 func valuesFromSource(params *types.RouteParams) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		sourceName := c.Param("source_name")
 
-		// Allowed sources
-		allowedSources := []string{"mlh-schools"}
-		allowed := false
-		for _, allowedSource := range allowedSources {
-			if sourceName == allowedSource {
-				allowed = true
-				break
+		var options []string
+		if builtin, ok := selectors.Builtin(sourceName); ok && builtin.MaxAge() == 0 {
+			options, _ = builtin.Fetch(c)
+		} else if ok {
+			source, err := params.MongoService.GetSourceByName(c, sourceName)
+			if err != nil && err != mongo.ErrNoDocuments {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
+				return
 			}
-		}
 
-		if !allowed {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source name"})
-			return
-		}
+			if source == nil || len(source.Options) == 0 {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Source is still loading, try again shortly"})
+				return
+			}
+			options = source.Options
+		} else {
+			eventID, err := primitive.ObjectIDFromHex(c.Query("event_id"))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source name"})
+				return
+			}
 
-		source, err := params.MongoService.GetSourceByName(c, sourceName)
-		refreshSource := false
-		addSource := false
-		if err != nil {
+			source, err := params.MongoService.GetEventSelectorSource(c, eventID, sourceName)
 			if err == mongo.ErrNoDocuments {
-				addSource = true
-			} else {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+				return
+			} else if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
 				return
 			}
-		}
-
-		if source == nil {
-			refreshSource = true
-		}
 
-		// check if source updated in last 24h
-		if !addSource {
-			lastUpdated := source.LastUpdated
-			now := time.Now()
-			diff := now.Sub(lastUpdated)
-			if diff.Hours() > 24 {
-				refreshSource = true
+			if len(source.Options) == 0 {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Source is still loading, try again shortly"})
+				return
 			}
+			options = source.Options
 		}
 
-		// We haven't cached the source yet, so we need to fetch it from the external source
-		// This probably should be abstracted out into a setup script or something, but for now it's
-		// fine to just do it here
-		if addSource || refreshSource {
-			switch sourceName {
-			case "mlh-schools":
-				schools, err := getMLHSchools()
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
-					return
-				}
-
-				// Create the source
-				newSource := models.SelectorSource{
-					SourceName:  sourceName,
-					LastUpdated: time.Now(),
-					Options:     schools,
-				}
-
-				if addSource {
-					_, err = params.MongoService.CreateSource(c, newSource)
-					if err != nil {
-						fmt.Println(err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
-						return
-					}
-				} else if refreshSource {
-					_, err = params.MongoService.UpdateSource(c, newSource, source.ID)
-					if err != nil {
-						fmt.Println(err)
-						c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
-						return
-					}
-				}
-
-				c.JSON(http.StatusOK, schools)
-			default:
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve source"})
-			}
+		offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "0"))
+		if offset < 0 {
+			offset = 0
+		}
+		if limit < 0 {
+			limit = 0
 		}
 
-		c.JSON(http.StatusOK, source.Options)
+		matches := selectors.Search(options, c.Query("search"))
+		c.Header("X-Total-Count", strconv.Itoa(len(matches)))
+		c.JSON(http.StatusOK, selectors.Page(matches, offset, limit))
 	}
 }
@@ -0,0 +1,260 @@
+package files
+
+import (
+	"api/internal/middlewares"
+	"api/internal/types"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"shared/models"
+	"shared/mongodb"
+	"shared/storage"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxUploadSize caps every upload regardless of the field's own limit
+	maxUploadSize = 25 << 20
+
+	// defaultMaxFileSize is used for file fields without a MaxFileSize
+	defaultMaxFileSize = 10 << 20
+)
+
+func RegisterFormFileRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	r.POST("", middlewares.JWTAuthMiddleware(), uploadFileHandler(params))
+	r.GET(":file_id", middlewares.JWTAuthMiddleware(), downloadFileHandler(params))
+}
+
+// uploadFileHandler stores a file for one of the form's file fields, the returned ID is then submitted as the field's value
+func uploadFileHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, false)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		// Organizers can upload on behalf of applicants at any time, applicants only while the form accepts submissions
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form) {
+			if form.Status != "published" || (!form.CloseSubmissionsAt.IsZero() && form.CloseSubmissionsAt.Before(time.Now())) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting submissions"})
+				return
+			}
+
+			if form.IsRestricted {
+				if grant, restrictMessage := mongodb.CheckFormAccess(c, params.MongoService, form); grant == nil {
+					c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
+					return
+				}
+			}
+		}
+
+		// Leave some room for the rest of the multipart body
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize+(1<<20))
+
+		fieldKey := c.PostForm("fieldKey")
+		var field *models.FormField
+		for i, attr := range form.Attrs {
+			if attr.Key == fieldKey && attr.Type == models.FieldTypeFile {
+				field = &form.Attrs[i]
+			}
+		}
+		if field == nil || (field.IsInternal && !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form has no file field " + fieldKey})
+			return
+		}
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "A file is required, it may be larger than the upload limit"})
+			return
+		}
+
+		limit := field.AdditionalValidation.MaxFileSize
+		if limit <= 0 || limit > maxUploadSize {
+			limit = defaultMaxFileSize
+			if field.AdditionalValidation.MaxFileSize > maxUploadSize {
+				limit = maxUploadSize
+			}
+		}
+		if fileHeader.Size > limit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("File must be smaller than %.1f MB", float64(limit)/(1<<20))})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+			return
+		}
+		defer file.Close()
+
+		head := make([]byte, 512)
+		n, err := io.ReadFull(file, head)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+			return
+		}
+		head = head[:n]
+
+		contentType := detectContentType(head, fileHeader.Filename)
+		if !mimeTypeAllowed(contentType, field.AdditionalValidation.AllowedMimeTypes) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s files are not allowed, allowed types: %s", contentType, strings.Join(field.AdditionalValidation.AllowedMimeTypes, ", "))})
+			return
+		}
+
+		upload := models.FileUpload{
+			ID:          primitive.NewObjectID(),
+			FormID:      formID,
+			EventID:     form.EventID,
+			FieldKey:    field.Key,
+			UserID:      authenticatedUser.ID,
+			FileName:    filepath.Base(fileHeader.Filename),
+			ContentType: contentType,
+			Size:        fileHeader.Size,
+		}
+		upload.StorageKey = fmt.Sprintf("forms/%s/%s", formID.Hex(), upload.ID.Hex())
+
+		if err := params.FileStorage.Put(c, upload.StorageKey, io.MultiReader(bytes.NewReader(head), file), fileHeader.Size, contentType); err != nil {
+			log.Printf("Failed to store upload: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+			return
+		}
+
+		if _, err := params.MongoService.CreateFileUpload(c, upload); err != nil {
+			params.FileStorage.Delete(c, upload.StorageKey)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store file"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": upload.ID, "fileName": upload.FileName, "size": upload.Size, "contentType": upload.ContentType})
+	}
+}
+
+// downloadFileHandler streams an uploaded file to the form's organizers, the applicant who uploaded it or the
+// reviewers assigned its response
+func downloadFileHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		fileID, err := primitive.ObjectIDFromHex(c.Param("file_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file ID"})
+			return
+		}
+
+		upload, err := params.MongoService.GetFileUpload(c, fileID)
+		if err != nil || upload.FormID != formID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return
+		}
+
+		if upload.UserID != authenticatedUser.ID && !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, nil) &&
+			!canReviewerDownload(c, params, upload, authenticatedUser) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this file"})
+			return
+		}
+
+		file, err := params.FileStorage.Get(c, upload.StorageKey)
+		if err == storage.ErrFileNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "File not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read file"})
+			return
+		}
+		defer file.Close()
+
+		// Always downloaded rather than displayed so uploaded HTML or SVG can't run on our origin
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": upload.FileName}))
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.DataFromReader(http.StatusOK, upload.Size, upload.ContentType, file, nil)
+	}
+}
+
+// canReviewerDownload checks the user was assigned to review the response the file is attached to, and that the
+// file's field isn't hidden from reviewers, eg: in blind mode
+func canReviewerDownload(c *gin.Context, params *types.RouteParams, upload *models.FileUpload, user *models.User) bool {
+	if upload.ResponseID.IsZero() {
+		return false
+	}
+
+	if _, err := params.MongoService.GetReview(c, upload.ResponseID, user.ID); err != nil {
+		return false
+	}
+
+	form, err := params.MongoService.GetForm(c, upload.FormID, true)
+	if err != nil {
+		return false
+	}
+
+	rubric, err := params.MongoService.GetReviewRubric(c, upload.FormID)
+	if err != nil {
+		return false
+	}
+
+	return !rubric.HiddenFields(form, false)[upload.FieldKey]
+}
+
+// detectContentType sniffs the file's type, falling back to its extension for formats that sniff
+// as generic containers like .docx (zip) or .csv (text)
+func detectContentType(head []byte, fileName string) string {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+
+	switch sniffed {
+	case "application/octet-stream", "application/zip", "text/plain":
+		if byExtension := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName))); byExtension != "" {
+			if mediaType, _, err := mime.ParseMediaType(byExtension); err == nil {
+				return mediaType
+			}
+		}
+	}
+
+	return sniffed
+}
+
+// mimeTypeAllowed checks the type against the field's allowed types, which can end in /* to allow a whole family
+func mimeTypeAllowed(contentType string, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == contentType || (strings.HasSuffix(pattern, "/*") && strings.HasPrefix(contentType, strings.TrimSuffix(pattern, "*"))) {
+			return true
+		}
+	}
+
+	return false
+}
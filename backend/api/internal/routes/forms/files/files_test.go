@@ -0,0 +1,104 @@
+package files
+
+import (
+	"api/internal/types"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"shared/models"
+	"shared/mongodb"
+	"shared/storage"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDetectContentType(t *testing.T) {
+	assert.Equal(t, "application/pdf", detectContentType([]byte("%PDF-1.4 ..."), "resume.pdf"))
+	assert.Equal(t, "image/png", detectContentType([]byte("\x89PNG\r\n\x1a\n"), "photo.jpg"))
+
+	// Office documents are zip files, so the extension decides
+	assert.Equal(t, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", detectContentType([]byte("PK\x03\x04"), "Resume.DOCX"))
+
+	// HTML is never trusted to be what the extension says
+	assert.Equal(t, "text/html", detectContentType([]byte("<html><script>"), "resume.pdf"))
+}
+
+func TestMimeTypeAllowed(t *testing.T) {
+	assert.True(t, mimeTypeAllowed("application/pdf", nil))
+	assert.True(t, mimeTypeAllowed("application/pdf", []string{"image/*", "application/pdf"}))
+	assert.True(t, mimeTypeAllowed("image/png", []string{"image/*"}))
+	assert.False(t, mimeTypeAllowed("text/html", []string{"image/*", "application/pdf"}))
+}
+
+// downloadStore has one upload attached to a response, and the reviewers assigned that response
+type downloadStore struct {
+	*mongodb.MockMongoService
+	form      models.FormStructure
+	upload    models.FileUpload
+	rubric    models.ReviewRubric
+	reviewers map[primitive.ObjectID]bool
+}
+
+func (s *downloadStore) GetFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*models.FileUpload, error) {
+	return &s.upload, nil
+}
+
+func (s *downloadStore) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
+	return &s.form, nil
+}
+
+func (s *downloadStore) GetEvent(ctx *gin.Context, eventID primitive.ObjectID) (*models.Event, error) {
+	return &models.Event{ID: eventID}, nil
+}
+
+func (s *downloadStore) GetReview(ctx context.Context, responseID primitive.ObjectID, reviewerID primitive.ObjectID) (*models.Review, error) {
+	if responseID != s.upload.ResponseID || !s.reviewers[reviewerID] {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &models.Review{ResponseID: responseID, ReviewerID: reviewerID}, nil
+}
+
+func (s *downloadStore) GetReviewRubric(ctx context.Context, formID primitive.ObjectID) (*models.ReviewRubric, error) {
+	return &s.rubric, nil
+}
+
+func TestDownloadFileByReviewer(t *testing.T) {
+	fileStorage, err := storage.NewLocalStorage(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, fileStorage.Put(context.Background(), "cv", strings.NewReader("%PDF-1.4"), 8, "application/pdf"))
+
+	reviewer, stranger := primitive.NewObjectID(), primitive.NewObjectID()
+	formID := primitive.NewObjectID()
+	store := &downloadStore{
+		MockMongoService: mongodb.NewMockMongoService(),
+		form:             models.FormStructure{ID: formID, Attrs: []models.FormField{{Key: "cv", Type: models.FieldTypeFile}}},
+		upload:           models.FileUpload{ID: primitive.NewObjectID(), FormID: formID, FieldKey: "cv", UserID: primitive.NewObjectID(), ResponseID: primitive.NewObjectID(), FileName: "cv.pdf", ContentType: "application/pdf", Size: 8, StorageKey: "cv"},
+		reviewers:        map[primitive.ObjectID]bool{reviewer: true},
+	}
+	params := &types.RouteParams{MongoService: store, FileStorage: fileStorage}
+
+	download := func(userID primitive.ObjectID) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Params = gin.Params{{Key: "form_id", Value: formID.Hex()}, {Key: "file_id", Value: store.upload.ID.Hex()}}
+		c.Set("user", &models.User{ID: userID})
+		downloadFileHandler(params)(c)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, download(reviewer))
+	assert.Equal(t, http.StatusForbidden, download(stranger))
+
+	// Blind mode hides uploads from reviewers unless the organizer opts them in
+	store.rubric.BlindMode = true
+	assert.Equal(t, http.StatusForbidden, download(reviewer))
+	store.rubric.BlindShowFiles = true
+	assert.Equal(t, http.StatusOK, download(reviewer))
+}
@@ -2,7 +2,9 @@ package forms
 
 import (
 	"api/internal/middlewares"
+	"api/internal/routes/forms/files"
 	"api/internal/routes/forms/responses"
+	"api/internal/routes/forms/reviews"
 	"api/internal/types"
 	"log"
 	"net/http"
@@ -16,13 +18,27 @@ import (
 )
 
 func RegisterRoutes(r *gin.RouterGroup, params *types.RouteParams) {
-	r.GET(":form_id", middlewares.JWTAuthMiddleware(), getFormDataHandler(params))
+	// Optionally authenticated so forms allowing anonymous responses can be viewed without an account
+	r.GET(":form_id", getFormDataHandler(params))
 	r.POST("", middlewares.JWTAuthMiddleware(), createFormHandler(params))
 	r.PUT(":form_id", middlewares.JWTAuthMiddleware(), updateFormHandler(params))
 	r.DELETE(":form_id", middlewares.JWTAuthMiddleware(), deleteFormHandler(params))
 
 	responsesGroup := r.Group(":form_id/responses")
 	responses.RegisterFormResponsesRoutes(responsesGroup, params)
+
+	reviewsGroup := r.Group(":form_id/reviews")
+	reviews.RegisterFormReviewRoutes(reviewsGroup, params)
+
+	filesGroup := r.Group(":form_id/files")
+	files.RegisterFormFileRoutes(filesGroup, params)
+
+	r.GET(":form_id/versions", middlewares.JWTAuthMiddleware(), listFormVersionsHandler(params))
+	r.GET(":form_id/versions/:version", middlewares.JWTAuthMiddleware(), getFormVersionHandler(params))
+
+	r.GET(":form_id/invites", middlewares.JWTAuthMiddleware(), listFormInvitesHandler(params))
+	r.POST(":form_id/invites", middlewares.JWTAuthMiddleware(), createFormInviteHandler(params))
+	r.POST(":form_id/invites/:invite_id/revoke", middlewares.JWTAuthMiddleware(), revokeFormInviteHandler(params))
 }
 
 func getFormDataHandler(params *types.RouteParams) gin.HandlerFunc {
@@ -44,29 +60,47 @@ func getFormDataHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		if form.Status != "published" {
-			authenticatedUser, ok := utils.GetUserFromContext(c, true)
-			if !ok {
+		authenticatedUser, ok := utils.GetUserFromContext(c, false)
+		if !ok {
+			if form.Status != "published" || !form.AllowAnonymous {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
 				return
 			}
 
-			if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form) {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this form"})
+			form.StripSecrets()
+			form.StripInternalFields()
+			c.JSON(http.StatusOK, gin.H{"form": form, "prefill": utils.PrefillFormData(form, nil, c.Request.URL.Query())})
+			return
+		}
+
+		isOrganizer := mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form)
+		if form.Status != "published" && !isOrganizer {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this form"})
+			return
+		}
+
+		// Check the authenticated user is let in by one of the form's access rules, organizers can always view it
+		if !isOrganizer {
+			if grant, restrictMessage := mongodb.CheckFormAccess(c, params.MongoService, form); grant == nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
 				return
 			}
 		}
 
-		// Check if the authenticated user's emails are in the form's whitelist, if it exists
-		if form.IsRestricted {
-			allowed, restrictMessage := mongodb.IsUserEmailInWhitelist(c, form.AllowedSubmitters)
-			if !allowed {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
+		// The JWT only has the user's name and email, the rest of the profile is loaded when the form needs it
+		profile := authenticatedUser
+		if utils.FormUsesProfile(form) {
+			if profile, err = params.MongoService.FindUserByID(c, authenticatedUser.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load your profile"})
 				return
 			}
 		}
 
 		form.StripSecrets()
-		c.JSON(http.StatusOK, gin.H{"form": form})
+		if !isOrganizer {
+			form.StripInternalFields()
+		}
+		c.JSON(http.StatusOK, gin.H{"form": form, "prefill": utils.PrefillFormData(form, profile, c.Request.URL.Query())})
 	}
 }
 
@@ -83,6 +117,11 @@ func createFormHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
+		if errors := utils.ValidateFormStructure(req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
 		authenticatedUser, ok := utils.GetUserFromContext(c, true)
 		if !ok {
 			return
@@ -108,6 +147,24 @@ func createFormHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
+		if !checkAnonymousConfirmationTemplate(c, params, req, req.EventID) {
+			return
+		}
+
+		if !checkSelectorSources(c, params, req, req.EventID) {
+			return
+		}
+
+		// Forms created published get their first version straight away, like publishing a draft does
+		req.ID = primitive.NewObjectID()
+		req.Version, req.VersionID = 0, primitive.NilObjectID
+		if req.Status == "published" {
+			if err := saveFormVersion(c, params, &req, req.ID, req.EventID, authenticatedUser.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create form"})
+				return
+			}
+		}
+
 		formID, err := params.MongoService.CreateForm(c, req)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create form"})
@@ -118,6 +175,27 @@ func createFormHandler(params *types.RouteParams) gin.HandlerFunc {
 	}
 }
 
+// saveFormVersion saves the form's schema as its next version and points the form at it
+func saveFormVersion(c *gin.Context, params *types.RouteParams, form *models.FormStructure, formID primitive.ObjectID, eventID primitive.ObjectID, createdBy primitive.ObjectID) error {
+	version := models.FormVersion{
+		FormID:    formID,
+		EventID:   eventID,
+		Version:   form.Version + 1,
+		Attrs:     form.Attrs,
+		Sections:  form.Sections,
+		CreatedBy: createdBy,
+	}
+
+	result, err := params.MongoService.CreateFormVersion(c, version)
+	if err != nil {
+		return err
+	}
+
+	form.Version = version.Version
+	form.VersionID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
 func deleteFormHandler(params *types.RouteParams) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authenticatedUser, ok := utils.GetUserFromContext(c, true)
@@ -166,6 +244,11 @@ func updateFormHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
+		if errors := utils.ValidateFormStructure(req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
 		authenticatedUser, ok := utils.GetUserFromContext(c, true)
 		if !ok {
 			return
@@ -188,6 +271,30 @@ func updateFormHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
+		existing, err := params.MongoService.GetForm(c, formID, false)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+			return
+		}
+
+		if !checkAnonymousConfirmationTemplate(c, params, req, existing.EventID) {
+			return
+		}
+
+		if !checkSelectorSources(c, params, req, existing.EventID) {
+			return
+		}
+
+		// Published forms get a new version whenever their schema changes so earlier responses keep their meaning
+		req.Version = existing.Version
+		req.VersionID = existing.VersionID
+		if req.Status == "published" && (existing.VersionID.IsZero() || !utils.FormSchemaEqual(*existing, req)) {
+			if err := saveFormVersion(c, params, &req, formID, existing.EventID, authenticatedUser.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update form"})
+				return
+			}
+		}
+
 		_, err = params.MongoService.UpdateForm(c, req, formID)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update form"})
@@ -198,3 +305,19 @@ func updateFormHandler(params *types.RouteParams) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "Form updated successfully"})
 	}
 }
+
+// checkAnonymousConfirmationTemplate checks a form allowing anonymous responses confirms them with one of its event's
+// email templates, writing the error response if not
+func checkAnonymousConfirmationTemplate(c *gin.Context, params *types.RouteParams, form models.FormStructure, eventID primitive.ObjectID) bool {
+	if !form.AllowAnonymous {
+		return true
+	}
+
+	template, err := params.MongoService.GetEmailTemplate(c, form.AnonymousConfirmationTemplateID)
+	if err != nil || template.EventID != eventID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Anonymous confirmation email template does not exist on this event"})
+		return false
+	}
+
+	return true
+}
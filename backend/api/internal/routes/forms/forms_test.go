@@ -0,0 +1,79 @@
+package forms
+
+import (
+	"api/internal/types"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"shared/models"
+	"shared/mongodb"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// formStore records the forms and versions created for an event the user organizes
+type formStore struct {
+	*mongodb.MockMongoService
+	organizerID primitive.ObjectID
+	created     []models.FormStructure
+	versions    []models.FormVersion
+}
+
+func (s *formStore) GetEvent(ctx *gin.Context, eventID primitive.ObjectID) (*models.Event, error) {
+	return &models.Event{ID: eventID, OrganizerIDs: []primitive.ObjectID{s.organizerID}}, nil
+}
+
+func (s *formStore) CreateForm(ctx context.Context, form models.FormStructure) (*mongo.InsertOneResult, error) {
+	s.created = append(s.created, form)
+	return &mongo.InsertOneResult{InsertedID: form.ID}, nil
+}
+
+func (s *formStore) CreateFormVersion(ctx context.Context, version models.FormVersion) (*mongo.InsertOneResult, error) {
+	s.versions = append(s.versions, version)
+	return &mongo.InsertOneResult{InsertedID: primitive.NewObjectID()}, nil
+}
+
+func TestCreateFormVersions(t *testing.T) {
+	for _, status := range []string{"published", "draft"} {
+		t.Run(status, func(t *testing.T) {
+			store := &formStore{MockMongoService: mongodb.NewMockMongoService(), organizerID: primitive.NewObjectID()}
+			body, err := json.Marshal(models.FormStructure{
+				EventID: primitive.NewObjectID(),
+				Name:    "Application",
+				Status:  status,
+				Attrs:   []models.FormField{{Key: uuid.NewString(), Type: models.FieldTypeText, Question: "Name"}},
+			})
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Set("user", &models.User{ID: store.organizerID})
+			createFormHandler(&types.RouteParams{MongoService: store})(c)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			require.Len(t, store.created, 1)
+
+			// Only a form created published gets its first version, which it points at
+			created := store.created[0]
+			if status == "published" {
+				require.Len(t, store.versions, 1)
+				assert.Equal(t, created.ID, store.versions[0].FormID)
+				assert.Equal(t, 1, store.versions[0].Version)
+				assert.Equal(t, 1, created.Version)
+				assert.False(t, created.VersionID.IsZero())
+			} else {
+				assert.Empty(t, store.versions)
+				assert.True(t, created.VersionID.IsZero())
+			}
+		})
+	}
+}
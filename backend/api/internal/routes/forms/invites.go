@@ -0,0 +1,140 @@
+package forms
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// listFormInvitesHandler lists a form's invites along with the token to put in each invite link
+func listFormInvitesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this form's invites"})
+			return
+		}
+
+		invites, err := params.MongoService.ListFormInvites(c, formID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list form invites"})
+			return
+		}
+
+		for i := range invites {
+			invites[i].Token = utils.GenerateFormInviteToken(invites[i].ID)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"invites": invites})
+	}
+}
+
+func createFormInviteHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		var req models.FormInvite
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to create invites for this form"})
+			return
+		}
+
+		if !form.IsRestricted {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invites can only be created for restricted forms"})
+			return
+		}
+
+		req.ID = primitive.NilObjectID
+		req.FormID = formID
+		req.EventID = form.EventID
+		req.CreatedBy = authenticatedUser.ID
+		result, err := params.MongoService.CreateFormInvite(c, req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create form invite"})
+			return
+		}
+
+		inviteID := result.InsertedID.(primitive.ObjectID)
+		c.JSON(http.StatusOK, gin.H{"id": inviteID, "token": utils.GenerateFormInviteToken(inviteID)})
+	}
+}
+
+func revokeFormInviteHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		inviteID, err := primitive.ObjectIDFromHex(c.Param("invite_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invite ID"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to revoke this form's invites"})
+			return
+		}
+
+		invite, err := params.MongoService.GetFormInvite(c, inviteID)
+		if err != nil || invite.FormID != formID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invite not found"})
+			return
+		}
+
+		if _, err := params.MongoService.RevokeFormInvite(c, inviteID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke form invite"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Invite revoked successfully"})
+	}
+}
@@ -0,0 +1,316 @@
+package responses
+
+import (
+	"api/internal/types"
+	"fmt"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// analyticsMaxOptions limits how many of a field's most common answers are counted, for fields like schools
+	analyticsMaxOptions = 100
+
+	analyticsHistogramBuckets = 10
+)
+
+// formAnalytics summarises a form's responses
+type formAnalytics struct {
+	Total               int64            `json:"total"`
+	SubmissionsOverTime []analyticsCount `json:"submissionsOverTime"`
+	Fields              []fieldAnalytics `json:"fields"`
+}
+
+// fieldAnalytics summarises the answers to one field. Options are counted for select and checkbox fields,
+// number fields get a histogram and their min, max and mean.
+type fieldAnalytics struct {
+	Key            string               `json:"key"`
+	Question       string               `json:"question"`
+	Type           models.FormFieldType `json:"type"`
+	Required       bool                 `json:"required"`
+	Answered       int64                `json:"answered"`
+	CompletionRate float64              `json:"completionRate"`
+
+	Options        []analyticsCount `json:"options,omitempty"`
+	OptionsLimited bool             `json:"optionsLimited,omitempty"` // only the most common answers are included
+
+	Histogram []analyticsBucket `json:"histogram,omitempty"`
+	Min       *float64          `json:"min,omitempty"`
+	Max       *float64          `json:"max,omitempty"`
+	Mean      *float64          `json:"mean,omitempty"`
+}
+
+type analyticsCount struct {
+	Value interface{} `json:"value"`
+	Count int64       `json:"count"`
+}
+
+// analyticsBucket counts the answers from Min up to Max, Max is only included in the last bucket
+type analyticsBucket struct {
+	Min   interface{} `json:"min"`
+	Max   interface{} `json:"max"`
+	Count int64       `json:"count"`
+}
+
+// formAnalyticsHandler computes the distribution of each field's answers over the responses matching the
+// listing's ?filter[] and ?search=. Submissions are counted per day in ?tz=, or the event's timezone.
+func formAnalyticsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+			return
+		}
+
+		query, err := parseResponseQuery(form, c.Request.URL.Query(), false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		location, err := responseLocation(c, params, form)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+
+		filter := bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}}
+		if len(query.conditions) > 0 {
+			filter["$and"] = query.conditions
+		}
+
+		results, err := params.MongoService.AggregateResponses(c, analyticsPipeline(form, filter, location))
+		if err != nil || len(results) == 0 {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute form analytics"})
+			return
+		}
+
+		c.JSON(http.StatusOK, parseAnalytics(form, results[0]))
+	}
+}
+
+// isOptionField reports whether the field's answers are counted by option
+func isOptionField(field models.FormField) bool {
+	switch field.Type {
+	case models.FieldTypeSelect, models.FieldTypeRadio, models.FieldTypeCustomSelect, models.FieldTypeCheckbox,
+		models.FieldTypeMultiSelect, models.FieldTypeCustomMultiSelect:
+		return true
+	}
+	return false
+}
+
+func isMultiOptionField(field models.FormField) bool {
+	return field.Type == models.FieldTypeMultiSelect || field.Type == models.FieldTypeCustomMultiSelect
+}
+
+// analyticsFacet names a field's facets by position, field keys can't be used as they may contain dots
+func analyticsFacet(index int, facet string) string {
+	return fmt.Sprintf("field%d_%s", index, facet)
+}
+
+// analyticsPipeline computes every statistic in a single pass over the matching responses with a $facet per statistic
+func analyticsPipeline(form *models.FormStructure, filter bson.M, location *time.Location) mongo.Pipeline {
+	facets := bson.M{
+		"total": bson.A{bson.M{"$count": "count"}},
+		"overTime": bson.A{
+			bson.M{"$group": bson.M{
+				"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$createdAt", "timezone": location.String()}},
+				"count": bson.M{"$sum": 1},
+			}},
+			bson.M{"$sort": bson.M{"_id": 1}},
+		},
+	}
+
+	answered := bson.M{"_id": nil}
+	for i, field := range form.Attrs {
+		key := "data." + field.Key
+		path := "$" + key
+
+		// Unanswered optional fields are missing, null, empty text or an empty list
+		answered[analyticsFacet(i, "answered")] = bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$and": bson.A{
+				bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{path, nil}}, nil}},
+				bson.M{"$ne": bson.A{path, ""}},
+				bson.M{"$ne": bson.A{path, bson.A{}}},
+			}},
+			1,
+			0,
+		}}}
+
+		switch {
+		case isOptionField(field):
+			options := bson.A{bson.M{"$match": bson.M{key: bson.M{"$exists": true, "$nin": bson.A{nil, ""}}}}}
+			if isMultiOptionField(field) {
+				options = append(options, bson.M{"$unwind": path})
+			}
+			options = append(options,
+				bson.M{"$group": bson.M{"_id": path, "count": bson.M{"$sum": 1}}},
+				bson.D{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+				bson.M{"$limit": analyticsMaxOptions + 1},
+			)
+			facets[analyticsFacet(i, "options")] = options
+
+		case field.Type == models.FieldTypeNumber:
+			numbers := bson.M{"$match": bson.M{key: bson.M{"$type": "number"}}}
+			facets[analyticsFacet(i, "histogram")] = bson.A{
+				numbers,
+				bson.M{"$bucketAuto": bson.M{"groupBy": path, "buckets": analyticsHistogramBuckets}},
+			}
+			facets[analyticsFacet(i, "stats")] = bson.A{
+				numbers,
+				bson.M{"$group": bson.M{"_id": nil, "min": bson.M{"$min": path}, "max": bson.M{"$max": path}, "mean": bson.M{"$avg": path}}},
+			}
+		}
+	}
+	facets["answered"] = bson.A{bson.M{"$group": answered}}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$facet", Value: facets}},
+	}
+}
+
+// parseAnalytics reads the result of analyticsPipeline
+func parseAnalytics(form *models.FormStructure, result bson.M) formAnalytics {
+	analytics := formAnalytics{SubmissionsOverTime: []analyticsCount{}, Fields: []fieldAnalytics{}}
+
+	if total := facetDocuments(result, "total"); len(total) > 0 {
+		analytics.Total = toInt64(total[0]["count"])
+	}
+
+	for _, day := range facetDocuments(result, "overTime") {
+		analytics.SubmissionsOverTime = append(analytics.SubmissionsOverTime, analyticsCount{Value: day["_id"], Count: toInt64(day["count"])})
+	}
+
+	var answered bson.M
+	if documents := facetDocuments(result, "answered"); len(documents) > 0 {
+		answered = documents[0]
+	}
+
+	for i, field := range form.Attrs {
+		summary := fieldAnalytics{
+			Key:      field.Key,
+			Question: field.Question,
+			Type:     field.Type,
+			Required: field.Required,
+			Answered: toInt64(answered[analyticsFacet(i, "answered")]),
+		}
+		if analytics.Total > 0 {
+			summary.CompletionRate = float64(summary.Answered) / float64(analytics.Total)
+		}
+
+		if isOptionField(field) {
+			summary.Options = []analyticsCount{}
+			counted := map[string]bool{}
+			for _, option := range facetDocuments(result, analyticsFacet(i, "options")) {
+				summary.Options = append(summary.Options, analyticsCount{Value: option["_id"], Count: toInt64(option["count"])})
+				counted[fmt.Sprint(option["_id"])] = true
+			}
+
+			if len(summary.Options) > analyticsMaxOptions {
+				summary.Options = summary.Options[:analyticsMaxOptions]
+				summary.OptionsLimited = true
+			} else {
+				// Options nobody picked are still part of the distribution
+				for _, option := range field.Options {
+					if !counted[option] {
+						summary.Options = append(summary.Options, analyticsCount{Value: option, Count: 0})
+					}
+				}
+			}
+		}
+
+		if field.Type == models.FieldTypeNumber {
+			summary.Histogram = []analyticsBucket{}
+			for _, bucket := range facetDocuments(result, analyticsFacet(i, "histogram")) {
+				bounds := asDocument(bucket["_id"])
+				summary.Histogram = append(summary.Histogram, analyticsBucket{Min: bounds["min"], Max: bounds["max"], Count: toInt64(bucket["count"])})
+			}
+
+			if stats := facetDocuments(result, analyticsFacet(i, "stats")); len(stats) > 0 {
+				summary.Min = toFloat64(stats[0]["min"])
+				summary.Max = toFloat64(stats[0]["max"])
+				summary.Mean = toFloat64(stats[0]["mean"])
+			}
+		}
+
+		analytics.Fields = append(analytics.Fields, summary)
+	}
+
+	return analytics
+}
+
+// facetDocuments returns the documents a facet produced
+func facetDocuments(result bson.M, facet string) []bson.M {
+	items, _ := result[facet].(bson.A)
+
+	documents := make([]bson.M, 0, len(items))
+	for _, item := range items {
+		if document := asDocument(item); document != nil {
+			documents = append(documents, document)
+		}
+	}
+	return documents
+}
+
+// asDocument reads an embedded document however the driver decoded it
+func asDocument(value interface{}) bson.M {
+	switch value := value.(type) {
+	case bson.M:
+		return value
+	case bson.D:
+		return value.Map()
+	}
+	return nil
+}
+
+func toInt64(value interface{}) int64 {
+	switch value := value.(type) {
+	case int32:
+		return int64(value)
+	case int64:
+		return value
+	case float64:
+		return int64(value)
+	}
+	return 0
+}
+
+func toFloat64(value interface{}) *float64 {
+	var number float64
+	switch value := value.(type) {
+	case int32:
+		number = float64(value)
+	case int64:
+		number = float64(value)
+	case float64:
+		number = value
+	default:
+		return nil
+	}
+	return &number
+}
@@ -0,0 +1,74 @@
+package responses
+
+import (
+	"fmt"
+	"shared/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var analyticsForm = &models.FormStructure{Attrs: []models.FormField{
+	{Key: "name", Question: "Name", Type: models.FieldTypeText, Required: true},
+	{Key: "shirt", Question: "Shirt", Type: models.FieldTypeSelect, Options: []string{"S", "M", "L"}},
+	{Key: "age", Question: "Age", Type: models.FieldTypeNumber},
+	{Key: "school", Question: "School", Type: models.FieldTypeCustomSelect},
+}}
+
+func TestAnalyticsPipeline(t *testing.T) {
+	pipeline := analyticsPipeline(analyticsForm, bson.M{}, time.UTC)
+	require.Len(t, pipeline, 2)
+
+	facets, ok := pipeline[1][0].Value.(bson.M)
+	require.True(t, ok)
+	for _, facet := range []string{"total", "overTime", "answered", "field1_options", "field2_histogram", "field2_stats", "field3_options"} {
+		assert.Contains(t, facets, facet)
+	}
+	assert.NotContains(t, facets, "field0_options")
+}
+
+func TestParseAnalytics(t *testing.T) {
+	schools := bson.A{}
+	for i := 0; i <= analyticsMaxOptions; i++ {
+		schools = append(schools, bson.M{"_id": fmt.Sprintf("School %d", i), "count": int32(1)})
+	}
+
+	analytics := parseAnalytics(analyticsForm, bson.M{
+		"total":    bson.A{bson.M{"count": int32(4)}},
+		"overTime": bson.A{bson.M{"_id": "2026-03-01", "count": int32(4)}},
+		"answered": bson.A{bson.M{"_id": nil, "field0_answered": int32(4), "field1_answered": int32(3), "field2_answered": int32(2)}},
+		"field1_options": bson.A{
+			bson.M{"_id": "M", "count": int32(2)},
+			bson.D{{Key: "_id", Value: "S"}, {Key: "count", Value: int32(1)}},
+		},
+		"field2_histogram": bson.A{bson.M{"_id": bson.M{"min": 18.0, "max": 25.0}, "count": int32(2)}},
+		"field2_stats":     bson.A{bson.M{"_id": nil, "min": int32(18), "max": 25.0, "mean": 21.5}},
+		"field3_options":   schools,
+	})
+
+	assert.Equal(t, int64(4), analytics.Total)
+	assert.Equal(t, []analyticsCount{{Value: "2026-03-01", Count: 4}}, analytics.SubmissionsOverTime)
+	require.Len(t, analytics.Fields, 4)
+
+	assert.Equal(t, 1.0, analytics.Fields[0].CompletionRate)
+	assert.Nil(t, analytics.Fields[0].Options)
+
+	shirt := analytics.Fields[1]
+	assert.Equal(t, 0.75, shirt.CompletionRate)
+	assert.Equal(t, []analyticsCount{{Value: "M", Count: 2}, {Value: "S", Count: 1}, {Value: "L", Count: 0}}, shirt.Options)
+	assert.False(t, shirt.OptionsLimited)
+
+	age := analytics.Fields[2]
+	assert.Equal(t, []analyticsBucket{{Min: 18.0, Max: 25.0, Count: 2}}, age.Histogram)
+	require.NotNil(t, age.Min)
+	assert.Equal(t, 18.0, *age.Min)
+	assert.Equal(t, 21.5, *age.Mean)
+
+	school := analytics.Fields[3]
+	assert.Len(t, school.Options, analyticsMaxOptions)
+	assert.True(t, school.OptionsLimited)
+	assert.Equal(t, 0.0, school.CompletionRate)
+}
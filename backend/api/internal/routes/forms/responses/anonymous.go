@@ -0,0 +1,170 @@
+package responses
+
+import (
+	"api/internal/types"
+	"log"
+	"net/http"
+	"shared/models"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxPendingPerEmail limits how many forms an email can have unconfirmed anonymous responses to at once
+	maxPendingPerEmail = 3
+
+	// maxPendingPerIP limits how many anonymous responses an address can start in pendingIPWindow
+	maxPendingPerIP = 10
+	pendingIPWindow = time.Hour
+)
+
+type anonymousResponseRequest struct {
+	Email string                 `json:"email" validate:"required,email"`
+	Data  map[string]interface{} `json:"data" validate:"required"`
+}
+
+// submitAnonymousResponseHandler holds a response from someone without an account until they confirm their email,
+// the event listener sends the confirmation link
+func submitAnonymousResponseHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		var req anonymousResponseRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if form.Status != "published" || !form.AllowAnonymous {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not accept anonymous responses"})
+			return
+		}
+
+		if (!form.CloseSubmissionsAt.IsZero() && form.CloseSubmissionsAt.Before(time.Now())) ||
+			(!form.OpenSubmissionsAt.IsZero() && form.OpenSubmissionsAt.After(time.Now())) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting submissions"})
+			return
+		}
+
+		// Validated now so the applicant can fix mistakes, it's validated again when confirmed
+		if fieldErrors := utils.ValidateFormData(form, req.Data, false); len(fieldErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.FormDataErrorMessage(form, fieldErrors), "fieldErrors": fieldErrors})
+			return
+		}
+		utils.DropHiddenFormFields(form, req.Data)
+
+		if !checkPendingLimits(c, params, formID, req.Email) {
+			return
+		}
+
+		_, err = params.MongoService.SavePendingResponse(c, models.PendingResponse{
+			FormID:    formID,
+			Email:     req.Email,
+			Data:      req.Data,
+			RequestIP: c.ClientIP(),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save response"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Check your email for a link to confirm your response"})
+	}
+}
+
+// checkPendingLimits stops the anonymous endpoint being used to send confirmation emails to someone else's address
+// over and over. Submitting again to the same form is always allowed, it only replaces the pending response.
+// The error response is written when it returns false.
+func checkPendingLimits(c *gin.Context, params *types.RouteParams, formID primitive.ObjectID, email string) bool {
+	email = strings.ToLower(email)
+	sameResponse := bson.M{"formID": formID, "email": email}
+
+	existing, err := params.MongoService.CountPendingResponses(c, sameResponse)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return false
+	}
+	if existing > 0 {
+		return true
+	}
+
+	perEmail, err := params.MongoService.CountPendingResponses(c, bson.M{"email": email})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return false
+	}
+	if perEmail >= maxPendingPerEmail {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "This email already has responses waiting to be confirmed, confirm them first"})
+		return false
+	}
+
+	perIP, err := params.MongoService.CountPendingResponses(c, bson.M{"requestIP": c.ClientIP(), "createdAt": bson.M{"$gt": time.Now().Add(-pendingIPWindow)}})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return false
+	}
+	if perIP >= maxPendingPerIP {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many responses, try again later"})
+		return false
+	}
+
+	return true
+}
+
+// confirmAnonymousResponseHandler saves a pending anonymous response as a submission, it's opened straight
+// from the confirmation email so it can't require a login
+func confirmAnonymousResponseHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		pendingID, err := utils.VerifyPendingResponseToken(c.Query("token"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid confirmation link"})
+			return
+		}
+
+		pending, err := params.MongoService.ConfirmPendingResponse(c, pendingID)
+		if err != nil || pending.FormID != formID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "This confirmation link has expired or was already used"})
+			return
+		}
+
+		response, ok := submitResponse(c, params, models.FormResponse{FormID: formID, Data: pending.Data, AnonymousEmail: pending.Email})
+		if !ok {
+			// The link keeps working until it expires in case the problem is temporary
+			params.MongoService.ReleasePendingResponse(c, pendingID)
+			return
+		}
+
+		// The response is saved, a leftover pending response can't be confirmed again and is deleted once it expires
+		if _, err := params.MongoService.DeletePendingResponse(c, pendingID); err != nil {
+			log.Printf("Failed to delete pending response %s: %v", pendingID.Hex(), err)
+		}
+
+		c.JSON(http.StatusOK, submissionResult(response))
+	}
+}
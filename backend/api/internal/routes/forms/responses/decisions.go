@@ -0,0 +1,396 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxBulkDecisions limits how many responses a single bulk decision can change
+	maxBulkDecisions = 5000
+
+	// bulkDecisionProgressEvery is how many responses are decided between progress lines
+	bulkDecisionProgressEvery = 25
+)
+
+var decisions = []models.Decision{
+	models.DecisionPending, models.DecisionAccepted, models.DecisionRejected,
+	models.DecisionWaitlisted, models.DecisionConfirmed, models.DecisionDeclined,
+}
+
+type decisionRequest struct {
+	Decision models.Decision `json:"decision" validate:"required,oneof=pending accepted rejected waitlisted confirmed declined"`
+}
+
+// bulkDecisionRequest decides either the listed responses or the ones matching a listing query, eg: the top 300
+// by score is {"query": {"sort": "reviewScore", "order": "desc"}, "limit": 300}
+type bulkDecisionRequest struct {
+	Decision    models.Decision      `json:"decision" validate:"required,oneof=pending accepted rejected waitlisted confirmed declined"`
+	ResponseIDs []primitive.ObjectID `json:"responseIDs,omitempty" validate:"max=5000"`
+	Query       map[string]string    `json:"query,omitempty"`
+	Limit       int                  `json:"limit,omitempty" validate:"min=0,max=5000"`
+}
+
+// errDecisionChanged is returned by applyDecision when the response's decision changed after it was read
+var errDecisionChanged = errors.New("the decision was changed by someone else")
+
+// applyDecision sets the response's decision if it hasn't changed since the response was read, then records it and
+// fires FieldChange pipelines on the decision. Only the decision is written so it can't undo anything saved since.
+// Rejecting or declining a response gives its place on the form to the waitlist, changing the decision back takes
+// a place again, or waitlists the response when the form is full. It reports whether the decision changed.
+func applyDecision(ctx context.Context, params *types.RouteParams, form *models.FormStructure, response models.FormResponse, decision models.Decision, decidedBy primitive.ObjectID) (bool, error) {
+	if response.CurrentDecision() == decision {
+		return false, nil
+	}
+
+	decided := response
+	decided.Decision = decision
+	decided.DecidedAt = time.Now()
+	decided.DecidedBy = decidedBy
+
+	// The place is taken before the decision is saved so a full form without a waitlist refuses the decision
+	reclaimed := !decision.ReleasesPlace() && response.Status == models.ResponseReleased
+	if reclaimed {
+		status, position, err := params.MongoService.ReclaimResponsePlace(ctx, *form, response.ID)
+		if err != nil {
+			return false, err
+		}
+		decided.Status, decided.WaitlistPosition = status, position
+	}
+
+	saved, err := params.MongoService.SetResponseDecision(ctx, response.ID, response.CurrentDecision(), decision, decidedBy)
+	if err == nil && !saved {
+		err = errDecisionChanged
+	}
+	if err != nil {
+		if reclaimed {
+			releasePlace(ctx, params, form.ID, response.ID)
+		}
+		return false, err
+	}
+
+	if decision.ReleasesPlace() && response.Status != models.ResponseReleased {
+		if previous := releasePlace(ctx, params, form.ID, response.ID); previous != nil {
+			decided.Status, decided.WaitlistPosition = models.ResponseReleased, 0
+		}
+	}
+
+	change := models.ResponseChange{ChangedBy: decidedBy, Source: models.ResponseChangeAPI, Action: "decision"}
+	if err := responseChanged(ctx, params, form, response, decided, change); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// releasePlace gives the response's place on the form to the next waitlisted response. It returns the response as
+// it was before, nil when it was already released. Failures are logged since the decision has been saved.
+func releasePlace(ctx context.Context, params *types.RouteParams, formID primitive.ObjectID, responseID primitive.ObjectID) *models.FormResponse {
+	previous, promoted, err := params.MongoService.ReleaseResponsePlace(ctx, responseID)
+	if err != nil {
+		log.Printf("Failed to release the place of response %s: %v", responseID.Hex(), err)
+		return previous
+	}
+
+	if promoted != nil {
+		if err := announcePromotion(ctx, params, formID, promoted); err != nil {
+			log.Printf("Failed to announce the promotion of response %s: %v", promoted.ID.Hex(), err)
+		}
+	}
+	return previous
+}
+
+// decisionFilterCondition converts ?filter[decision]= to a condition, it takes eq or in. Responses without a decision are pending.
+func decisionFilterCondition(filter string) (bson.M, error) {
+	operator, value, ok := strings.Cut(filter, ":")
+	if !ok || (operator != filterEq && operator != filterIn) {
+		return nil, fmt.Errorf("filter on decision must be eq:<decision> or in:<decisions>")
+	}
+
+	var values []interface{}
+	for _, item := range strings.Split(value, ",") {
+		decision := models.Decision(strings.TrimSpace(item))
+		if !isDecision(decision) {
+			return nil, fmt.Errorf("%s is not a decision", item)
+		}
+
+		values = append(values, string(decision))
+		if decision == models.DecisionPending {
+			values = append(values, nil)
+		}
+	}
+
+	return bson.M{"decision": bson.M{"$in": values}}, nil
+}
+
+func isDecision(decision models.Decision) bool {
+	for _, known := range decisions {
+		if decision == known {
+			return true
+		}
+	}
+	return false
+}
+
+// setDecisionHandler sets the decision on a single response
+func setDecisionHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form, response := getDecisionResponse(c, params)
+		if response == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this form"})
+			return
+		}
+
+		var req decisionRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		changed, err := applyDecision(c, params, form, *response, req.Decision, authenticatedUser.ID)
+		if err == errDecisionChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "The decision was changed by someone else, reload the response and try again"})
+			return
+		} else if err == mongodb.ErrFormFull {
+			c.JSON(http.StatusConflict, gin.H{"error": "The form is full, the response no longer has a place"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save decision"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": response.ID, "decision": req.Decision, "changed": changed})
+	}
+}
+
+// respondToDecisionHandler lets an accepted applicant confirm or decline their place, a confirmed place can still be declined
+func respondToDecisionHandler(params *types.RouteParams, decision models.Decision) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form, response := getDecisionResponse(c, params)
+		if response == nil {
+			return
+		}
+
+		if response.UserID != authenticatedUser.ID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+			return
+		}
+
+		current := response.CurrentDecision()
+		if current != models.DecisionAccepted && !(current == models.DecisionConfirmed && decision == models.DecisionDeclined) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A %s application can't be %s", current, decision)})
+			return
+		}
+
+		if _, err := applyDecision(c, params, form, *response, decision, authenticatedUser.ID); err == errDecisionChanged {
+			c.JSON(http.StatusConflict, gin.H{"error": "Your application's decision has changed, reload and try again"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save your response"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": response.ID, "decision": decision})
+	}
+}
+
+// getDecisionResponse reads the form and response in the route, the error response is written when the response is nil
+func getDecisionResponse(c *gin.Context, params *types.RouteParams) (*models.FormStructure, *models.FormResponse) {
+	formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return nil, nil
+	}
+
+	responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+		return nil, nil
+	}
+
+	form, err := params.MongoService.GetForm(c, formID, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+		return nil, nil
+	}
+
+	responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "formID": formID, "isDeleted": bson.M{"$ne": true}}, nil)
+	if err != nil || len(responses) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+		return nil, nil
+	}
+
+	return form, &responses[0]
+}
+
+// bulkDecisionHandler decides many responses at once, each one goes through the same path as a single decision.
+// Progress is streamed as NDJSON: a "started" line with the total, "progress" lines, a "failure" line for each
+// response that couldn't be decided and a final "done" line with the counts.
+func bulkDecisionHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this form"})
+			return
+		}
+
+		var req bulkDecisionRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if (len(req.ResponseIDs) > 0) == (req.Query != nil) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Either responseIDs or query is required"})
+			return
+		}
+
+		filter := bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}}
+		query := &responseQuery{sortKey: sortSubmittedAt}
+		if req.Query != nil {
+			values := url.Values{}
+			for key, value := range req.Query {
+				values.Set(key, value)
+			}
+
+			if query, err = parseResponseQuery(form, values, false); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if len(query.conditions) > 0 {
+				filter["$and"] = query.conditions
+			}
+		} else {
+			filter["_id"] = bson.M{"$in": req.ResponseIDs}
+		}
+
+		limit := req.Limit
+		if limit == 0 {
+			limit = maxBulkDecisions
+		}
+
+		// One past the limit tells whether a query matched too many responses
+		opts := query.findOptions()
+		opts.SetLimit(int64(limit + 1))
+
+		responses, err := params.MongoService.ListResponses(c, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		if len(responses) > limit {
+			if req.Limit == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d responses can be decided at once", maxBulkDecisions)})
+				return
+			}
+			responses = responses[:limit]
+		}
+
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+
+		encoder := json.NewEncoder(c.Writer)
+		report := func(line gin.H) {
+			encoder.Encode(line)
+			c.Writer.Flush()
+		}
+
+		total := len(responses)
+		report(gin.H{"type": "started", "total": total})
+
+		// Listed responses that weren't found have been withdrawn or belong to another form
+		found := map[primitive.ObjectID]bool{}
+		for _, response := range responses {
+			found[response.ID] = true
+		}
+
+		failed := 0
+		for _, responseID := range req.ResponseIDs {
+			if !found[responseID] {
+				failed++
+				found[responseID] = true
+				report(gin.H{"type": "failure", "responseID": responseID, "error": "Response does not exist"})
+			}
+		}
+
+		changed, unchanged := 0, 0
+		for i, response := range responses {
+			if decided, err := applyDecision(c, params, form, response, req.Decision, authenticatedUser.ID); err == errDecisionChanged {
+				failed++
+				report(gin.H{"type": "failure", "responseID": response.ID, "error": "The decision was changed by someone else"})
+			} else if err == mongodb.ErrFormFull {
+				failed++
+				report(gin.H{"type": "failure", "responseID": response.ID, "error": "The form is full, the response no longer has a place"})
+			} else if err != nil {
+				failed++
+				log.Printf("Failed to decide response %s: %v", response.ID.Hex(), err)
+				report(gin.H{"type": "failure", "responseID": response.ID, "error": "Failed to save decision"})
+			} else if decided {
+				changed++
+			} else {
+				unchanged++
+			}
+
+			if (i+1)%bulkDecisionProgressEvery == 0 && i+1 < total {
+				report(gin.H{"type": "progress", "processed": i + 1, "total": total})
+			}
+		}
+
+		report(gin.H{"type": "done", "total": total, "changed": changed, "unchanged": unchanged, "failed": failed})
+	}
+}
@@ -0,0 +1,138 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"net/url"
+	"shared/models"
+	"shared/mongodb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDecisionFilterCondition(t *testing.T) {
+	condition, err := decisionFilterCondition("eq:accepted")
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"decision": bson.M{"$in": []interface{}{"accepted"}}}, condition)
+
+	// Responses without a decision are pending
+	condition, err = decisionFilterCondition("in:pending,waitlisted")
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"decision": bson.M{"$in": []interface{}{"pending", nil, "waitlisted"}}}, condition)
+
+	for _, invalid := range []string{"accepted", "range:accepted", "eq:maybe"} {
+		_, err := decisionFilterCondition(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	query, err := parseResponseQuery(queryForm, url.Values{"filter[decision]": {"eq:rejected"}}, false)
+	require.NoError(t, err)
+	assert.Len(t, query.conditions, 1)
+}
+
+func TestFieldChangeData(t *testing.T) {
+	response := models.FormResponse{Data: map[string]interface{}{"name": "Ada"}}
+	assert.Equal(t, map[string]interface{}{"name": "Ada", models.DecisionFieldID: "pending", models.TagsFieldID: []string{}}, fieldChangeData(response))
+
+	response.Decision = models.DecisionAccepted
+	assert.Equal(t, "accepted", fieldChangeData(response)[models.DecisionFieldID])
+	assert.NotContains(t, response.Data, models.DecisionFieldID)
+}
+
+func TestApplyDecisionConflict(t *testing.T) {
+	params := &types.RouteParams{MongoService: &mongodb.MockMongoService{}}
+	response := models.FormResponse{ID: primitive.NewObjectID(), Decision: models.DecisionAccepted}
+
+	// Deciding the same again doesn't write anything
+	changed, err := applyDecision(context.Background(), params, &models.FormStructure{}, response, models.DecisionAccepted, primitive.NewObjectID())
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// The mock reports the decision didn't match, as if it changed after the response was read
+	changed, err = applyDecision(context.Background(), params, &models.FormStructure{}, response, models.DecisionRejected, primitive.NewObjectID())
+	assert.Equal(t, errDecisionChanged, err)
+	assert.False(t, changed)
+}
+
+// placeStore records the calls applyDecision makes to give places back and take them again
+type placeStore struct {
+	*mongodb.MockMongoService
+	form       models.FormStructure
+	before     models.ResponseStatus
+	reclaimErr error
+
+	decided   int
+	released  int
+	reclaimed int
+	promoted  int
+}
+
+func (s *placeStore) SetResponseDecision(ctx context.Context, responseID primitive.ObjectID, previous models.Decision, decision models.Decision, decidedBy primitive.ObjectID) (bool, error) {
+	s.decided++
+	return true, nil
+}
+
+// ReleaseResponsePlace hands a freed place to a waitlisted response, as the service does while anyone is waiting
+func (s *placeStore) ReleaseResponsePlace(ctx context.Context, responseID primitive.ObjectID) (*models.FormResponse, *models.FormResponse, error) {
+	s.released++
+	previous := &models.FormResponse{ID: responseID, Status: s.before}
+	if !previous.HoldsPlace() {
+		return previous, nil, nil
+	}
+	return previous, &models.FormResponse{ID: primitive.NewObjectID(), FormID: s.form.ID, Status: models.ResponseSubmitted}, nil
+}
+
+func (s *placeStore) ReclaimResponsePlace(ctx context.Context, form models.FormStructure, responseID primitive.ObjectID) (models.ResponseStatus, int, error) {
+	s.reclaimed++
+	return models.ResponseSubmitted, 0, s.reclaimErr
+}
+
+func (s *placeStore) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
+	return &s.form, nil
+}
+
+// ListPipelines counts the promotions announced to the form's WaitlistPromotion pipelines
+func (s *placeStore) ListPipelines(ctx context.Context, filter bson.M) ([]models.PipelineConfiguration, error) {
+	if filter["event.type"] == "WaitlistPromotion" {
+		s.promoted++
+	}
+	return nil, nil
+}
+
+func TestApplyDecisionReleasesPlaces(t *testing.T) {
+	form := models.FormStructure{ID: primitive.NewObjectID(), MaxSubmissions: 10, EnableWaitlist: true}
+	decide := func(store *placeStore, response models.FormResponse, decision models.Decision) error {
+		params := &types.RouteParams{MongoService: store}
+		_, err := applyDecision(context.Background(), params, &store.form, response, decision, primitive.NewObjectID())
+		return err
+	}
+
+	// Rejecting a response with a place gives it to the waitlist
+	store := &placeStore{MockMongoService: mongodb.NewMockMongoService(), form: form, before: models.ResponseSubmitted}
+	require.NoError(t, decide(store, models.FormResponse{ID: primitive.NewObjectID(), Status: models.ResponseSubmitted, Decision: models.DecisionAccepted}, models.DecisionRejected))
+	assert.Equal(t, 1, store.released)
+	assert.Equal(t, 1, store.promoted)
+
+	// Declining a waitlisted response takes it off the waitlist without freeing a place
+	store = &placeStore{MockMongoService: mongodb.NewMockMongoService(), form: form, before: models.ResponseWaitlisted}
+	require.NoError(t, decide(store, models.FormResponse{ID: primitive.NewObjectID(), Status: models.ResponseWaitlisted, Decision: models.DecisionAccepted}, models.DecisionDeclined))
+	assert.Equal(t, 1, store.released)
+	assert.Zero(t, store.promoted)
+
+	// A released response takes a place again when it's accepted, and nothing is released when it's declined after rejecting
+	store = &placeStore{MockMongoService: mongodb.NewMockMongoService(), form: form}
+	released := models.FormResponse{ID: primitive.NewObjectID(), Status: models.ResponseReleased, Decision: models.DecisionRejected}
+	require.NoError(t, decide(store, released, models.DecisionAccepted))
+	require.NoError(t, decide(store, released, models.DecisionDeclined))
+	assert.Equal(t, 1, store.reclaimed)
+	assert.Zero(t, store.released)
+
+	// A full form without a waitlist refuses the decision before it's saved
+	store = &placeStore{MockMongoService: mongodb.NewMockMongoService(), form: form, reclaimErr: mongodb.ErrFormFull}
+	assert.Equal(t, mongodb.ErrFormFull, decide(store, released, models.DecisionAccepted))
+	assert.Zero(t, store.decided)
+}
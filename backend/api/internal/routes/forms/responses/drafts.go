@@ -0,0 +1,178 @@
+package responses
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// getResponseDraftHandler returns the user's draft so they can resume filling in the form
+func getResponseDraftHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		draft, err := params.MongoService.GetResponseDraft(c, formID, authenticatedUser.ID)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "You have no draft for this form"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+// saveResponseDraftHandler saves partial data without validating it or triggering pipelines
+func saveResponseDraftHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		var formData map[string]interface{}
+		if err := utils.BindJSON(c, &formData); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if formData == nil {
+			formData = map[string]interface{}{}
+		}
+
+		// Loaded with its access rules to check the user can submit it, the form isn't returned
+		form, err := params.MongoService.GetForm(c, formID, false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if form.Status != "published" || (!form.CloseSubmissionsAt.IsZero() && form.CloseSubmissionsAt.Before(time.Now())) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not accepting submissions"})
+			return
+		}
+
+		if form.IsRestricted {
+			if grant, restrictMessage := mongodb.CheckFormAccess(c, params.MongoService, form); grant == nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
+				return
+			}
+		}
+
+		draft, err := params.MongoService.SaveResponseDraft(c, models.ResponseDraft{
+			FormID: formID,
+			UserID: authenticatedUser.ID,
+			Data:   formData,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save draft"})
+			return
+		}
+
+		// Drafts aren't validated, so only the valid file references are kept from being cleaned up
+		uploadIDs, _ := verifyFileUploads(c, params, form, formData, authenticatedUser.ID, primitive.NilObjectID)
+		if _, err := params.MongoService.AttachDraftFileUploads(c, draft.ID, uploadIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save draft"})
+			return
+		}
+
+		c.JSON(http.StatusOK, draft)
+	}
+}
+
+func deleteResponseDraftHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		draft, err := params.MongoService.GetResponseDraft(c, formID, authenticatedUser.ID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "You have no draft for this form"})
+			return
+		}
+
+		if !deleteResponseDraft(c, params, draft) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete draft"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Draft deleted successfully"})
+	}
+}
+
+// submitResponseDraftHandler submits the saved draft as the user's response, it's validated the same as a direct submission
+func submitResponseDraftHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		draft, err := params.MongoService.GetResponseDraft(c, formID, authenticatedUser.ID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "You have no draft for this form"})
+			return
+		}
+
+		response, ok := submitResponse(c, params, models.FormResponse{FormID: formID, Data: draft.Data, UserID: authenticatedUser.ID})
+		if !ok {
+			return
+		}
+
+		// The response is already saved, a leftover draft is reported but does not fail the submission
+		result := submissionResult(response)
+		if !deleteResponseDraft(c, params, draft) {
+			result["warning"] = "Failed to delete draft"
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// deleteResponseDraft deletes the draft and releases the uploads it was holding on to
+func deleteResponseDraft(c *gin.Context, params *types.RouteParams, draft *models.ResponseDraft) bool {
+	if _, err := params.MongoService.AttachDraftFileUploads(c, draft.ID, nil); err != nil {
+		return false
+	}
+
+	_, err := params.MongoService.DeleteResponseDraft(c, draft.ID)
+	return err == nil
+}
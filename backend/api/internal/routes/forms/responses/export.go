@@ -0,0 +1,341 @@
+package responses
+
+import (
+	"api/internal/types"
+	"fmt"
+	"log"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Columns every export can include besides the form's fields
+const (
+	columnResponseID  = "id"
+	columnUserID      = "userId"
+	columnSubmittedAt = "submittedAt"
+	columnStatus      = "status"
+	columnDecision    = "decision"
+	columnTags        = "tags"
+)
+
+var metaColumnHeaders = map[string]string{
+	columnResponseID:  "Response ID",
+	columnUserID:      "User ID",
+	columnSubmittedAt: "Submitted At",
+	columnStatus:      "Status",
+	columnDecision:    "Decision",
+	columnTags:        "Tags",
+}
+
+// exportColumn is a column of an export, field is nil for the response's own columns
+type exportColumn struct {
+	key    string
+	header string
+	field  *models.FormField
+}
+
+// exportResponsesHandler streams a form's responses as CSV, XLSX, JSON or NDJSON. It takes:
+//   - ?format=csv (the default), xlsx, json or ndjson
+//   - the listing's ?filter[], ?search=, ?sort= and ?order=, every matching response is exported
+//   - ?columns= a comma separated list of field keys and id, userId, submittedAt, status, decision or tags, in the order to export them
+//   - ?tz= the timezone for dates, the event's timezone by default
+//   - ?schema= and ?version= as for listings, the original schema can only be exported one version at a time
+func exportResponsesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+			return
+		}
+
+		newWriter, ok := exportFormats[c.DefaultQuery("format", "csv")]
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv, xlsx, json or ndjson"})
+			return
+		}
+
+		query, err := parseResponseQuery(form, c.Request.URL.Query(), false)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		location, err := responseLocation(c, params, form)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid timezone"})
+			return
+		}
+
+		filter := bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}}
+		fields, ok := exportFields(c, params, form, filter)
+		if !ok {
+			return
+		}
+
+		columns, err := selectExportColumns(fields, c.Query("columns"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(query.conditions) > 0 {
+			filter["$and"] = query.conditions
+		}
+
+		writer := newWriter(c.Writer)
+		c.Header("Content-Type", writer.contentType())
+		c.Header("Content-Disposition", "attachment;filename=form_responses."+writer.extension())
+
+		// Once the first row is written the status can't change, errors can only end the download early
+		err = writer.writeHeader(columns)
+		if err == nil {
+			err = params.MongoService.StreamResponses(c, filter, query.findOptions(), func(response models.FormResponse) error {
+				values := make([]interface{}, len(columns))
+				for i, column := range columns {
+					values[i] = exportValue(column, response, location)
+				}
+				return writer.writeRow(values)
+			})
+		}
+		if err == nil {
+			err = writer.close()
+		}
+		if err != nil {
+			log.Printf("Failed to export responses for form %s: %v", formID.Hex(), err)
+		}
+	}
+}
+
+// responseLocation is the timezone dates are exported and grouped in, from ?tz= or the event's timezone, falling back to UTC
+func responseLocation(c *gin.Context, params *types.RouteParams, form *models.FormStructure) (*time.Location, error) {
+	if tz := c.Query("tz"); tz != "" {
+		return time.LoadLocation(tz)
+	}
+
+	if event, err := params.MongoService.GetEvent(c, form.EventID); err == nil && event.Metadata.Timezone != "" {
+		if location, err := time.LoadLocation(event.Metadata.Timezone); err == nil {
+			return location, nil
+		}
+	}
+	return time.UTC, nil
+}
+
+// exportFields returns the fields the export's columns can be chosen from for ?schema= and ?version=.
+// The latest schema has the form's fields followed by any removed fields that responses still have data for.
+// The error response is written when it returns false.
+func exportFields(c *gin.Context, params *types.RouteParams, form *models.FormStructure, filter bson.M) ([]models.FormField, bool) {
+	schema := c.DefaultQuery("schema", schemaLatest)
+	if schema != schemaLatest && schema != schemaOriginal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "schema must be latest or original"})
+		return nil, false
+	}
+
+	// An export has a single set of columns so the original schema can only be exported one version at a time
+	if schema == schemaOriginal && c.Query("version") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "version is required to export responses with their original schema"})
+		return nil, false
+	}
+
+	versions, err := params.MongoService.ListFormVersions(c, form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, false
+	}
+
+	selected, ok := selectedFormVersion(c, versions)
+	if !ok {
+		return nil, false
+	}
+	if selected != nil {
+		filter["formVersionID"] = selected.ID
+	}
+
+	if schema == schemaOriginal {
+		return selected.Attrs, true
+	}
+
+	merged, removed := utils.MergeRemovedFormFields(form.Attrs, versions)
+	fields := make([]models.FormField, 0, len(merged))
+	for _, field := range merged {
+		if !removed[field.Key] {
+			fields = append(fields, field)
+			continue
+		}
+
+		withData := bson.M{"data." + field.Key: bson.M{"$exists": true}}
+		for key, value := range filter {
+			withData[key] = value
+		}
+
+		count, err := params.MongoService.CountResponses(c, withData)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return nil, false
+		}
+		if count > 0 {
+			field.Question += " (removed)"
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, true
+}
+
+// selectExportColumns picks the columns named in ?columns=, in that order. Without any every field is exported
+// after the response's own columns. Headers are the questions, a repeated question is followed by its field key.
+func selectExportColumns(fields []models.FormField, selection string) ([]exportColumn, error) {
+	byKey := map[string]models.FormField{}
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	var keys []string
+	if selection == "" {
+		keys = []string{columnResponseID, columnUserID, columnSubmittedAt, columnStatus, columnDecision}
+		for _, field := range fields {
+			keys = append(keys, field.Key)
+		}
+	} else {
+		for _, key := range strings.Split(selection, ",") {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+
+	questions := map[string]int{}
+	for _, field := range fields {
+		questions[exportHeader(field)]++
+	}
+
+	columns := make([]exportColumn, 0, len(keys))
+	seen := map[string]bool{}
+	for _, key := range keys {
+		if seen[key] {
+			return nil, fmt.Errorf("column %s is selected twice", key)
+		}
+		seen[key] = true
+
+		if header, ok := metaColumnHeaders[key]; ok {
+			columns = append(columns, exportColumn{key: key, header: header})
+			continue
+		}
+
+		field, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %s", key)
+		}
+
+		header := exportHeader(field)
+		if questions[header] > 1 {
+			header = fmt.Sprintf("%s (%s)", header, field.Key)
+		}
+		columns = append(columns, exportColumn{key: key, header: header, field: &field})
+	}
+
+	return columns, nil
+}
+
+// exportHeader is the header of a field's column, internal fields are marked so they aren't mistaken
+// for something the applicant answered
+func exportHeader(field models.FormField) string {
+	if field.IsInternal {
+		return field.Question + " (internal)"
+	}
+	return field.Question
+}
+
+// exportValue is a response's value for a column. Values keep their type so each format can write them natively,
+// lists are []string, addresses are map[string]interface{} and dates are strings in the export's timezone.
+func exportValue(column exportColumn, response models.FormResponse, location *time.Location) interface{} {
+	if column.field == nil {
+		switch column.key {
+		case columnResponseID:
+			return response.ID.Hex()
+		case columnUserID:
+			if response.UserID.IsZero() {
+				return nil
+			}
+			return response.UserID.Hex()
+		case columnSubmittedAt:
+			return response.CreatedAt.In(location).Format(time.RFC3339)
+		case columnStatus:
+			return responseStatusLabel(response)
+		case columnDecision:
+			return string(response.CurrentDecision())
+		case columnTags:
+			return strings.Join(response.Tags, ", ")
+		}
+		return nil
+	}
+
+	value, ok := response.Data[column.field.Key]
+	if !ok || value == nil {
+		return nil
+	}
+
+	switch value := value.(type) {
+	case primitive.A:
+		return exportList(value)
+	case []interface{}:
+		return exportList(value)
+	case primitive.D:
+		return value.Map()
+	case primitive.M:
+		return map[string]interface{}(value)
+	case string:
+		return exportDate(column.field.Type, value, location)
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	}
+	return value
+}
+
+func exportList(items []interface{}) []string {
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		list = append(list, fmt.Sprint(item))
+	}
+	return list
+}
+
+// exportDate converts date and timestamp answers to the export's timezone, other text is unchanged
+func exportDate(fieldType models.FormFieldType, value string, location *time.Location) string {
+	if fieldType != models.FieldTypeDate && fieldType != models.FieldTypeTimestamp {
+		return value
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+
+	if fieldType == models.FieldTypeDate {
+		return parsed.In(location).Format("2006-01-02")
+	}
+	return parsed.In(location).Format(time.RFC3339)
+}
@@ -0,0 +1,130 @@
+package responses
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"shared/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var exportFieldsFixture = []models.FormField{
+	{Key: "name", Question: "Name", Type: models.FieldTypeText},
+	{Key: "tracks", Question: "Tracks", Type: models.FieldTypeMultiSelect},
+	{Key: "arrival", Question: "Arrival", Type: models.FieldTypeTimestamp},
+	{Key: "notes", Question: "Name", Type: models.FieldTypeTextArea, IsInternal: true},
+	{Key: "other", Question: "Name", Type: models.FieldTypeText},
+}
+
+func TestSelectExportColumns(t *testing.T) {
+	columns, err := selectExportColumns(exportFieldsFixture, "")
+	require.NoError(t, err)
+	require.Len(t, columns, 10)
+	assert.Equal(t, "Response ID", columns[0].header)
+	assert.Equal(t, "Name (name)", columns[5].header)
+	assert.Equal(t, "Name (internal)", columns[8].header)
+
+	columns, err = selectExportColumns(exportFieldsFixture, "tracks, submittedAt")
+	require.NoError(t, err)
+	assert.Equal(t, "tracks", columns[0].key)
+	assert.Equal(t, "Submitted At", columns[1].header)
+
+	_, err = selectExportColumns(exportFieldsFixture, "missing")
+	assert.Error(t, err)
+	_, err = selectExportColumns(exportFieldsFixture, "name,name")
+	assert.Error(t, err)
+}
+
+func TestExportValue(t *testing.T) {
+	location, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+
+	columns, err := selectExportColumns(exportFieldsFixture, "submittedAt,userId,tracks,arrival,name")
+	require.NoError(t, err)
+
+	response := models.FormResponse{
+		CreatedAt: time.Date(2026, 3, 1, 17, 0, 0, 0, time.UTC),
+		Data: map[string]interface{}{
+			"tracks":  primitive.A{"ai", "web"},
+			"arrival": "2026-03-02T14:30:00Z",
+		},
+	}
+
+	assert.Equal(t, "2026-03-01T12:00:00-05:00", exportValue(columns[0], response, location))
+	assert.Nil(t, exportValue(columns[1], response, location))
+	assert.Equal(t, []string{"ai", "web"}, exportValue(columns[2], response, location))
+	assert.Equal(t, "2026-03-02T09:30:00-05:00", exportValue(columns[3], response, location))
+	assert.Nil(t, exportValue(columns[4], response, location))
+}
+
+func TestCellText(t *testing.T) {
+	assert.Equal(t, "", cellText(nil))
+	assert.Equal(t, "ai; web", cellText([]string{"ai", "web"}))
+	assert.Equal(t, "1.5", cellText(1.5))
+	assert.Equal(t, "1 Main St, Toronto, Canada", cellText(map[string]interface{}{"streetAddress": "1 Main St", "city": "Toronto", "country": "Canada"}))
+
+	// Answers a spreadsheet would run as formulas are written as text, numbers are left alone
+	for _, formula := range []string{"=HYPERLINK(\"http://evil\")", "+1", "-2+3", "@SUM(A1)", "\tx", "\rx"} {
+		assert.Equal(t, "'"+formula, cellText(formula), formula)
+	}
+	assert.Equal(t, "'=1; 2", cellText([]string{"=1", "2"}))
+	assert.Equal(t, "-1.5", cellText(-1.5))
+	assert.Equal(t, "a=b", cellText("a=b"))
+}
+
+func TestExportWriters(t *testing.T) {
+	columns := []exportColumn{{key: "name", header: "Name"}, {key: "tracks", header: "Tracks"}, {key: "age", header: "Age"}}
+	rows := [][]interface{}{
+		{"Ada, L", []string{"ai", "web"}, 36.0},
+		{nil, []string{}, nil},
+		{"=cmd|' /C calc'!A0", []string{"ai"}, -1.0},
+	}
+
+	write := func(format string) string {
+		var buf bytes.Buffer
+		writer := exportFormats[format](&buf)
+		require.NoError(t, writer.writeHeader(columns))
+		for _, row := range rows {
+			require.NoError(t, writer.writeRow(row))
+		}
+		require.NoError(t, writer.close())
+		return buf.String()
+	}
+
+	assert.Equal(t, "Name,Tracks,Age\n\"Ada, L\",ai; web,36\n,,\n'=cmd|' /C calc'!A0,ai,-1\n", write("csv"))
+	assert.Equal(t, `[{"name":"Ada, L","tracks":["ai","web"],"age":36},{"name":null,"tracks":[],"age":null},{"name":"=cmd|' /C calc'!A0","tracks":["ai"],"age":-1}]`, write("json"))
+	assert.Equal(t, "{\"name\":\"Ada, L\",\"tracks\":[\"ai\",\"web\"],\"age\":36}\n{\"name\":null,\"tracks\":[],\"age\":null}\n{\"name\":\"=cmd|' /C calc'!A0\",\"tracks\":[\"ai\"],\"age\":-1}\n", write("ndjson"))
+
+	xlsx := write("xlsx")
+	archive, err := zip.NewReader(strings.NewReader(xlsx), int64(len(xlsx)))
+	require.NoError(t, err)
+
+	var sheet string
+	for _, file := range archive.File {
+		if file.Name == "xl/worksheets/sheet1.xml" {
+			reader, err := file.Open()
+			require.NoError(t, err)
+			content, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			sheet = string(content)
+		}
+	}
+	assert.Len(t, archive.File, 5)
+	assert.Contains(t, sheet, `<c r="A2" t="inlineStr"><is><t xml:space="preserve">Ada, L</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="C2"><v>36</v></c>`)
+	assert.Contains(t, sheet, `<row r="3"><c r="B3" t="inlineStr">`)
+	assert.Contains(t, sheet, `<c r="A4" t="inlineStr"><is><t xml:space="preserve">&#39;=cmd|&#39; /C calc&#39;!A0</t></is></c>`)
+}
+
+func TestXLSXColumnName(t *testing.T) {
+	assert.Equal(t, "A", xlsxColumnName(0))
+	assert.Equal(t, "Z", xlsxColumnName(25))
+	assert.Equal(t, "AB", xlsxColumnName(27))
+	assert.Equal(t, "ZZ", xlsxColumnName(701))
+}
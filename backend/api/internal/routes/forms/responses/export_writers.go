@@ -0,0 +1,294 @@
+package responses
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// exportWriter writes an export one row at a time as responses are read
+type exportWriter interface {
+	contentType() string
+	extension() string
+	writeHeader(columns []exportColumn) error
+	writeRow(values []interface{}) error
+	close() error
+}
+
+var exportFormats = map[string]func(w io.Writer) exportWriter{
+	"csv":    newCSVExportWriter,
+	"xlsx":   newXLSXExportWriter,
+	"json":   func(w io.Writer) exportWriter { return newJSONExportWriter(w, false) },
+	"ndjson": func(w io.Writer) exportWriter { return newJSONExportWriter(w, true) },
+}
+
+// addressParts is the order an address's parts are written in a single cell
+var addressParts = []string{"streetAddress", "city", "region", "zipCode", "country"}
+
+// formulaPrefixes start a formula when a spreadsheet reads a cell, cells are written as text
+// rather than let an applicant's answer run as one
+const formulaPrefixes = "=+-@\t\r"
+
+// cellText writes a value in a single spreadsheet cell, lists are separated by semicolons
+func cellText(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(value)
+	case []string:
+		return escapeFormula(strings.Join(value, "; "))
+	case map[string]interface{}:
+		var parts []string
+		for _, part := range addressParts {
+			if text, ok := value[part].(string); ok && text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return escapeFormula(strings.Join(parts, ", "))
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return escapeFormula(fmt.Sprint(value))
+}
+
+// escapeFormula prefixes text that a spreadsheet would read as a formula with ', which makes it plain text
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune(formulaPrefixes, rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+type csvExportWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVExportWriter(w io.Writer) exportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *csvExportWriter) contentType() string {
+	return "text/csv"
+}
+
+func (w *csvExportWriter) extension() string {
+	return "csv"
+}
+
+func (w *csvExportWriter) writeHeader(columns []exportColumn) error {
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = escapeFormula(column.header)
+	}
+	return w.writer.Write(headers)
+}
+
+func (w *csvExportWriter) writeRow(values []interface{}) error {
+	row := make([]string, len(values))
+	for i, value := range values {
+		row[i] = cellText(value)
+	}
+	return w.writer.Write(row)
+}
+
+func (w *csvExportWriter) close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// jsonExportWriter writes an array of objects, or one object per line for NDJSON.
+// Objects are keyed by column key and keep the selected column order.
+type jsonExportWriter struct {
+	writer    *bufio.Writer
+	lines     bool
+	keys      [][]byte
+	wroteRows bool
+}
+
+func newJSONExportWriter(w io.Writer, lines bool) exportWriter {
+	return &jsonExportWriter{writer: bufio.NewWriter(w), lines: lines}
+}
+
+func (w *jsonExportWriter) contentType() string {
+	if w.lines {
+		return "application/x-ndjson"
+	}
+	return "application/json"
+}
+
+func (w *jsonExportWriter) extension() string {
+	if w.lines {
+		return "ndjson"
+	}
+	return "json"
+}
+
+func (w *jsonExportWriter) writeHeader(columns []exportColumn) error {
+	for _, column := range columns {
+		key, err := json.Marshal(column.key)
+		if err != nil {
+			return err
+		}
+		w.keys = append(w.keys, key)
+	}
+
+	if !w.lines {
+		_, err := w.writer.WriteString("[")
+		return err
+	}
+	return nil
+}
+
+func (w *jsonExportWriter) writeRow(values []interface{}) error {
+	if w.wroteRows && !w.lines {
+		w.writer.WriteString(",")
+	}
+	w.wroteRows = true
+
+	w.writer.WriteString("{")
+	for i, value := range values {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			w.writer.WriteString(",")
+		}
+		w.writer.Write(w.keys[i])
+		w.writer.WriteString(":")
+		w.writer.Write(encoded)
+	}
+	_, err := w.writer.WriteString("}")
+	if w.lines && err == nil {
+		_, err = w.writer.WriteString("\n")
+	}
+	return err
+}
+
+func (w *jsonExportWriter) close() error {
+	if !w.lines {
+		w.writer.WriteString("]")
+	}
+	return w.writer.Flush()
+}
+
+// xlsxMaxCellLength is the most characters Excel shows in a cell
+const xlsxMaxCellLength = 32767
+
+// xlsxParts are the parts of a single sheet workbook besides the sheet itself
+var xlsxParts = []struct{ name, content string }{
+	{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/><Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/></Types>`},
+	{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/></Relationships>`},
+	{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Responses" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+	{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/></Relationships>`},
+}
+
+// xlsxExportWriter streams a single sheet workbook, the sheet is written straight into the zip as rows arrive.
+// Text is written inline rather than in a shared strings table so nothing has to be held until the end.
+type xlsxExportWriter struct {
+	zip   *zip.Writer
+	sheet *bufio.Writer
+	row   int
+}
+
+func newXLSXExportWriter(w io.Writer) exportWriter {
+	return &xlsxExportWriter{zip: zip.NewWriter(w)}
+}
+
+func (w *xlsxExportWriter) contentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (w *xlsxExportWriter) extension() string {
+	return "xlsx"
+}
+
+func (w *xlsxExportWriter) writeHeader(columns []exportColumn) error {
+	for _, part := range xlsxParts {
+		file, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(file, part.content); err != nil {
+			return err
+		}
+	}
+
+	file, err := w.zip.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	w.sheet = bufio.NewWriter(file)
+	w.sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	w.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	headers := make([]interface{}, len(columns))
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+	return w.writeRow(headers)
+}
+
+func (w *xlsxExportWriter) writeRow(values []interface{}) error {
+	w.row++
+	fmt.Fprintf(w.sheet, `<row r="%d">`, w.row)
+	for i, value := range values {
+		ref := xlsxColumnName(i) + strconv.Itoa(w.row)
+		switch value := value.(type) {
+		case nil:
+			continue
+		case float64:
+			fmt.Fprintf(w.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(value, 'f', -1, 64))
+		case bool:
+			checked := 0
+			if value {
+				checked = 1
+			}
+			fmt.Fprintf(w.sheet, `<c r="%s" t="b"><v>%d</v></c>`, ref, checked)
+		default:
+			text := cellText(value)
+			if utf8.RuneCountInString(text) > xlsxMaxCellLength {
+				text = string([]rune(text)[:xlsxMaxCellLength])
+			}
+			fmt.Fprintf(w.sheet, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+			if err := xml.EscapeText(w.sheet, []byte(text)); err != nil {
+				return err
+			}
+			w.sheet.WriteString(`</t></is></c>`)
+		}
+	}
+	_, err := w.sheet.WriteString(`</row>`)
+	return err
+}
+
+func (w *xlsxExportWriter) close() error {
+	w.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zip.Close()
+}
+
+// xlsxColumnName converts a zero based column index to its spreadsheet letters, eg: 0 is A and 27 is AB
+func xlsxColumnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
@@ -0,0 +1,47 @@
+package responses
+
+import (
+	"api/internal/types"
+	"shared/models"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// verifyFileUploads checks each file field in data references an upload made for that field, returning the upload IDs
+// to attach to the response. uploaderID restricts uploads to a single user and is zero when an organizer edits a response.
+// responseID is zero for a new response, otherwise uploads already attached to it can be kept.
+func verifyFileUploads(c *gin.Context, params *types.RouteParams, form *models.FormStructure, data map[string]interface{}, uploaderID primitive.ObjectID, responseID primitive.ObjectID) ([]primitive.ObjectID, map[string]string) {
+	uploadIDs := []primitive.ObjectID{}
+	fieldErrors := map[string]string{}
+
+	for _, attr := range form.Attrs {
+		if attr.Type != models.FieldTypeFile {
+			continue
+		}
+
+		value, ok := data[attr.Key].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		// The value's format is checked by ValidateFormData
+		uploadID, err := primitive.ObjectIDFromHex(value)
+		if err != nil {
+			fieldErrors[attr.Key] = "must be an uploaded file"
+			continue
+		}
+
+		upload, err := params.MongoService.GetFileUpload(c, uploadID)
+		if err != nil || upload.FormID != form.ID || upload.FieldKey != attr.Key ||
+			(!uploaderID.IsZero() && upload.UserID != uploaderID) ||
+			(!upload.ResponseID.IsZero() && upload.ResponseID != responseID) {
+			fieldErrors[attr.Key] = "must be a file uploaded to this field"
+			continue
+		}
+
+		uploadIDs = append(uploadIDs, uploadID)
+	}
+
+	return uploadIDs, fieldErrors
+}
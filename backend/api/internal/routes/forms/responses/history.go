@@ -0,0 +1,178 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordResponseChange adds the difference between two versions of a response to its history,
+// saving a response without changing anything isn't recorded
+func recordResponseChange(ctx context.Context, params *types.RouteParams, before models.FormResponse, after models.FormResponse, change models.ResponseChange) error {
+	change.Changes = utils.DiffResponses(before, after)
+	if len(change.Changes) == 0 {
+		return nil
+	}
+
+	change.ResponseID = before.ID
+	change.FormID = before.FormID
+	change.PreviousData = before.Data
+	change.PreviousFormVersionID = before.FormVersionID
+	if change.PreviousData == nil {
+		change.PreviousData = map[string]interface{}{}
+	}
+
+	_, err := params.MongoService.RecordResponseChange(ctx, change)
+	return err
+}
+
+// getHistoryResponse reads the response in the route for an organizer, the error response is written when it returns nil.
+// Withdrawn responses are included so organizers can see how they got there.
+func getHistoryResponse(c *gin.Context, params *types.RouteParams) (*models.FormStructure, *models.FormResponse) {
+	authenticatedUser, ok := utils.GetUserFromContext(c, true)
+	if !ok {
+		return nil, nil
+	}
+
+	formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return nil, nil
+	}
+
+	responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+		return nil, nil
+	}
+
+	form, err := params.MongoService.GetForm(c, formID, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+		return nil, nil
+	}
+
+	if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+		return nil, nil
+	}
+
+	responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "formID": formID}, nil)
+	if err != nil || len(responses) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+		return nil, nil
+	}
+
+	return form, &responses[0]
+}
+
+// responseHistoryHandler lists every recorded change to a response, newest first, with the data each one replaced
+func responseHistoryHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_, response := getHistoryResponse(c, params)
+		if response == nil {
+			return
+		}
+
+		changes, err := params.MongoService.ListResponseChanges(c, response.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list response history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"history": changes})
+	}
+}
+
+// restoreResponseHandler puts back the data a change replaced. The restore is saved like any other edit, so it's
+// recorded in the history and fires FieldChange pipelines, but the decision is left as it is.
+// The uploads the data references are attached again, it's refused if any of them has been deleted.
+func restoreResponseHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form, response := getHistoryResponse(c, params)
+		if response == nil {
+			return
+		}
+
+		if response.IsDeleted {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Withdrawn responses can't be restored"})
+			return
+		}
+
+		changeID, err := primitive.ObjectIDFromHex(c.Param("change_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid change ID"})
+			return
+		}
+
+		change, err := params.MongoService.GetResponseChange(c, changeID)
+		if err != nil || change.ResponseID != response.ID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Change does not exist"})
+			return
+		}
+
+		restored := *response
+		restored.Data = change.PreviousData
+		restored.FormVersionID = change.PreviousFormVersionID
+
+		schema, err := restoredSchema(c, params, form, change.PreviousFormVersionID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore response"})
+			return
+		}
+
+		// Uploads the data stopped referencing were detached and may have been cleaned up since
+		uploadIDs, fieldErrors := verifyFileUploads(c, params, schema, restored.Data, primitive.NilObjectID, response.ID)
+		if len(fieldErrors) > 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Files this data references no longer exist, it can't be restored", "fieldErrors": fieldErrors})
+			return
+		}
+
+		restore := models.ResponseChange{ChangedBy: authenticatedUser.ID, Source: models.ResponseChangeAPI, Action: "restore", RestoredFrom: change.ID}
+		if err := saveResponseChange(c, params, form, *response, restored, restore); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore response"})
+			return
+		}
+
+		if _, err := params.MongoService.AttachFileUploads(c, response.ID, uploadIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore response"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": response.ID, "data": restored.Data})
+	}
+}
+
+// restoredSchema is the form with the fields of the version restored data was saved against, so the file fields of an
+// earlier version are checked too. The current form is used when the version is the current one or wasn't recorded.
+func restoredSchema(c *gin.Context, params *types.RouteParams, form *models.FormStructure, versionID primitive.ObjectID) (*models.FormStructure, error) {
+	if versionID.IsZero() || versionID == form.VersionID {
+		return form, nil
+	}
+
+	versions, err := params.MongoService.ListFormVersions(c, form.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range versions {
+		if version.ID == versionID {
+			schema := *form
+			schema.Attrs = version.Attrs
+			return &schema, nil
+		}
+	}
+	return form, nil
+}
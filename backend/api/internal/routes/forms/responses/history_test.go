@@ -0,0 +1,101 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"shared/models"
+	"shared/mongodb"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// restoreStore has a response whose earlier data referenced an upload, which may since have been cleaned up
+type restoreStore struct {
+	*mongodb.MockMongoService
+	organizerID primitive.ObjectID
+	form        models.FormStructure
+	response    models.FormResponse
+	change      models.ResponseChange
+	upload      *models.FileUpload
+
+	saved    bool
+	attached []primitive.ObjectID
+}
+
+func (s *restoreStore) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
+	return &s.form, nil
+}
+
+func (s *restoreStore) GetEvent(ctx *gin.Context, eventID primitive.ObjectID) (*models.Event, error) {
+	return &models.Event{ID: eventID, OrganizerIDs: []primitive.ObjectID{s.organizerID}}, nil
+}
+
+func (s *restoreStore) ListResponses(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.FormResponse, error) {
+	return []models.FormResponse{s.response}, nil
+}
+
+func (s *restoreStore) GetResponseChange(ctx context.Context, changeID primitive.ObjectID) (*models.ResponseChange, error) {
+	return &s.change, nil
+}
+
+func (s *restoreStore) GetFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*models.FileUpload, error) {
+	if s.upload == nil || s.upload.ID != uploadID {
+		return nil, mongo.ErrNoDocuments
+	}
+	return s.upload, nil
+}
+
+func (s *restoreStore) UpdateResponse(ctx context.Context, response models.FormResponse, responseID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	s.saved = true
+	return &mongo.UpdateResult{MatchedCount: 1}, nil
+}
+
+func (s *restoreStore) AttachFileUploads(ctx context.Context, responseID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error) {
+	s.attached = uploadIDs
+	return &mongo.UpdateResult{}, nil
+}
+
+func TestRestoreResponseReattachesUploads(t *testing.T) {
+	formID, uploadID := primitive.NewObjectID(), primitive.NewObjectID()
+	newStore := func(upload *models.FileUpload) *restoreStore {
+		responseID := primitive.NewObjectID()
+		return &restoreStore{
+			MockMongoService: mongodb.NewMockMongoService(),
+			organizerID:      primitive.NewObjectID(),
+			form:             models.FormStructure{ID: formID, Attrs: []models.FormField{{Key: "cv", Type: models.FieldTypeFile}}},
+			response:         models.FormResponse{ID: responseID, FormID: formID, Data: map[string]interface{}{}},
+			change:           models.ResponseChange{ID: primitive.NewObjectID(), ResponseID: responseID, PreviousData: map[string]interface{}{"cv": uploadID.Hex()}},
+			upload:           upload,
+		}
+	}
+	restore := func(store *restoreStore) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		c.Params = gin.Params{{Key: "form_id", Value: formID.Hex()}, {Key: "response_id", Value: store.response.ID.Hex()}, {Key: "change_id", Value: store.change.ID.Hex()}}
+		c.Set("user", &models.User{ID: store.organizerID})
+		restoreResponseHandler(&types.RouteParams{MongoService: store})(c)
+		return w.Code
+	}
+
+	// The detached upload still exists, so it's attached to the response again
+	store := newStore(&models.FileUpload{ID: uploadID, FormID: formID, FieldKey: "cv"})
+	require.Equal(t, http.StatusOK, restore(store))
+	assert.True(t, store.saved)
+	assert.Equal(t, []primitive.ObjectID{uploadID}, store.attached)
+
+	// The upload was cleaned up, restoring would reference a file that's gone
+	store = newStore(nil)
+	assert.Equal(t, http.StatusConflict, restore(store))
+	assert.False(t, store.saved)
+	assert.Nil(t, store.attached)
+}
@@ -0,0 +1,326 @@
+package responses
+
+import (
+	"api/internal/types"
+	"fmt"
+	"net/http"
+	"regexp"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxResponseTags limits how many tags a single response can have
+const maxResponseTags = 20
+
+// tagPattern is what a tag looks like once it's normalized, eg: needs-travel
+var tagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,39}$`)
+
+type responseTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type responseNoteRequest struct {
+	Body string `json:"body" validate:"required,max=5000"`
+}
+
+// normalizeTags lowercases tags and joins their words with dashes, so "Sponsor Pick" and "sponsor-pick" are the
+// same tag. The tags are returned sorted without duplicates.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if !tagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("%q is not a valid tag, tags are up to 40 letters, numbers, dashes and underscores", tag)
+		}
+
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) > maxResponseTags {
+		return nil, fmt.Errorf("a response can have at most %d tags", maxResponseTags)
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// tagFilterCondition converts ?filter[tags]= to a condition, eq:<tag> matches responses with the tag and
+// in:<tags> responses with any of them
+func tagFilterCondition(filter string) (bson.M, error) {
+	operator, value, ok := strings.Cut(filter, ":")
+	if !ok || (operator != filterEq && operator != filterIn) {
+		return nil, fmt.Errorf("filter on tags must be eq:<tag> or in:<tags>")
+	}
+
+	tags, err := normalizeTags(strings.Split(value, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return bson.M{"tags": bson.M{"$in": tags}}, nil
+}
+
+// getAnnotatedResponse reads the response in the route for someone who can tag it and leave notes on it: the form's
+// organizers and the reviewers assigned to the response. The error response is written when it returns nil.
+func getAnnotatedResponse(c *gin.Context, params *types.RouteParams, user *models.User) (*models.FormStructure, *models.FormResponse) {
+	form, response := getDecisionResponse(c, params)
+	if response == nil {
+		return nil, nil
+	}
+
+	if mongodb.CanUserModifyForm(c, params.MongoService, user, form.ID, form) {
+		return form, response
+	}
+
+	assigned, err := params.MongoService.ListReviews(c, bson.M{"formID": form.ID, "responseID": response.ID, "reviewerID": user.ID})
+	if err != nil || len(assigned) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this response"})
+		return nil, nil
+	}
+
+	return form, response
+}
+
+// listResponseTagsHandler lists the tags in use on a form's responses, for organizers picking tags to filter by
+func listResponseTagsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+			return
+		}
+
+		tags, err := params.MongoService.ListResponseTags(c, form.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tags"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"tags": tags})
+	}
+}
+
+// setResponseTagsHandler replaces a response's tags. The change is recorded in the response's history and
+// FieldChange pipelines on TagsFieldID run when a tag is added or removed.
+func setResponseTagsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form, response := getAnnotatedResponse(c, params, authenticatedUser)
+		if response == nil {
+			return
+		}
+
+		var req responseTagsRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tags, err := normalizeTags(req.Tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		tagged := *response
+		tagged.Tags = tags
+
+		// Only the tags are written, and only if nobody else changed them since the response was read
+		saved, err := params.MongoService.SetResponseTags(c, response.ID, response.Tags, tags)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tags"})
+			return
+		}
+		if !saved {
+			c.JSON(http.StatusConflict, gin.H{"error": "The tags were changed by someone else, reload the response and try again"})
+			return
+		}
+
+		change := models.ResponseChange{ChangedBy: authenticatedUser.ID, Source: models.ResponseChangeAPI, Action: "tags"}
+		if err := responseChanged(c, params, form, *response, tagged, change); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save tags"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": response.ID, "tags": tags})
+	}
+}
+
+// listResponseNotesHandler lists the notes on a response, oldest first
+func listResponseNotesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		_, response := getAnnotatedResponse(c, params, authenticatedUser)
+		if response == nil {
+			return
+		}
+
+		notes, err := params.MongoService.ListResponseNotes(c, response.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list notes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"notes": notes})
+	}
+}
+
+// createResponseNoteHandler leaves a note on a response
+func createResponseNoteHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form, response := getAnnotatedResponse(c, params, authenticatedUser)
+		if response == nil {
+			return
+		}
+
+		var req responseNoteRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req.Body = strings.TrimSpace(req.Body)
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		note := models.ResponseNote{ResponseID: response.ID, FormID: form.ID, AuthorID: authenticatedUser.ID, Body: req.Body}
+		result, err := params.MongoService.CreateResponseNote(c, note)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save note"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": result.InsertedID})
+	}
+}
+
+// getOwnNote reads the note in the route, notes can only be changed by their author.
+// The error response is written when it returns nil.
+func getOwnNote(c *gin.Context, params *types.RouteParams, user *models.User, response *models.FormResponse) *models.ResponseNote {
+	noteID, err := primitive.ObjectIDFromHex(c.Param("note_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note ID"})
+		return nil
+	}
+
+	note, err := params.MongoService.GetResponseNote(c, noteID)
+	if err != nil || note.ResponseID != response.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Note does not exist"})
+		return nil
+	}
+
+	if note.AuthorID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only change your own notes"})
+		return nil
+	}
+
+	return note
+}
+
+// updateResponseNoteHandler changes the text of a note
+func updateResponseNoteHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		_, response := getAnnotatedResponse(c, params, authenticatedUser)
+		if response == nil {
+			return
+		}
+
+		note := getOwnNote(c, params, authenticatedUser, response)
+		if note == nil {
+			return
+		}
+
+		var req responseNoteRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		req.Body = strings.TrimSpace(req.Body)
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if _, err := params.MongoService.UpdateResponseNote(c, note.ID, req.Body); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save note"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": note.ID})
+	}
+}
+
+// deleteResponseNoteHandler deletes a note
+func deleteResponseNoteHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		_, response := getAnnotatedResponse(c, params, authenticatedUser)
+		if response == nil {
+			return
+		}
+
+		note := getOwnNote(c, params, authenticatedUser, response)
+		if note == nil {
+			return
+		}
+
+		if _, err := params.MongoService.DeleteResponseNote(c, note.ID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete note"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": note.ID})
+	}
+}
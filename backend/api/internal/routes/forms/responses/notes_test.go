@@ -0,0 +1,62 @@
+package responses
+
+import (
+	"net/url"
+	"shared/kafka"
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := normalizeTags([]string{"Sponsor Pick", "needs-travel", " sponsor-pick "})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"needs-travel", "sponsor-pick"}, tags)
+
+	tags, err = normalizeTags(nil)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+
+	for _, invalid := range []string{"", "-dash", "needs/travel", "a-very-long-tag-that-goes-on-past-forty-characters"} {
+		_, err := normalizeTags([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+
+	tooMany := make([]string, maxResponseTags+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+	_, err = normalizeTags(tooMany)
+	assert.Error(t, err)
+}
+
+func TestTagFilterCondition(t *testing.T) {
+	condition, err := tagFilterCondition("in:Needs Travel,sponsor-pick")
+	require.NoError(t, err)
+	assert.Equal(t, bson.M{"tags": bson.M{"$in": []string{"needs-travel", "sponsor-pick"}}}, condition)
+
+	for _, invalid := range []string{"sponsor-pick", "contains:sponsor", "eq:"} {
+		_, err := tagFilterCondition(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	query, err := parseResponseQuery(queryForm, url.Values{"filter[tags]": {"eq:sponsor-pick"}}, false)
+	require.NoError(t, err)
+	assert.Len(t, query.conditions, 1)
+}
+
+func TestTagFieldChange(t *testing.T) {
+	tagged := fieldChangeData(models.FormResponse{Tags: []string{"sponsor-pick"}})
+	untagged := fieldChangeData(models.FormResponse{})
+
+	hasTag := &models.FieldChange{OnFieldID: models.TagsFieldID, Condition: models.FieldChangeCondition{Comparison: models.ComparisonEq, Value: "sponsor-pick"}}
+	assert.True(t, kafka.FieldChangeCheck(hasTag, &tagged))
+	assert.False(t, kafka.FieldChangeCheck(hasTag, &untagged))
+
+	lacksTag := &models.FieldChange{OnFieldID: models.TagsFieldID, Condition: models.FieldChangeCondition{Comparison: models.ComparisonNeq, Value: "sponsor-pick"}}
+	assert.False(t, kafka.FieldChangeCheck(lacksTag, &tagged))
+	assert.True(t, kafka.FieldChangeCheck(lacksTag, &untagged))
+}
@@ -0,0 +1,199 @@
+package responses
+
+import (
+	"api/internal/types"
+	"log"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// listOwnResponsesHandler returns the responses the user has submitted to the form, without internal fields
+func listOwnResponsesHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		responses, err := params.MongoService.ListResponses(c, bson.M{"formID": formID, "userID": authenticatedUser.ID, "isDeleted": bson.M{"$ne": true}}, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		for i := range responses {
+			stripInternalResponseData(form, &responses[i])
+		}
+
+		c.JSON(http.StatusOK, gin.H{"responses": responses, "canEdit": applicantEditsOpen(form)})
+	}
+}
+
+// getFormResponseHandler returns a single response to an organizer, or to the applicant who submitted it
+func getFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+			return
+		}
+
+		form, err := params.MongoService.GetForm(c, formID, true)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+			return
+		}
+
+		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "formID": formID}, nil)
+		if err != nil || len(responses) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+			return
+		}
+		response := responses[0]
+
+		if mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, form) {
+			c.JSON(http.StatusOK, response)
+			return
+		}
+
+		// Applicants don't see responses they've withdrawn
+		if response.UserID != authenticatedUser.ID || response.IsDeleted {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+			return
+		}
+
+		stripInternalResponseData(form, &response)
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// withdrawFormResponseHandler lets applicants withdraw their response, it's soft deleted so organizers keep a record of it
+func withdrawFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+			return
+		}
+
+		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "formID": formID, "isDeleted": bson.M{"$ne": true}}, nil)
+		if err != nil || len(responses) == 0 || responses[0].UserID != authenticatedUser.ID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Response does not exist"})
+			return
+		}
+
+		result, err := params.MongoService.WithdrawResponse(c, responseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw response"})
+			return
+		}
+
+		if result.MatchedCount == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Response has already been withdrawn"})
+			return
+		}
+
+		response := responses[0]
+		withdrawn := response
+		withdrawn.IsDeleted = true
+		change := models.ResponseChange{ChangedBy: authenticatedUser.ID, Source: models.ResponseChangeAPI, Action: "withdraw"}
+		if err := recordResponseChange(c, params, response, withdrawn, change); err != nil {
+			log.Printf("Failed to record the withdrawal of response %s: %v", responseID.Hex(), err)
+		}
+
+		// The withdrawn response no longer takes up a submission, its place goes to the waitlist. Once a decision
+		// has been made, or the place was released, the claim is kept so withdrawing can't be used to submit again.
+		if response.CurrentDecision() != models.DecisionPending || response.Status == models.ResponseReleased {
+			releasePlace(c, params, formID, responseID)
+		} else if err := releaseSubmission(c, params, response, response.HoldsPlace()); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw response"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Response withdrawn successfully"})
+	}
+}
+
+// canApplicantEditResponse checks a non-organizer can edit the response, writing the error response if not
+func canApplicantEditResponse(c *gin.Context, form *models.FormStructure, response *models.FormResponse, user *models.User) bool {
+	if response.UserID != user.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+		return false
+	}
+
+	if !form.AllowResponseEdits {
+		c.JSON(http.StatusForbidden, gin.H{"error": "This form does not allow editing responses"})
+		return false
+	}
+
+	if !applicantEditsOpen(form) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form submissions are closed"})
+		return false
+	}
+
+	return true
+}
+
+// applicantEditsOpen reports whether applicants can currently edit their responses to the form
+func applicantEditsOpen(form *models.FormStructure) bool {
+	if !form.AllowResponseEdits || form.Status != "published" {
+		return false
+	}
+
+	return form.CloseSubmissionsAt.IsZero() || form.CloseSubmissionsAt.After(time.Now())
+}
+
+// stripInternalResponseData removes the values of organizer only fields, the response's review scores and tags, and
+// who decided on it before a response is shown to its applicant
+func stripInternalResponseData(form *models.FormStructure, response *models.FormResponse) {
+	response.Review = nil
+	response.Tags = nil
+	response.DecidedBy = primitive.NilObjectID
+
+	for _, attr := range form.Attrs {
+		if attr.IsInternal {
+			delete(response.Data, attr.Key)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"shared/models"
+	"shared/mongodb"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestStripInternalResponseData(t *testing.T) {
+	form := &models.FormStructure{Attrs: []models.FormField{
+		{Key: "name", Type: models.FieldTypeText},
+		{Key: "notes", Type: models.FieldTypeTextArea, IsInternal: true},
+	}}
+	response := models.FormResponse{
+		Data:      map[string]interface{}{"name": "Ada", "notes": "Strong"},
+		Decision:  models.DecisionAccepted,
+		DecidedAt: time.Now(),
+		DecidedBy: primitive.NewObjectID(),
+		Review:    &models.ResponseReviewSummary{Score: 82.5, Count: 3},
+		Tags:      []string{"sponsor-pick"},
+	}
+
+	stripInternalResponseData(form, &response)
+
+	view, err := json.Marshal(response)
+	require.NoError(t, err)
+
+	var fields map[string]interface{}
+	require.NoError(t, json.Unmarshal(view, &fields))
+	assert.NotContains(t, fields, "review")
+	assert.NotContains(t, fields, "tags")
+	assert.Equal(t, primitive.NilObjectID.Hex(), fields["decidedBy"])
+	assert.Equal(t, map[string]interface{}{"name": "Ada"}, fields["data"])
+
+	// Applicants still see their decision so they can confirm or decline
+	assert.Equal(t, "accepted", fields["decision"])
+}
+
+// claimStore holds one applicant's response and their claim on the form, like form_submitters
+type claimStore struct {
+	*mongodb.MockMongoService
+	form     models.FormStructure
+	response models.FormResponse
+	claimed  bool
+}
+
+func (s *claimStore) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
+	return &s.form, nil
+}
+
+func (s *claimStore) ListResponses(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.FormResponse, error) {
+	if s.response.IsDeleted {
+		return nil, nil
+	}
+	return []models.FormResponse{s.response}, nil
+}
+
+func (s *claimStore) WithdrawResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	s.response.IsDeleted = true
+	return &mongo.UpdateResult{MatchedCount: 1}, nil
+}
+
+func (s *claimStore) ReleaseFormSubmission(ctx context.Context, response models.FormResponse, heldPlace bool) (*models.FormResponse, error) {
+	s.claimed = false
+	return nil, nil
+}
+
+func (s *claimStore) ReserveFormSubmission(ctx context.Context, form models.FormStructure, response models.FormResponse) (int, error) {
+	if s.claimed {
+		return 0, mongodb.ErrAlreadySubmitted
+	}
+	s.claimed = true
+	return 0, nil
+}
+
+func (s *claimStore) CreateResponse(ctx context.Context, submission models.FormResponse) (*mongo.InsertOneResult, error) {
+	return &mongo.InsertOneResult{InsertedID: primitive.NewObjectID()}, nil
+}
+
+func TestWithdrawKeepsClaimAfterDecision(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		decision models.Decision
+		status   models.ResponseStatus
+		claimed  bool
+	}{
+		{"rejected", models.DecisionRejected, models.ResponseReleased, true},
+		{"accepted", models.DecisionAccepted, models.ResponseSubmitted, true},
+		{"pending", "", models.ResponseSubmitted, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			user := &models.User{ID: primitive.NewObjectID(), Email: "ada@example.com"}
+			store := &claimStore{
+				MockMongoService: mongodb.NewMockMongoService(),
+				form:             models.FormStructure{ID: primitive.NewObjectID(), Status: "published"},
+				claimed:          true,
+			}
+			store.response = models.FormResponse{ID: primitive.NewObjectID(), FormID: store.form.ID, UserID: user.ID, Decision: tc.decision, Status: tc.status}
+			params := &types.RouteParams{MongoService: store}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodDelete, "/", nil)
+			c.Params = gin.Params{{Key: "form_id", Value: store.form.ID.Hex()}, {Key: "response_id", Value: store.response.ID.Hex()}}
+			c.Set("user", user)
+			withdrawFormResponseHandler(params)(c)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tc.claimed, store.claimed)
+
+			// Submitting again only gets past the claim when the withdrawn response had no decision
+			w = httptest.NewRecorder()
+			c, _ = gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			c.Set("user", user)
+			_, ok := submitResponse(c, params, models.FormResponse{FormID: store.form.ID, UserID: user.ID, Data: map[string]interface{}{}})
+			assert.Equal(t, !tc.claimed, ok)
+			if tc.claimed {
+				assert.Equal(t, http.StatusConflict, w.Code)
+			}
+		})
+	}
+}
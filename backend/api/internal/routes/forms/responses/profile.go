@@ -0,0 +1,39 @@
+package responses
+
+import (
+	"api/internal/types"
+	"log"
+	"shared/models"
+	"shared/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// lockProfileFields loads the applicant's profile when the form prefills from it and puts the profile's values
+// in the form's locked fields. The profile is nil when the form doesn't use it.
+func lockProfileFields(c *gin.Context, params *types.RouteParams, form *models.FormStructure, data map[string]interface{}, userID primitive.ObjectID) (*models.User, error) {
+	if !utils.FormUsesProfile(form) {
+		return nil, nil
+	}
+
+	profile, err := params.MongoService.FindUserByID(c, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LockPrefilledFields(form, data, profile)
+	return profile, nil
+}
+
+// writeBackProfileFields saves the values of the form's write back fields to the applicant's profile.
+// The response is already saved so a failure is only logged.
+func writeBackProfileFields(c *gin.Context, params *types.RouteParams, form *models.FormStructure, data map[string]interface{}, profile *models.User) {
+	if profile == nil || !utils.ApplyProfileWriteBack(form, data, profile) {
+		return
+	}
+
+	if err := params.MongoService.UpdateUserDetails(c, profile.ID, *profile); err != nil {
+		log.Printf("Failed to write form %s back to the profile of user %s: %v", form.ID.Hex(), profile.ID.Hex(), err)
+	}
+}
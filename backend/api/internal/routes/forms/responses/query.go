@@ -0,0 +1,367 @@
+package responses
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"shared/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultResponsePageSize = 100
+	maxResponsePageSize     = 1000
+
+	// sortSubmittedAt sorts responses by when they were submitted, it's the default
+	sortSubmittedAt = "createdAt"
+
+	// sortReviewScore sorts responses by their average review score, responses without reviews sort like unanswered fields
+	sortReviewScore    = "reviewScore"
+	reviewScoreSortKey = "review.score"
+)
+
+// Filter operators, a filter is written as ?filter[<field key>]=<operator>:<value>
+const (
+	filterEq       = "eq"       // the answer is the value, or a multi-select answer includes it
+	filterIn       = "in"       // the answer is one of the comma separated values
+	filterRange    = "range"    // the answer is between min,max inclusive, either can be left out
+	filterContains = "contains" // the text answer contains the value, ignoring case
+)
+
+// responseQuery is the filters, search, sort and page an organizer asked for when listing responses
+type responseQuery struct {
+	conditions []bson.M
+	sortKey    string
+	descending bool
+	limit      int
+	after      *responseCursor
+}
+
+// responseCursor is the position of the last response on a page, the next page starts after it
+type responseCursor struct {
+	Value interface{}        `json:"v"`
+	ID    primitive.ObjectID `json:"id"`
+}
+
+// parseResponseQuery reads the response listing query parameters:
+//   - ?filter[<field key>]=<operator>:<value>, repeated filters must all match. ?filter[decision]= and
+//     ?filter[tags]= take eq or in.
+//   - ?search= matches responses with a text answer containing it
+//   - ?sort=createdAt, reviewScore or a field key, and ?order=asc or desc
+//   - ?limit= and ?cursor=, the cursor is the nextCursor of the previous page
+//
+// Exports aren't paged, they ignore the limit and cursor and list every matching response.
+func parseResponseQuery(form *models.FormStructure, query url.Values, paged bool) (*responseQuery, error) {
+	fields := map[string]models.FormField{}
+	for _, field := range form.Attrs {
+		fields[field.Key] = field
+	}
+
+	q := &responseQuery{sortKey: sortSubmittedAt}
+
+	for param, values := range query {
+		if !strings.HasPrefix(param, "filter[") || !strings.HasSuffix(param, "]") {
+			continue
+		}
+
+		key := strings.TrimSuffix(strings.TrimPrefix(param, "filter["), "]")
+		if key == models.DecisionFieldID {
+			for _, value := range values {
+				condition, err := decisionFilterCondition(value)
+				if err != nil {
+					return nil, err
+				}
+				q.conditions = append(q.conditions, condition)
+			}
+			continue
+		}
+
+		if key == models.TagsFieldID {
+			for _, value := range values {
+				condition, err := tagFilterCondition(value)
+				if err != nil {
+					return nil, err
+				}
+				q.conditions = append(q.conditions, condition)
+			}
+			continue
+		}
+
+		field, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("can't filter by unknown field %s", key)
+		}
+
+		for _, value := range values {
+			condition, err := fieldFilterCondition(field, value)
+			if err != nil {
+				return nil, err
+			}
+			q.conditions = append(q.conditions, condition)
+		}
+	}
+
+	if search := strings.TrimSpace(query.Get("search")); search != "" {
+		q.conditions = append(q.conditions, searchCondition(form, search))
+	}
+
+	if sort := query.Get("sort"); sort == sortReviewScore {
+		q.sortKey = reviewScoreSortKey
+	} else if sort != "" && sort != sortSubmittedAt {
+		field, ok := fields[sort]
+		if !ok || !isSortableField(field) {
+			return nil, fmt.Errorf("can't sort by %s", sort)
+		}
+		q.sortKey = "data." + field.Key
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.descending = true
+	default:
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+
+	if !paged {
+		return q, nil
+	}
+
+	q.limit = defaultResponsePageSize
+	if limit := query.Get("limit"); limit != "" {
+		number, err := strconv.Atoi(limit)
+		if err != nil || number < 1 || number > maxResponsePageSize {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxResponsePageSize)
+		}
+		q.limit = number
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := decodeResponseCursor(cursor, q.sortKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		q.after = after
+		q.conditions = append(q.conditions, q.afterCondition())
+	}
+
+	return q, nil
+}
+
+// fieldFilterCondition converts a single ?filter[] value into a condition on the field's answers
+func fieldFilterCondition(field models.FormField, filter string) (bson.M, error) {
+	operator, value, ok := strings.Cut(filter, ":")
+	if !ok {
+		return nil, fmt.Errorf("filter on %s must be written as operator:value", field.Key)
+	}
+
+	key := "data." + field.Key
+	switch operator {
+	case filterEq:
+		converted, err := filterValue(field, value)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{key: converted}, nil
+
+	case filterIn:
+		var values []interface{}
+		for _, item := range strings.Split(value, ",") {
+			converted, err := filterValue(field, item)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, converted)
+		}
+		return bson.M{key: bson.M{"$in": values}}, nil
+
+	case filterRange:
+		min, max, ok := strings.Cut(value, ",")
+		if !ok {
+			return nil, fmt.Errorf("range filter on %s must be written as range:min,max", field.Key)
+		}
+
+		bounds := bson.M{}
+		if min != "" {
+			converted, err := filterValue(field, min)
+			if err != nil {
+				return nil, err
+			}
+			bounds["$gte"] = converted
+		}
+		if max != "" {
+			converted, err := filterValue(field, max)
+			if err != nil {
+				return nil, err
+			}
+			bounds["$lte"] = converted
+		}
+		if len(bounds) == 0 {
+			return nil, fmt.Errorf("range filter on %s needs a min or a max", field.Key)
+		}
+		return bson.M{key: bounds}, nil
+
+	case filterContains:
+		if !isTextField(field) {
+			return nil, fmt.Errorf("contains can only filter text fields")
+		}
+		return bson.M{key: containsRegex(value)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown filter operator %s", operator)
+}
+
+// filterValue converts a filter value to the type the field's answers are stored as
+func filterValue(field models.FormField, value string) (interface{}, error) {
+	switch field.Type {
+	case models.FieldTypeNumber:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be filtered by a number", field.Key)
+		}
+		return number, nil
+	case models.FieldTypeCheckbox:
+		checked, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be filtered by true or false", field.Key)
+		}
+		return checked, nil
+	}
+	return value, nil
+}
+
+// searchCondition matches responses where any text answer contains the search
+func searchCondition(form *models.FormStructure, search string) bson.M {
+	var matches []bson.M
+	for _, field := range form.Attrs {
+		if isTextField(field) {
+			matches = append(matches, bson.M{"data." + field.Key: containsRegex(search)})
+		}
+	}
+
+	// Nothing can match a form without text fields
+	if len(matches) == 0 {
+		return bson.M{"_id": bson.M{"$exists": false}}
+	}
+	return bson.M{"$or": matches}
+}
+
+func containsRegex(value string) primitive.Regex {
+	return primitive.Regex{Pattern: regexp.QuoteMeta(value), Options: "i"}
+}
+
+// isTextField reports whether the field's answers are text, or lists of text for multi-selects
+func isTextField(field models.FormField) bool {
+	switch field.Type {
+	case models.FieldTypeText, models.FieldTypeTextArea, models.FieldTypeRichText, models.FieldTypeTelephone,
+		models.FieldTypeSelect, models.FieldTypeRadio, models.FieldTypeCustomSelect,
+		models.FieldTypeMultiSelect, models.FieldTypeCustomMultiSelect:
+		return true
+	}
+	return false
+}
+
+// isSortableField reports whether the field has a single value that responses can be ordered by
+func isSortableField(field models.FormField) bool {
+	switch field.Type {
+	case models.FieldTypeMultiSelect, models.FieldTypeCustomMultiSelect, models.FieldTypeAddress, models.FieldTypeFile:
+		return false
+	}
+	return true
+}
+
+// findOptions sorts by the sort key with the ID breaking ties, when paged one extra response is fetched to tell if there's another page
+func (q *responseQuery) findOptions() *options.FindOptions {
+	direction := 1
+	if q.descending {
+		direction = -1
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: q.sortKey, Value: direction}, {Key: "_id", Value: direction}})
+	if q.limit > 0 {
+		opts.SetLimit(int64(q.limit + 1))
+	}
+	return opts
+}
+
+// afterCondition matches the responses that sort after the cursor. Responses without an answer sort
+// before every answer, so they come first in ascending order and last in descending order.
+func (q *responseQuery) afterCondition() bson.M {
+	value, id := q.after.Value, q.after.ID
+	switch {
+	case !q.descending && value == nil:
+		return bson.M{"$or": []bson.M{
+			{q.sortKey: nil, "_id": bson.M{"$gt": id}},
+			{q.sortKey: bson.M{"$ne": nil}},
+		}}
+	case !q.descending:
+		return bson.M{"$or": []bson.M{
+			{q.sortKey: bson.M{"$gt": value}},
+			{q.sortKey: value, "_id": bson.M{"$gt": id}},
+		}}
+	case value == nil:
+		return bson.M{q.sortKey: nil, "_id": bson.M{"$lt": id}}
+	default:
+		return bson.M{"$or": []bson.M{
+			{q.sortKey: bson.M{"$lt": value}},
+			{q.sortKey: value, "_id": bson.M{"$lt": id}},
+			{q.sortKey: nil},
+		}}
+	}
+}
+
+// page trims the extra response fetched by findOptions, returning the cursor for the next page or an empty string on the last page
+func (q *responseQuery) page(responses []models.FormResponse) ([]models.FormResponse, string) {
+	if q.limit == 0 || len(responses) <= q.limit {
+		return responses, ""
+	}
+
+	responses = responses[:q.limit]
+	last := responses[len(responses)-1]
+
+	cursor := responseCursor{ID: last.ID}
+	if q.sortKey == sortSubmittedAt {
+		cursor.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	} else if q.sortKey == reviewScoreSortKey {
+		if last.Review != nil {
+			cursor.Value = last.Review.Score
+		}
+	} else {
+		cursor.Value = last.Data[strings.TrimPrefix(q.sortKey, "data.")]
+	}
+
+	encoded, _ := json.Marshal(cursor)
+	return responses, base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeResponseCursor(cursor string, sortKey string) (*responseCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var after responseCursor
+	if err := json.Unmarshal(decoded, &after); err != nil {
+		return nil, err
+	}
+
+	if sortKey == sortSubmittedAt {
+		value, ok := after.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid submission time")
+		}
+		if after.Value, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return &after, nil
+}
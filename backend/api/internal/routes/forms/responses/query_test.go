@@ -0,0 +1,105 @@
+package responses
+
+import (
+	"net/url"
+	"shared/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var queryForm = &models.FormStructure{Attrs: []models.FormField{
+	{Key: "name", Type: models.FieldTypeText},
+	{Key: "age", Type: models.FieldTypeNumber},
+	{Key: "adult", Type: models.FieldTypeCheckbox},
+	{Key: "tracks", Type: models.FieldTypeMultiSelect},
+}}
+
+func TestParseResponseQueryFilters(t *testing.T) {
+	query, err := parseResponseQuery(queryForm, url.Values{
+		"filter[age]":    {"range:18,"},
+		"filter[adult]":  {"eq:true"},
+		"filter[tracks]": {"in:ai,web"},
+		"search":         {"a.b"},
+	}, true)
+	require.NoError(t, err)
+
+	assert.Contains(t, query.conditions, bson.M{"data.age": bson.M{"$gte": 18.0}})
+	assert.Contains(t, query.conditions, bson.M{"data.adult": true})
+	assert.Contains(t, query.conditions, bson.M{"data.tracks": bson.M{"$in": []interface{}{"ai", "web"}}})
+	assert.Contains(t, query.conditions, bson.M{"$or": []bson.M{
+		{"data.name": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+		{"data.tracks": primitive.Regex{Pattern: `a\.b`, Options: "i"}},
+	}})
+	assert.Equal(t, sortSubmittedAt, query.sortKey)
+	assert.Equal(t, defaultResponsePageSize, query.limit)
+
+	invalid := []url.Values{
+		{"filter[unknown]": {"eq:x"}},
+		{"filter[age]": {"18"}},
+		{"filter[age]": {"eq:old"}},
+		{"filter[age]": {"range:,"}},
+		{"filter[age]": {"contains:1"}},
+		{"filter[name]": {"like:x"}},
+		{"sort": {"tracks"}},
+		{"order": {"up"}},
+		{"limit": {"0"}},
+		{"cursor": {"nope"}},
+	}
+	for _, values := range invalid {
+		_, err := parseResponseQuery(queryForm, values, true)
+		assert.Error(t, err, "%v", values)
+	}
+}
+
+func TestResponseQueryPaging(t *testing.T) {
+	query, err := parseResponseQuery(queryForm, url.Values{"limit": {"2"}}, true)
+	require.NoError(t, err)
+
+	createdAt := time.Date(2026, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	responses := []models.FormResponse{
+		{ID: primitive.NewObjectID()},
+		{ID: primitive.NewObjectID(), CreatedAt: createdAt},
+		{ID: primitive.NewObjectID()},
+	}
+
+	page, cursor := query.page(responses)
+	assert.Len(t, page, 2)
+	require.NotEmpty(t, cursor)
+
+	next, err := parseResponseQuery(queryForm, url.Values{"limit": {"2"}, "cursor": {cursor}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, responses[1].ID, next.after.ID)
+	assert.Equal(t, createdAt, next.after.Value)
+
+	_, cursor = query.page(responses[:2])
+	assert.Empty(t, cursor)
+
+	query, err = parseResponseQuery(queryForm, url.Values{"sort": {"reviewScore"}, "limit": {"1"}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, reviewScoreSortKey, query.sortKey)
+
+	_, cursor = query.page([]models.FormResponse{{ID: primitive.NewObjectID(), Review: &models.ResponseReviewSummary{Score: 87.5}}, {}})
+	next, err = parseResponseQuery(queryForm, url.Values{"sort": {"reviewScore"}, "cursor": {cursor}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 87.5, next.after.Value)
+}
+
+func TestResponseQueryAfterCondition(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	query := &responseQuery{sortKey: "data.age", after: &responseCursor{Value: 20.0, ID: id}}
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"data.age": bson.M{"$gt": 20.0}},
+		{"data.age": 20.0, "_id": bson.M{"$gt": id}},
+	}}, query.afterCondition())
+
+	// Responses without an answer come last in descending order
+	query.descending = true
+	query.after.Value = nil
+	assert.Equal(t, bson.M{"data.age": nil, "_id": bson.M{"$lt": id}}, query.afterCondition())
+}
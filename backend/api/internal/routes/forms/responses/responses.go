@@ -4,7 +4,7 @@ import (
 	"api/internal/helpers"
 	"api/internal/middlewares"
 	"api/internal/types"
-	"encoding/csv"
+	"context"
 	"fmt"
 	"net/http"
 	"shared/kafka"
@@ -22,9 +22,38 @@ import (
 func RegisterFormResponsesRoutes(r *gin.RouterGroup, params *types.RouteParams) {
 	r.POST("", middlewares.JWTAuthMiddleware(), submitFormHandler(params))
 	r.GET("", middlewares.JWTAuthMiddleware(), listFormResponsesHandler(params))
-	r.GET("csv", middlewares.JWTAuthMiddleware(), downloadFormResponsesAsCSVHandler(params))
-
+	r.GET("export", middlewares.JWTAuthMiddleware(), exportResponsesHandler(params))
+	r.GET("csv", middlewares.JWTAuthMiddleware(), exportResponsesHandler(params))
+	r.GET("analytics", middlewares.JWTAuthMiddleware(), formAnalyticsHandler(params))
+
+	r.GET("draft", middlewares.JWTAuthMiddleware(), getResponseDraftHandler(params))
+	r.PUT("draft", middlewares.JWTAuthMiddleware(), saveResponseDraftHandler(params))
+	r.DELETE("draft", middlewares.JWTAuthMiddleware(), deleteResponseDraftHandler(params))
+	r.POST("draft/submit", middlewares.JWTAuthMiddleware(), submitResponseDraftHandler(params))
+
+	// Anonymous responses are confirmed from a link in an email so neither route requires a login
+	r.POST("anonymous", submitAnonymousResponseHandler(params))
+	r.GET("anonymous/confirm", confirmAnonymousResponseHandler(params))
+
+	r.POST("decisions", middlewares.JWTAuthMiddleware(), bulkDecisionHandler(params))
+	r.PUT(":response_id/decision", middlewares.JWTAuthMiddleware(), setDecisionHandler(params))
+	r.POST(":response_id/confirm", middlewares.JWTAuthMiddleware(), respondToDecisionHandler(params, models.DecisionConfirmed))
+	r.POST(":response_id/decline", middlewares.JWTAuthMiddleware(), respondToDecisionHandler(params, models.DecisionDeclined))
+
+	r.GET("tags", middlewares.JWTAuthMiddleware(), listResponseTagsHandler(params))
+	r.PUT(":response_id/tags", middlewares.JWTAuthMiddleware(), setResponseTagsHandler(params))
+	r.GET(":response_id/notes", middlewares.JWTAuthMiddleware(), listResponseNotesHandler(params))
+	r.POST(":response_id/notes", middlewares.JWTAuthMiddleware(), createResponseNoteHandler(params))
+	r.PUT(":response_id/notes/:note_id", middlewares.JWTAuthMiddleware(), updateResponseNoteHandler(params))
+	r.DELETE(":response_id/notes/:note_id", middlewares.JWTAuthMiddleware(), deleteResponseNoteHandler(params))
+
+	r.GET(":response_id/history", middlewares.JWTAuthMiddleware(), responseHistoryHandler(params))
+	r.POST(":response_id/history/:change_id/restore", middlewares.JWTAuthMiddleware(), restoreResponseHandler(params))
+
+	r.GET("mine", middlewares.JWTAuthMiddleware(), listOwnResponsesHandler(params))
+	r.GET(":response_id", middlewares.JWTAuthMiddleware(), getFormResponseHandler(params))
 	r.PUT(":response_id", middlewares.JWTAuthMiddleware(), updateFormResponseHandler(params))
+	r.DELETE(":response_id", middlewares.JWTAuthMiddleware(), withdrawFormResponseHandler(params))
 }
 
 func submitFormHandler(params *types.RouteParams) gin.HandlerFunc {
@@ -46,124 +75,199 @@ func submitFormHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		req := models.FormResponse{
-			FormID:    formID,
-			Data:      formData,
-			CreatedAt: time.Now(),
-		}
-		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+		response, ok := submitResponse(c, params, models.FormResponse{FormID: formID, Data: formData, UserID: authenticatedUser.ID})
+		if !ok {
 			return
 		}
 
-		form, err := params.MongoService.GetForm(c, formID, true)
-		if err != nil && form != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
-			return
-		}
+		c.JSON(http.StatusOK, submissionResult(response))
+	}
+}
 
-		if form.Status != "published" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not published, if you believe this is an error message the event admins"})
-			return
-		}
+// submitResponse validates the data as a final submission to the form, triggers its pipelines and saves the response.
+// If the form is full and has a waitlist the response is saved as waitlisted instead.
+// req has the form, data and either the submitting user or the confirmed email of an anonymous submission.
+// The error response is written when it returns false.
+func submitResponse(c *gin.Context, params *types.RouteParams, req models.FormResponse) (*models.FormResponse, bool) {
+	formID := req.FormID
+	formData := req.Data
+	req.CreatedAt = time.Now()
+	if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+		return nil, false
+	}
 
-		if !form.CloseSubmissionsAt.IsZero() && form.CloseSubmissionsAt.Before(time.Now()) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Form submissions are closed"})
-			return
+	// Loaded with its access rules to check the user can submit it, the form isn't returned
+	form, err := params.MongoService.GetForm(c, formID, false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+		return nil, false
+	}
+
+	if form.Status != "published" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form is not published, if you believe this is an error message the event admins"})
+		return nil, false
+	}
+
+	if !form.CloseSubmissionsAt.IsZero() && form.CloseSubmissionsAt.Before(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form submissions are closed"})
+		return nil, false
+	}
+
+	if !form.OpenSubmissionsAt.IsZero() && form.OpenSubmissionsAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form submissions are not open yet"})
+		return nil, false
+	}
+
+	// Anonymous applicants have no profile, their locked fields are filled in like any other
+	var profile *models.User
+	if req.AnonymousEmail == "" {
+		if profile, err = lockProfileFields(c, params, form, formData, req.UserID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return nil, false
 		}
+	}
 
-		if !form.OpenSubmissionsAt.IsZero() && form.OpenSubmissionsAt.After(time.Now()) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Form submissions are not open yet"})
-			return
+	if fieldErrors := utils.ValidateFormData(form, formData, false); len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": utils.FormDataErrorMessage(form, fieldErrors), "fieldErrors": fieldErrors})
+		return nil, false
+	}
+	utils.DropHiddenFormFields(form, formData)
+
+	// Check which of the form's access rules lets the user submit it, this is recorded on the response.
+	// Forms allowing anonymous responses can't be restricted or have file fields, see utils.ValidateFormStructure
+	uploadIDs := []primitive.ObjectID{}
+	var grant *models.FormAccessGrant
+	if req.AnonymousEmail != "" {
+		if !form.AllowAnonymous {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not accept anonymous responses"})
+			return nil, false
+		}
+		req.AnonymousEmail = strings.ToLower(req.AnonymousEmail)
+		grant = &models.FormAccessGrant{Rule: models.FormAccessAnonymous, Email: req.AnonymousEmail}
+	} else {
+		var fieldErrors map[string]string
+		uploadIDs, fieldErrors = verifyFileUploads(c, params, form, formData, req.UserID, primitive.NilObjectID)
+		if len(fieldErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.FormDataErrorMessage(form, fieldErrors), "fieldErrors": fieldErrors})
+			return nil, false
+		}
+
+		var restrictMessage string
+		grant, restrictMessage = mongodb.CheckFormAccess(c, params.MongoService, form)
+		if grant == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
+			return nil, false
 		}
+	}
+	req.AccessGrant = grant
+
+	// Claim the submission atomically so concurrent submissions can't both get the last slot,
+	// it's given back if anything below fails
+	waitlistPosition, err := params.MongoService.ReserveFormSubmission(c, *form, req)
+	if err != nil {
+		switch err {
+		case mongodb.ErrFormFull:
+			c.JSON(http.StatusConflict, gin.H{"error": "Form has reached maximum number of submissions"})
+		case mongodb.ErrAlreadySubmitted:
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already submitted this form"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return nil, false
+	}
 
-		// Check if form has reached max submissions
-		var submissions []models.FormResponse
-		if form.MaxSubmissions > 0 {
-			submissions, err = params.MongoService.ListResponses(c, bson.M{"formID": formID})
+	// Invites are used up once the submission is claimed so a full form doesn't waste a use
+	if grant.Rule == models.FormAccessInvite {
+		used, err := params.MongoService.UseFormInvite(c, grant.InviteID)
+		if err != nil || !used {
+			releaseSubmission(c, params, req, waitlistPosition == 0)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-				return
-			}
-
-			if len(submissions) >= form.MaxSubmissions {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "Form has reached maximum number of submissions"})
-				return
+			} else {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "This invite link has already been used"})
 			}
+			return nil, false
 		}
+	}
 
-		if !form.AllowMultipleSubmissions {
-			if submissions == nil {
-				submissions, err = params.MongoService.ListResponses(c, bson.M{"formID": formID, "userID": authenticatedUser.ID})
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-					return
-				}
-			}
+	req.Status = models.ResponseSubmitted
+	if waitlistPosition > 0 {
+		req.Status = models.ResponseWaitlisted
+		req.WaitlistPosition = waitlistPosition
+	}
 
-			// TODO: Test efficiency of this vs just potentially re-querying the database
-			// my guess is this is more efficient if both max and allow multiple submissions are false
-			// because probably less than 1000 submissions per form
-			for _, submission := range submissions {
-				if submission.UserID == authenticatedUser.ID {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "You have already submitted this form"})
-					return
-				}
-			}
+	responseID, err := saveReservedResponse(c, params, form, req)
+	if err != nil {
+		releaseSubmission(c, params, req, !req.IsWaitlisted())
+		if grant.Rule == models.FormAccessInvite {
+			params.MongoService.ReleaseFormInvite(c, grant.InviteID)
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, false
+	}
+	req.ID = responseID
 
-		// If the form is restricted, check if the user is in the whitelist
-		if form.IsRestricted {
-			allowed, restrictMessage := mongodb.IsUserEmailInWhitelist(c, form.AllowedSubmitters)
-			if !allowed {
-				c.JSON(http.StatusUnauthorized, gin.H{"error": restrictMessage})
-				return
-			}
-		}
+	if _, err := params.MongoService.AttachFileUploads(c, responseID, uploadIDs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, false
+	}
 
-		// TODO: We should do this in a transaction
+	writeBackProfileFields(c, params, form, formData, profile)
 
-		// Check pipeline
-		pipelines, err := params.MongoService.ListPipelines(c, bson.M{"eventID": form.EventID})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
-		}
+	return &req, true
+}
 
-		for _, pipeline := range pipelines {
-			if pipeline.Event.Type == "FormSubmission" {
-				// Sanity check
-				if pipeline.Event.FormSubmission.OnFormID != formID {
-					continue
-				}
+// submissionResult is the body returned for a successful submission, telling the applicant if they were waitlisted
+func submissionResult(response *models.FormResponse) gin.H {
+	if response.IsWaitlisted() {
+		return gin.H{"message": "The form is full, you have been added to the waitlist", "id": response.ID, "waitlisted": true, "waitlistPosition": response.WaitlistPosition}
+	}
 
-				err := helpers.TriggerPipeline(c, params.KafkaProducer, params.MongoService, pipeline, req.Data)
+	return gin.H{"message": "Success", "id": response.ID}
+}
 
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-					return
-				}
+// saveReservedResponse triggers the form's submission pipelines and saves the response.
+// Waitlisted responses trigger the form's WaitlistPromotion pipelines when they're promoted instead.
+func saveReservedResponse(c *gin.Context, params *types.RouteParams, form *models.FormStructure, req models.FormResponse) (primitive.ObjectID, error) {
+	// TODO: We should do this in a transaction
+
+	// Check pipeline
+	pipelines, err := params.MongoService.ListPipelines(c, bson.M{"eventID": form.EventID})
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	for _, pipeline := range pipelines {
+		if pipeline.Event.Type == "FormSubmission" && !req.IsWaitlisted() {
+			// Sanity check
+			if pipeline.Event.FormSubmission.OnFormID != form.ID {
+				continue
 			}
-		}
 
-		// Submit form
-		req.UserID = authenticatedUser.ID
-		if _, err := params.MongoService.CreateResponse(c, req); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
+			if err := helpers.TriggerPipeline(c, params.KafkaProducer, params.MongoService, pipeline, req.Data); err != nil {
+				return primitive.NilObjectID, err
+			}
 		}
+	}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Success"})
+	// Submit form
+	req.FormVersionID = form.VersionID
+	result, err := params.MongoService.CreateResponse(c, req)
+	if err != nil {
+		return primitive.NilObjectID, err
 	}
+
+	return result.InsertedID.(primitive.ObjectID), nil
 }
 
-func processResponses(form *models.FormStructure, responses *[]models.FormResponse) ([]map[string]interface{}, []string) {
+func processResponses(view responseView) ([]map[string]interface{}, []string) {
 	var processedResponses []map[string]interface{}
 
 	// Define the order of columns
-	columnOrder := []string{"Response ID", "User ID", "Submitted At"}
-	for _, attr := range form.Attrs {
-		columnOrder = append(columnOrder, attr.Question+"_attr_key:"+attr.Key)
+	columnOrder := []string{"Response ID", "User ID", "Submitted At", "Status", "Decision", "Review Score", "Tags"}
+	for _, attr := range view.fields {
+		columnOrder = append(columnOrder, responseColumnKey(attr))
 	}
 
 	// Create header row based on column order
@@ -174,15 +278,22 @@ func processResponses(form *models.FormStructure, responses *[]models.FormRespon
 	processedResponses = append(processedResponses, headerRow)
 
 	// Process each response
-	for _, response := range *responses {
+	for _, response := range view.responses {
 		processedResponse := make(map[string]interface{})
 		processedResponse["Response ID"] = response.ID.Hex()
 		processedResponse["User ID"] = response.UserID.Hex()
 		processedResponse["Submitted At"] = response.CreatedAt.Format(time.RFC3339)
+		processedResponse["Status"] = responseStatusLabel(response)
+		processedResponse["Decision"] = string(response.CurrentDecision())
+		processedResponse["Review Score"] = ""
+		if response.Review != nil {
+			processedResponse["Review Score"] = response.Review.Score
+		}
+		processedResponse["Tags"] = strings.Join(response.Tags, ", ")
 
 		// Add other attributes
-		for _, attr := range form.Attrs {
-			uniqueKey := attr.Question + "_attr_key:" + attr.Key
+		for _, attr := range view.fields {
+			uniqueKey := responseColumnKey(attr)
 			value, exists := response.Data[attr.Key]
 			if exists {
 				processedResponse[uniqueKey] = value
@@ -197,6 +308,28 @@ func processResponses(form *models.FormStructure, responses *[]models.FormRespon
 	return processedResponses, columnOrder
 }
 
+// responseStatusLabel describes the response's status for listings and exports, waitlisted responses include their position
+// and rejected or declined responses that gave their place back are released
+func responseStatusLabel(response models.FormResponse) string {
+	if response.IsWaitlisted() {
+		return fmt.Sprintf("waitlisted #%d", response.WaitlistPosition)
+	}
+	if response.Status == models.ResponseReleased {
+		return string(models.ResponseReleased)
+	}
+	return string(models.ResponseSubmitted)
+}
+
+// responseColumnKey is the column a field is exported under, internal fields are marked so they
+// aren't mistaken for something the applicant answered
+func responseColumnKey(attr models.FormField) string {
+	question := attr.Question
+	if attr.IsInternal {
+		question += " (internal)"
+	}
+	return question + "_attr_key:" + attr.Key
+}
+
 func listFormResponsesHandler(params *types.RouteParams) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authenticatedUser, ok := utils.GetUserFromContext(c, true)
@@ -221,73 +354,43 @@ func listFormResponsesHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		responses, err := params.MongoService.ListResponses(c, bson.M{"formID": formID})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
-		}
-
-		processedResponses, columnOrder := processResponses(form, &responses)
-
-		c.JSON(http.StatusOK, gin.H{"responses": processedResponses, "columnOrder": columnOrder})
-	}
-}
-
-func downloadFormResponsesAsCSVHandler(params *types.RouteParams) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authenticatedUser, ok := utils.GetUserFromContext(c, true)
-		if !ok {
-			return
+		// Withdrawn responses are only listed when asked for
+		filter := bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}}
+		if c.Query("includeWithdrawn") == "true" {
+			delete(filter, "isDeleted")
 		}
 
-		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		query, err := parseResponseQuery(form, c.Request.URL.Query(), true)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 
-		// Fetch form and responses similar to your existing logic
-		form, err := params.MongoService.GetForm(c, formID, true)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+		views, nextCursor, ok := listResponseViews(c, params, form, filter, query)
+		if !ok {
 			return
 		}
 
-		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
-			return
-		}
+		// Without a version the original schema has a set of columns per form version
+		if c.Query("schema") == schemaOriginal && c.Query("version") == "" {
+			versions := []gin.H{}
+			for _, view := range views {
+				processedResponses, columnOrder := processResponses(view)
+				versions = append(versions, gin.H{"version": view.version, "versionID": view.versionID, "responses": processedResponses, "columnOrder": columnOrder})
+			}
 
-		responses, err := params.MongoService.ListResponses(c, bson.M{"formID": formID})
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.JSON(http.StatusOK, gin.H{"versions": versions, "nextCursor": nextCursor})
 			return
 		}
-		processedResponses, columnOrder := processResponses(form, &responses)
-
-		c.Writer.Header().Set("Content-Type", "text/csv")
-		c.Writer.Header().Set("Content-Disposition", "attachment;filename=form_responses.csv")
-		writer := csv.NewWriter(c.Writer)
-
-		// Write data rows
-		for _, processedResponse := range processedResponses {
-			var row []string
-			for _, col := range columnOrder {
-				value, ok := processedResponse[col]
-				if !ok {
-					row = append(row, "")
-				} else {
-					row = append(row, fmt.Sprintf("%v", value))
-				}
-			}
-			writer.Write(row)
-		}
 
-		writer.Flush()
+		processedResponses, columnOrder := processResponses(views[0])
+
+		c.JSON(http.StatusOK, gin.H{"responses": processedResponses, "columnOrder": columnOrder, "nextCursor": nextCursor})
 	}
 }
 
-// Note: this only allows event admins to update responses not the user who submitted the response
+// updateFormResponseHandler lets organizers edit any response, applicants can edit their own while
+// submissions are open if the form allows response edits
 func updateFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authenticatedUser, ok := utils.GetUserFromContext(c, true)
@@ -301,7 +404,7 @@ func updateFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID})
+		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "isDeleted": bson.M{"$ne": true}}, nil)
 		if err != nil || len(responses) == 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Response does not exist"})
 			return
@@ -319,8 +422,14 @@ func updateFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+		// The data is validated against this form so the response has to belong to it
+		if responses[0].FormID != form.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Response does not exist"})
+			return
+		}
+
+		isOrganizer := mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form)
+		if !isOrganizer && !canApplicantEditResponse(c, form, &responses[0], authenticatedUser) {
 			return
 		}
 
@@ -330,49 +439,121 @@ func updateFormResponseHandler(params *types.RouteParams) gin.HandlerFunc {
 			return
 		}
 
-		// originalData := responses[0].Data
+		// Organizers editing a response can correct locked fields, applicants can't
+		var profile *models.User
+		if !isOrganizer {
+			if profile, err = lockProfileFields(c, params, form, formData, authenticatedUser.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update response"})
+				return
+			}
+		}
+
+		if fieldErrors := utils.ValidateFormData(form, formData, isOrganizer); len(fieldErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.FormDataErrorMessage(form, fieldErrors), "fieldErrors": fieldErrors})
+			return
+		}
+
+		// Applicants can't see internal fields so their edits keep whatever the organizers set
+		if !isOrganizer {
+			for _, attr := range form.Attrs {
+				if value, ok := responses[0].Data[attr.Key]; ok && attr.IsInternal {
+					formData[attr.Key] = value
+				}
+			}
+		}
+		utils.DropHiddenFormFields(form, formData)
+
+		// Organizers can attach any upload made for the field, applicants only their own
+		uploaderID := primitive.NilObjectID
+		if !isOrganizer {
+			uploaderID = authenticatedUser.ID
+		}
+
+		uploadIDs, fieldErrors := verifyFileUploads(c, params, form, formData, uploaderID, responseID)
+		if len(fieldErrors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": utils.FormDataErrorMessage(form, fieldErrors), "fieldErrors": fieldErrors})
+			return
+		}
+
 		response := responses[0]
 		response.Data = formData
 		response.UpdatedAt = time.Now()
 
+		// The data now matches the current schema rather than the one it was submitted against
+		response.FormVersionID = form.VersionID
+
 		if errors := utils.ValidateStruct(utils.Validator, response); len(errors) > 0 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
 			return
 		}
 
-		// Check pipeline
-		pipelines, err := params.MongoService.ListPipelines(c, bson.M{"eventID": form.EventID})
-		if err != nil {
+		change := models.ResponseChange{ChangedBy: authenticatedUser.ID, Source: models.ResponseChangeAPI, Action: "edit"}
+		if err := saveResponseChange(c, params, form, responses[0], response, change); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			return
 		}
 
-		for _, pipeline := range pipelines {
-			if pipeline.Event.Type == "FieldChange" {
-				// Sanity check
-				if pipeline.Event.FieldChange.OnFormID != formID {
-					continue
-				}
+		if _, err := params.MongoService.AttachFileUploads(c, responseID, uploadIDs); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
 
-				if !kafka.FieldChangeCheck(pipeline.Event.FieldChange, &response.Data) {
-					continue
-				}
+		writeBackProfileFields(c, params, form, formData, profile)
+
+		c.JSON(http.StatusOK, gin.H{"id": responseID})
+	}
+}
 
-				err := helpers.TriggerPipeline(c, params.KafkaProducer, params.MongoService, pipeline, response.Data)
+// saveResponseChange saves a response's edited data, then records the change and triggers pipelines like
+// responseChanged. Decisions and tags are saved on their own so an edit can't overwrite them.
+func saveResponseChange(ctx context.Context, params *types.RouteParams, form *models.FormStructure, before models.FormResponse, after models.FormResponse, change models.ResponseChange) error {
+	if _, err := params.MongoService.UpdateResponse(ctx, after, after.ID); err != nil {
+		return err
+	}
 
-				if err != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-					return
-				}
-			}
+	return responseChanged(ctx, params, form, before, after, change)
+}
+
+// responseChanged records a saved change in the response's history and triggers the form's FieldChange pipelines.
+// A pipeline only runs when the change makes its condition true, so each applicant goes through it once however
+// often their response is saved. Pipelines on DecisionFieldID watch the response's decision and pipelines on
+// TagsFieldID its tags.
+func responseChanged(ctx context.Context, params *types.RouteParams, form *models.FormStructure, before models.FormResponse, after models.FormResponse, change models.ResponseChange) error {
+	if err := recordResponseChange(ctx, params, before, after, change); err != nil {
+		return err
+	}
+
+	pipelines, err := params.MongoService.ListPipelines(ctx, bson.M{"eventID": form.EventID, "event.type": "FieldChange"})
+	if err != nil {
+		return err
+	}
+
+	beforeData, afterData := fieldChangeData(before), fieldChangeData(after)
+	for _, pipeline := range pipelines {
+		fieldChange := pipeline.Event.FieldChange
+		if fieldChange == nil || fieldChange.OnFormID != form.ID {
+			continue
 		}
 
-		_, err = params.MongoService.UpdateResponse(c, response, responseID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
-			return
+		if !kafka.FieldChangeCheck(fieldChange, &afterData) || kafka.FieldChangeCheck(fieldChange, &beforeData) {
+			continue
 		}
 
-		c.JSON(http.StatusOK, gin.H{"id": responseID})
+		if err := helpers.TriggerPipeline(ctx, params.KafkaProducer, params.MongoService, pipeline, afterData); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldChangeData is the response's data with its decision and tags, as FieldChange pipelines see it
+func fieldChangeData(response models.FormResponse) map[string]interface{} {
+	data := make(map[string]interface{}, len(response.Data)+2)
+	for key, value := range response.Data {
+		data[key] = value
 	}
+	data[models.DecisionFieldID] = string(response.CurrentDecision())
+	data[models.TagsFieldID] = append([]string{}, response.Tags...)
+	return data
 }
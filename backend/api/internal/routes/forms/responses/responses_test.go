@@ -0,0 +1,124 @@
+package responses
+
+import (
+	"api/internal/types"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"shared/models"
+	"shared/mongodb"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// submissionStore reserves a place, or a waitlist position, for every submission and fails to save the response
+type submissionStore struct {
+	*mongodb.MockMongoService
+	form             models.FormStructure
+	waitlistPosition int
+
+	released  int
+	heldPlace bool
+}
+
+func (s *submissionStore) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
+	form := s.form
+	if stripSecrets {
+		form.StripSecrets()
+	}
+	return &form, nil
+}
+
+func (s *submissionStore) ReserveFormSubmission(ctx context.Context, form models.FormStructure, response models.FormResponse) (int, error) {
+	return s.waitlistPosition, nil
+}
+
+func (s *submissionStore) CreateResponse(ctx context.Context, submission models.FormResponse) (*mongo.InsertOneResult, error) {
+	return nil, errors.New("write failed")
+}
+
+func (s *submissionStore) ReleaseFormSubmission(ctx context.Context, response models.FormResponse, heldPlace bool) (*models.FormResponse, error) {
+	s.released++
+	s.heldPlace = heldPlace
+	return nil, nil
+}
+
+func TestSubmitResponseReleasesWhenSaveFails(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		waitlistPosition int
+		heldPlace        bool
+	}{
+		{"held a place", 0, true},
+		{"waitlisted", 3, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &submissionStore{
+				MockMongoService: mongodb.NewMockMongoService(),
+				form:             models.FormStructure{ID: primitive.NewObjectID(), Status: "published"},
+				waitlistPosition: tc.waitlistPosition,
+			}
+			params := &types.RouteParams{MongoService: store}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+
+			response, ok := submitResponse(c, params, models.FormResponse{FormID: store.form.ID, UserID: primitive.NewObjectID(), Data: map[string]interface{}{}})
+			assert.False(t, ok)
+			assert.Nil(t, response)
+			assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+			// The reserved place is given back, a waitlisted response never took one
+			assert.Equal(t, 1, store.released)
+			assert.Equal(t, tc.heldPlace, store.heldPlace)
+		})
+	}
+}
+
+func TestSubmitResponseChecksAllowedDomains(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		email   string
+		allowed bool
+	}{
+		{"allowed domain", "ada@students.example.edu", true},
+		{"other domain", "ada@example.com", false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &submissionStore{
+				MockMongoService: mongodb.NewMockMongoService(),
+				form: models.FormStructure{
+					ID:             primitive.NewObjectID(),
+					Status:         "published",
+					IsRestricted:   true,
+					AllowedDomains: []models.FormAllowedDomain{{Domain: "example.edu", AllowSubdomains: true}},
+				},
+			}
+			params := &types.RouteParams{MongoService: store}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+			user := &models.User{ID: primitive.NewObjectID(), Email: tc.email}
+			c.Set("user", user)
+
+			_, ok := submitResponse(c, params, models.FormResponse{FormID: store.form.ID, UserID: user.ID, Data: map[string]interface{}{}})
+			assert.False(t, ok)
+
+			// An allowed applicant gets as far as saving the response, which the store fails
+			if tc.allowed {
+				assert.Equal(t, http.StatusInternalServerError, w.Code)
+				assert.Equal(t, 1, store.released)
+			} else {
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+				assert.Equal(t, 0, store.released)
+			}
+		})
+	}
+}
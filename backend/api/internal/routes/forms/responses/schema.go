@@ -0,0 +1,163 @@
+package responses
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/utils"
+	"sort"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// schemaLatest shows every response against the form's current fields, plus any removed fields that still have data
+	schemaLatest = "latest"
+
+	// schemaOriginal shows each response against the version of the form it was submitted to
+	schemaOriginal = "original"
+)
+
+// responseView is a set of responses and the fields they're shown against
+type responseView struct {
+	version   int
+	versionID primitive.ObjectID
+	fields    []models.FormField
+	responses []models.FormResponse
+}
+
+// listResponseViews lists the responses matching filter and splits them into the views requested by ?schema= and ?version=.
+// The original schema without a version gives one view per form version, otherwise there's a single view.
+// A query filters, sorts and pages the responses and the cursor for the next page is returned, without one every response is listed.
+// The error response is written when it returns false.
+func listResponseViews(c *gin.Context, params *types.RouteParams, form *models.FormStructure, filter bson.M, query *responseQuery) ([]responseView, string, bool) {
+	schema := c.DefaultQuery("schema", schemaLatest)
+	if schema != schemaLatest && schema != schemaOriginal {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "schema must be latest or original"})
+		return nil, "", false
+	}
+
+	versions, err := params.MongoService.ListFormVersions(c, form.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, "", false
+	}
+
+	selected, ok := selectedFormVersion(c, versions)
+	if !ok {
+		return nil, "", false
+	}
+	if selected != nil {
+		filter["formVersionID"] = selected.ID
+	}
+
+	var opts *options.FindOptions
+	if query != nil {
+		if len(query.conditions) > 0 {
+			filter["$and"] = query.conditions
+		}
+		opts = query.findOptions()
+	}
+
+	responses, err := params.MongoService.ListResponses(c, filter, opts)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return nil, "", false
+	}
+
+	var nextCursor string
+	if query != nil {
+		responses, nextCursor = query.page(responses)
+	}
+
+	switch {
+	case schema == schemaLatest:
+		return []responseView{{version: form.Version, versionID: form.VersionID, fields: latestSchemaFields(form, versions, responses), responses: responses}}, nextCursor, true
+	case selected != nil:
+		return []responseView{{version: selected.Version, versionID: selected.ID, fields: selected.Attrs, responses: responses}}, nextCursor, true
+	default:
+		return originalSchemaViews(form, versions, responses), nextCursor, true
+	}
+}
+
+// selectedFormVersion returns the version asked for with ?version=, or nil without one.
+// The error response is written when it returns false.
+func selectedFormVersion(c *gin.Context, versions []models.FormVersion) (*models.FormVersion, bool) {
+	if c.Query("version") == "" {
+		return nil, true
+	}
+
+	number, err := strconv.Atoi(c.Query("version"))
+	for i := range versions {
+		if err == nil && versions[i].Version == number {
+			return &versions[i], true
+		}
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Form version not found"})
+	return nil, false
+}
+
+// latestSchemaFields returns the form's fields followed by the removed fields that any of the responses still have data for,
+// removed fields are marked so they aren't mistaken for current questions
+func latestSchemaFields(form *models.FormStructure, versions []models.FormVersion, responses []models.FormResponse) []models.FormField {
+	merged, removed := utils.MergeRemovedFormFields(form.Attrs, versions)
+
+	fields := make([]models.FormField, 0, len(merged))
+	for _, field := range merged {
+		if !removed[field.Key] {
+			fields = append(fields, field)
+			continue
+		}
+
+		for _, response := range responses {
+			if _, ok := response.Data[field.Key]; ok {
+				field.Question += " (removed)"
+				fields = append(fields, field)
+				break
+			}
+		}
+	}
+
+	return fields
+}
+
+// originalSchemaViews groups responses by the form version they were submitted to, oldest version first.
+// Responses from before the form was versioned are shown against the current fields as version 0.
+func originalSchemaViews(form *models.FormStructure, versions []models.FormVersion, responses []models.FormResponse) []responseView {
+	byID := map[primitive.ObjectID]*responseView{}
+	for _, version := range versions {
+		byID[version.ID] = &responseView{version: version.Version, versionID: version.ID, fields: version.Attrs}
+	}
+
+	var unversioned *responseView
+	for _, response := range responses {
+		view, ok := byID[response.FormVersionID]
+		if !ok {
+			if unversioned == nil {
+				unversioned = &responseView{fields: form.Attrs}
+			}
+			view = unversioned
+		}
+		view.responses = append(view.responses, response)
+	}
+
+	views := []responseView{}
+	if unversioned != nil {
+		views = append(views, *unversioned)
+	}
+	for _, view := range byID {
+		if len(view.responses) > 0 {
+			views = append(views, *view)
+		}
+	}
+
+	sort.Slice(views, func(a, b int) bool {
+		return views[a].version < views[b].version
+	})
+	return views
+}
@@ -0,0 +1,47 @@
+package responses
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLatestSchemaFields(t *testing.T) {
+	form := &models.FormStructure{Attrs: []models.FormField{{Key: "name", Question: "Name"}}}
+	versions := []models.FormVersion{
+		{Version: 1, Attrs: []models.FormField{{Key: "name", Question: "Name"}, {Key: "age", Question: "Age"}, {Key: "school", Question: "School"}}},
+	}
+	responses := []models.FormResponse{{Data: map[string]interface{}{"name": "Ada", "age": 36}}}
+
+	fields := latestSchemaFields(form, versions, responses)
+
+	// school was removed and no response has data for it
+	assert.Len(t, fields, 2)
+	assert.Equal(t, "Name", fields[0].Question)
+	assert.Equal(t, "Age (removed)", fields[1].Question)
+}
+
+func TestOriginalSchemaViews(t *testing.T) {
+	v1, v2 := primitive.NewObjectID(), primitive.NewObjectID()
+	form := &models.FormStructure{Attrs: []models.FormField{{Key: "name", Question: "Full name"}}}
+	versions := []models.FormVersion{
+		{ID: v1, Version: 1, Attrs: []models.FormField{{Key: "name", Question: "Name"}}},
+		{ID: v2, Version: 2, Attrs: []models.FormField{{Key: "name", Question: "Full name"}}},
+	}
+	responses := []models.FormResponse{
+		{FormVersionID: v2},
+		{},
+		{FormVersionID: v1},
+		{FormVersionID: v2},
+	}
+
+	views := originalSchemaViews(form, versions, responses)
+
+	assert.Len(t, views, 3)
+	assert.Equal(t, 0, views[0].version, "responses from before versioning come first")
+	assert.Len(t, views[0].responses, 1)
+	assert.Equal(t, "Name", views[1].fields[0].Question)
+	assert.Len(t, views[2].responses, 2)
+}
@@ -0,0 +1,57 @@
+package responses
+
+import (
+	"api/internal/helpers"
+	"api/internal/types"
+	"context"
+	"log"
+	"shared/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// announcePromotion records that a waitlisted response was given a freed place on the form and triggers the
+// form's WaitlistPromotion pipelines so the applicant hears about it
+func announcePromotion(c context.Context, params *types.RouteParams, formID primitive.ObjectID, promoted *models.FormResponse) error {
+	form, err := params.MongoService.GetForm(c, formID, true)
+	if err != nil {
+		return err
+	}
+
+	waitlisted := *promoted
+	waitlisted.Status = models.ResponseWaitlisted
+	change := models.ResponseChange{Source: models.ResponseChangeSystem, Action: "promotion"}
+	if err := recordResponseChange(c, params, waitlisted, *promoted, change); err != nil {
+		return err
+	}
+
+	pipelines, err := params.MongoService.ListPipelines(c, bson.M{"eventID": form.EventID, "event.type": "WaitlistPromotion"})
+	if err != nil {
+		return err
+	}
+
+	for _, pipeline := range pipelines {
+		if pipeline.Event.WaitlistPromotion == nil || pipeline.Event.WaitlistPromotion.OnFormID != formID {
+			continue
+		}
+
+		if err := helpers.TriggerPipeline(c, params.KafkaProducer, params.MongoService, pipeline, promoted.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// releaseSubmission gives back the response's claim on the form, and its place to the waitlist, when it couldn't be
+// saved or was withdrawn. Failures to announce a promotion are logged since the place has already been handed over.
+func releaseSubmission(c context.Context, params *types.RouteParams, response models.FormResponse, heldPlace bool) error {
+	promoted, err := params.MongoService.ReleaseFormSubmission(c, response, heldPlace)
+	if promoted != nil {
+		if err := announcePromotion(c, params, response.FormID, promoted); err != nil {
+			log.Printf("Failed to announce the promotion of response %s: %v", promoted.ID.Hex(), err)
+		}
+	}
+	return err
+}
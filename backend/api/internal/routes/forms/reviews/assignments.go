@@ -0,0 +1,272 @@
+package reviews
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// assignReviewsRequest shares responses out between reviewers, every response on the form by default
+type assignReviewsRequest struct {
+	Reviewers          []string              `json:"reviewers" validate:"required,min=1,dive,email"`
+	Strategy           models.ReviewStrategy `json:"strategy" validate:"required,oneof=roundRobin loadBalanced"`
+	ReviewsPerResponse int                   `json:"reviewsPerResponse" validate:"min=1,max=20"`
+	ResponseIDs        []primitive.ObjectID  `json:"responseIDs,omitempty"`
+}
+
+// listAssignmentsHandler lists a form's review assignments for organizers, ?reviewerID= and ?responseID= narrow them down
+func listAssignmentsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+			return
+		}
+
+		filter := bson.M{"formID": form.ID}
+		for _, key := range []string{"reviewerID", "responseID"} {
+			value := c.Query(key)
+			if value == "" {
+				continue
+			}
+
+			id, err := primitive.ObjectIDFromHex(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + key})
+				return
+			}
+			filter[key] = id
+		}
+
+		reviews, err := params.MongoService.ListReviews(c, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list review assignments"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"assignments": reviews})
+	}
+}
+
+// assignReviewsHandler gives each response reviewers until it has reviewsPerResponse of them. Reviewers are
+// given by email and need an account, they don't have to be organizers.
+func assignReviewsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this form"})
+			return
+		}
+
+		var req assignReviewsRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if _, err := params.MongoService.GetReviewRubric(c, form.ID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The form needs a review rubric before responses can be assigned"})
+			return
+		}
+
+		var reviewers []primitive.ObjectID
+		seen := map[primitive.ObjectID]bool{}
+		for _, email := range req.Reviewers {
+			user, err := params.MongoService.FindUserByEmail(c, strings.ToLower(strings.TrimSpace(email)))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "No account exists for reviewer " + email})
+				return
+			}
+			if !seen[user.ID] {
+				seen[user.ID] = true
+				reviewers = append(reviewers, user.ID)
+			}
+		}
+
+		filter := bson.M{"formID": form.ID, "isDeleted": bson.M{"$ne": true}}
+		if len(req.ResponseIDs) > 0 {
+			filter["_id"] = bson.M{"$in": req.ResponseIDs}
+		}
+
+		opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}).SetProjection(bson.M{"_id": 1})
+		responses, err := params.MongoService.ListResponses(c, filter, opts)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		existing, err := params.MongoService.ListReviews(c, bson.M{"formID": form.ID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		assigned := map[primitive.ObjectID]map[primitive.ObjectID]bool{}
+		load := map[primitive.ObjectID]int{}
+		for _, review := range existing {
+			if assigned[review.ResponseID] == nil {
+				assigned[review.ResponseID] = map[primitive.ObjectID]bool{}
+			}
+			assigned[review.ResponseID][review.ReviewerID] = true
+			if !review.IsSubmitted() {
+				load[review.ReviewerID]++
+			}
+		}
+
+		responseIDs := make([]primitive.ObjectID, 0, len(responses))
+		for _, response := range responses {
+			responseIDs = append(responseIDs, response.ID)
+		}
+
+		reviews := distributeReviews(responseIDs, assigned, reviewers, load, req.ReviewsPerResponse, req.Strategy)
+		for i := range reviews {
+			reviews[i].FormID = form.ID
+			reviews[i].AssignedBy = authenticatedUser.ID
+		}
+
+		if err := params.MongoService.CreateReviews(c, reviews); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to assign reviews"})
+			return
+		}
+
+		// Responses are left short when every reviewer already has them
+		short := 0
+		for _, responseID := range responseIDs {
+			if len(assigned[responseID]) < req.ReviewsPerResponse {
+				short++
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"assigned": len(reviews), "shortResponses": short})
+	}
+}
+
+// distributeReviews picks reviewers for each response until it has perResponse of them, a reviewer is never given
+// the same response twice. Round robin hands responses to the reviewers in turn, load balanced picks the reviewers
+// with the least unfinished reviews. assigned and load are updated with the new reviews.
+func distributeReviews(
+	responseIDs []primitive.ObjectID,
+	assigned map[primitive.ObjectID]map[primitive.ObjectID]bool,
+	reviewers []primitive.ObjectID,
+	load map[primitive.ObjectID]int,
+	perResponse int,
+	strategy models.ReviewStrategy,
+) []models.Review {
+	reviews := []models.Review{}
+	next := 0
+
+	for _, responseID := range responseIDs {
+		if assigned[responseID] == nil {
+			assigned[responseID] = map[primitive.ObjectID]bool{}
+		}
+		have := assigned[responseID]
+
+		for len(have) < perResponse {
+			reviewer, ok := primitive.NilObjectID, false
+
+			switch strategy {
+			case models.ReviewRoundRobin:
+				for tried := 0; tried < len(reviewers) && !ok; tried++ {
+					candidate := reviewers[next%len(reviewers)]
+					next++
+					if !have[candidate] {
+						reviewer, ok = candidate, true
+					}
+				}
+			case models.ReviewLoadBalanced:
+				for _, candidate := range reviewers {
+					if !have[candidate] && (!ok || load[candidate] < load[reviewer]) {
+						reviewer, ok = candidate, true
+					}
+				}
+			}
+
+			if !ok {
+				break
+			}
+
+			have[reviewer] = true
+			load[reviewer]++
+			reviews = append(reviews, models.Review{ResponseID: responseID, ReviewerID: reviewer})
+		}
+	}
+
+	return reviews
+}
+
+// deleteAssignmentHandler removes a review assignment, a submitted review stops counting towards the response's score
+func deleteAssignmentHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this form"})
+			return
+		}
+
+		reviewID, err := primitive.ObjectIDFromHex(c.Param("review_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid review ID"})
+			return
+		}
+
+		reviews, err := params.MongoService.ListReviews(c, bson.M{"_id": reviewID, "formID": form.ID})
+		if err != nil || len(reviews) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review assignment does not exist"})
+			return
+		}
+
+		if _, err := params.MongoService.DeleteReview(c, reviewID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete review assignment"})
+			return
+		}
+
+		if reviews[0].IsSubmitted() {
+			if _, err := params.MongoService.SummarizeResponseReviews(c, reviews[0].ResponseID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update the response's score"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": reviewID})
+	}
+}
@@ -0,0 +1,439 @@
+package reviews
+
+import (
+	"api/internal/helpers"
+	"api/internal/middlewares"
+	"api/internal/types"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"shared/kafka"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func RegisterFormReviewRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	r.GET("rubric", middlewares.JWTAuthMiddleware(), getRubricHandler(params))
+	r.PUT("rubric", middlewares.JWTAuthMiddleware(), saveRubricHandler(params))
+
+	r.GET("assignments", middlewares.JWTAuthMiddleware(), listAssignmentsHandler(params))
+	r.POST("assignments", middlewares.JWTAuthMiddleware(), assignReviewsHandler(params))
+	r.DELETE("assignments/:review_id", middlewares.JWTAuthMiddleware(), deleteAssignmentHandler(params))
+
+	r.GET("queue", middlewares.JWTAuthMiddleware(), reviewQueueHandler(params))
+	r.GET("responses/:response_id", middlewares.JWTAuthMiddleware(), listResponseReviewsHandler(params))
+	r.PUT("responses/:response_id", middlewares.JWTAuthMiddleware(), submitReviewHandler(params))
+}
+
+// submitReviewRequest scores each of the rubric's criteria
+type submitReviewRequest struct {
+	Scores  map[string]float64 `json:"scores" validate:"required"`
+	Comment string             `json:"comment" validate:"max=5000"`
+}
+
+// getReviewForm reads the form in the route, the error response is written when it returns nil
+func getReviewForm(c *gin.Context, params *types.RouteParams) *models.FormStructure {
+	formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+		return nil
+	}
+
+	form, err := params.MongoService.GetForm(c, formID, true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Form does not exist"})
+		return nil
+	}
+	return form
+}
+
+func getRubricHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		// Reviewers need the rubric to score their responses
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			assigned, err := params.MongoService.ListReviews(c, bson.M{"formID": form.ID, "reviewerID": authenticatedUser.ID})
+			if err != nil || len(assigned) == 0 {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to review this form"})
+				return
+			}
+		}
+
+		rubric, err := params.MongoService.GetReviewRubric(c, form.ID)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "The form does not have a review rubric"})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rubric": rubric})
+	}
+}
+
+// saveRubricHandler creates or replaces the form's rubric. Submitted reviews are scored again so changing
+// a criterion's weight is reflected in every response's score.
+func saveRubricHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to modify this form"})
+			return
+		}
+
+		var rubric models.ReviewRubric
+		if err := utils.BindJSON(c, &rubric); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, rubric); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if err := checkRubric(form, rubric); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		rubric.FormID = form.ID
+		rubric.EventID = form.EventID
+		rubric.UpdatedBy = authenticatedUser.ID
+		if _, err := params.MongoService.SaveReviewRubric(c, rubric); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review rubric"})
+			return
+		}
+
+		submitted, err := params.MongoService.ListReviews(c, bson.M{"formID": form.ID, "submittedAt": bson.M{"$exists": true}})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		rescored := map[primitive.ObjectID]bool{}
+		for _, review := range submitted {
+			review.Score = rubric.Score(review.Scores)
+			if _, err := params.MongoService.SubmitReview(c, review); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review scores"})
+				return
+			}
+			rescored[review.ResponseID] = true
+		}
+
+		for responseID := range rescored {
+			if _, err := params.MongoService.SummarizeResponseReviews(c, responseID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update review scores"})
+				return
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rubric": rubric})
+	}
+}
+
+// checkRubric checks the criteria keys are unique and the blind fields are on the form
+func checkRubric(form *models.FormStructure, rubric models.ReviewRubric) error {
+	keys := map[string]bool{}
+	for _, criterion := range rubric.Criteria {
+		if keys[criterion.Key] {
+			return fmt.Errorf("criterion %s is used more than once", criterion.Key)
+		}
+		keys[criterion.Key] = true
+	}
+
+	fields := map[string]bool{}
+	for _, field := range form.Attrs {
+		fields[field.Key] = true
+	}
+	for _, key := range rubric.BlindFields {
+		if !fields[key] {
+			return fmt.Errorf("blind field %s is not on the form", key)
+		}
+	}
+
+	return nil
+}
+
+// checkScores checks every criterion is scored between 0 and its max score
+func checkScores(rubric *models.ReviewRubric, scores map[string]float64) error {
+	criteria := map[string]bool{}
+	for _, criterion := range rubric.Criteria {
+		criteria[criterion.Key] = true
+
+		score, ok := scores[criterion.Key]
+		if !ok {
+			return fmt.Errorf("%s must be scored", criterion.Label)
+		}
+		if score < 0 || score > float64(criterion.MaxScore) {
+			return fmt.Errorf("%s must be scored from 0 to %d", criterion.Label, criterion.MaxScore)
+		}
+	}
+
+	for key := range scores {
+		if !criteria[key] {
+			return fmt.Errorf("%s is not one of the rubric's criteria", key)
+		}
+	}
+	return nil
+}
+
+// submitReviewHandler saves the authenticated reviewer's scores for a response they were assigned, they can
+// change them later. The response's summary is updated and its ReviewScored pipelines are triggered when its
+// score starts to match their condition.
+func submitReviewHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+			return
+		}
+
+		review, err := params.MongoService.GetReview(c, responseID, authenticatedUser.ID)
+		if err != nil || review.FormID != form.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You were not assigned to review this response"})
+			return
+		}
+
+		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": responseID, "isDeleted": bson.M{"$ne": true}}, nil)
+		if err != nil || len(responses) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Response does not exist"})
+			return
+		}
+		response := responses[0]
+
+		rubric, err := params.MongoService.GetReviewRubric(c, form.ID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The form does not have a review rubric"})
+			return
+		}
+
+		var req submitReviewRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+			return
+		}
+
+		if err := checkScores(rubric, req.Scores); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		review.Scores = req.Scores
+		review.Comment = req.Comment
+		review.Score = rubric.Score(req.Scores)
+		review.SubmittedAt = time.Now()
+		if _, err := params.MongoService.SubmitReview(c, *review); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save review"})
+			return
+		}
+
+		summary, err := params.MongoService.SummarizeResponseReviews(c, responseID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update the response's score"})
+			return
+		}
+
+		if err := triggerReviewScored(c, params, form, response, summary); err != nil {
+			log.Printf("Failed to trigger review pipelines for response %s: %v", responseID.Hex(), err)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"review": review, "summary": summary})
+	}
+}
+
+// triggerReviewScored triggers the form's ReviewScored pipelines whose condition the new summary meets and the
+// response's previous summary didn't, so a pipeline only runs once for each time the score crosses its threshold
+func triggerReviewScored(c *gin.Context, params *types.RouteParams, form *models.FormStructure, response models.FormResponse, summary *models.ResponseReviewSummary) error {
+	pipelines, err := params.MongoService.ListPipelines(c, bson.M{"eventID": form.EventID, "event.type": "ReviewScored"})
+	if err != nil {
+		return err
+	}
+
+	for _, pipeline := range pipelines {
+		event := pipeline.Event.ReviewScored
+		if event == nil || event.OnFormID != form.ID {
+			continue
+		}
+
+		if !kafka.ReviewScoreCheck(event, summary) || kafka.ReviewScoreCheck(event, response.Review) {
+			continue
+		}
+
+		if err := helpers.TriggerPipeline(c, params.KafkaProducer, params.MongoService, pipeline, response.Data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listResponseReviewsHandler lists every review of a response for organizers
+func listResponseReviewsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "You do not have permission to access this form"})
+			return
+		}
+
+		responseID, err := primitive.ObjectIDFromHex(c.Param("response_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid response ID"})
+			return
+		}
+
+		reviews, err := params.MongoService.ListReviews(c, bson.M{"formID": form.ID, "responseID": responseID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reviews"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+	}
+}
+
+// reviewQueueHandler lists the authenticated reviewer's assignments with the responses to score,
+// ?pending=true leaves out the ones they've submitted. Internal fields are only shown to organizers
+// and in blind mode identifying answers are hidden from everyone.
+func reviewQueueHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		form := getReviewForm(c, params)
+		if form == nil {
+			return
+		}
+
+		filter := bson.M{"formID": form.ID, "reviewerID": authenticatedUser.ID}
+		if c.Query("pending") == "true" {
+			filter["submittedAt"] = bson.M{"$exists": false}
+		}
+
+		reviews, err := params.MongoService.ListReviews(c, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reviews"})
+			return
+		}
+
+		rubric, err := params.MongoService.GetReviewRubric(c, form.ID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The form does not have a review rubric"})
+			return
+		}
+
+		responseIDs := make([]primitive.ObjectID, 0, len(reviews))
+		for _, review := range reviews {
+			responseIDs = append(responseIDs, review.ResponseID)
+		}
+
+		responses, err := params.MongoService.ListResponses(c, bson.M{"_id": bson.M{"$in": responseIDs}, "isDeleted": bson.M{"$ne": true}}, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		byID := map[primitive.ObjectID]models.FormResponse{}
+		for _, response := range responses {
+			byID[response.ID] = response
+		}
+
+		isOrganizer := mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, form.ID, form)
+		hidden := rubric.HiddenFields(form, isOrganizer)
+
+		queue := []gin.H{}
+		for _, review := range reviews {
+			response, ok := byID[review.ResponseID]
+			if !ok {
+				continue
+			}
+			queue = append(queue, gin.H{"review": review, "response": reviewResponseView(response, hidden, rubric.BlindMode)})
+		}
+
+		c.JSON(http.StatusOK, gin.H{"rubric": rubric, "fields": visibleReviewFields(form, hidden), "queue": queue})
+	}
+}
+
+func visibleReviewFields(form *models.FormStructure, hidden map[string]bool) []models.FormField {
+	fields := []models.FormField{}
+	for _, field := range form.Attrs {
+		if !hidden[field.Key] {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// reviewResponseView is the part of a response a reviewer sees, in blind mode who submitted it is left out
+func reviewResponseView(response models.FormResponse, hidden map[string]bool, blind bool) gin.H {
+	data := map[string]interface{}{}
+	for key, value := range response.Data {
+		if !hidden[key] {
+			data[key] = value
+		}
+	}
+
+	view := gin.H{"id": response.ID, "data": data, "createdAt": response.CreatedAt}
+	if !blind {
+		view["userID"] = response.UserID
+		if response.AnonymousEmail != "" {
+			view["anonymousEmail"] = response.AnonymousEmail
+		}
+	}
+	return view
+}
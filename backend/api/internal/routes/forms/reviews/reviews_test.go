@@ -0,0 +1,87 @@
+package reviews
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var rubricFixture = &models.ReviewRubric{Criteria: []models.RubricCriterion{
+	{Key: "idea", Label: "Idea", Weight: 2, MaxScore: 5},
+	{Key: "experience", Label: "Experience", Weight: 1, MaxScore: 10},
+}}
+
+func TestRubricScore(t *testing.T) {
+	assert.Equal(t, 100.0, rubricFixture.Score(map[string]float64{"idea": 5, "experience": 10}))
+	assert.InDelta(t, 50.0, rubricFixture.Score(map[string]float64{"idea": 2.5, "experience": 5}), 0.0001)
+	assert.InDelta(t, 66.6667, rubricFixture.Score(map[string]float64{"idea": 5}), 0.0001)
+}
+
+func TestCheckScores(t *testing.T) {
+	assert.NoError(t, checkScores(rubricFixture, map[string]float64{"idea": 0, "experience": 10}))
+	assert.Error(t, checkScores(rubricFixture, map[string]float64{"idea": 3}))
+	assert.Error(t, checkScores(rubricFixture, map[string]float64{"idea": 6, "experience": 1}))
+	assert.Error(t, checkScores(rubricFixture, map[string]float64{"idea": 1, "experience": 1, "other": 1}))
+}
+
+func TestDistributeReviewsRoundRobin(t *testing.T) {
+	reviewers := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	responses := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+
+	// The first response already has the first reviewer
+	assigned := map[primitive.ObjectID]map[primitive.ObjectID]bool{responses[0]: {reviewers[0]: true}}
+	reviews := distributeReviews(responses, assigned, reviewers, map[primitive.ObjectID]int{}, 2, models.ReviewRoundRobin)
+
+	assert.Equal(t, []models.Review{
+		{ResponseID: responses[0], ReviewerID: reviewers[1]},
+		{ResponseID: responses[1], ReviewerID: reviewers[2]},
+		{ResponseID: responses[1], ReviewerID: reviewers[0]},
+	}, reviews)
+}
+
+func TestDistributeReviewsLoadBalanced(t *testing.T) {
+	reviewers := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+	responses := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+
+	load := map[primitive.ObjectID]int{reviewers[0]: 2}
+	reviews := distributeReviews(responses, map[primitive.ObjectID]map[primitive.ObjectID]bool{}, reviewers, load, 1, models.ReviewLoadBalanced)
+
+	assert.Equal(t, []models.Review{
+		{ResponseID: responses[0], ReviewerID: reviewers[1]},
+		{ResponseID: responses[1], ReviewerID: reviewers[1]},
+		{ResponseID: responses[2], ReviewerID: reviewers[0]},
+	}, reviews)
+	assert.Equal(t, 3, load[reviewers[0]])
+
+	// Responses are left short when there aren't enough reviewers
+	reviews = distributeReviews(responses[:1], map[primitive.ObjectID]map[primitive.ObjectID]bool{}, reviewers, load, 3, models.ReviewLoadBalanced)
+	assert.Len(t, reviews, 2)
+}
+
+func TestHiddenReviewFields(t *testing.T) {
+	form := &models.FormStructure{Attrs: []models.FormField{
+		{Key: "name", Type: models.FieldTypeText, Prefill: &models.FieldPrefill{ProfileAttribute: models.ProfileFullName}},
+		{Key: "phone", Type: models.FieldTypeTelephone},
+		{Key: "github", Type: models.FieldTypeText},
+		{Key: "cv", Type: models.FieldTypeFile},
+		{Key: "pitch", Type: models.FieldTypeTextArea},
+		{Key: "notes", Type: models.FieldTypeTextArea, IsInternal: true},
+	}}
+
+	rubric := &models.ReviewRubric{}
+	assert.Equal(t, map[string]bool{"notes": true}, rubric.HiddenFields(form, false))
+	assert.Empty(t, rubric.HiddenFields(form, true))
+
+	blind := &models.ReviewRubric{BlindMode: true, BlindFields: []string{"github"}}
+	assert.Equal(t, map[string]bool{"name": true, "phone": true, "github": true, "cv": true}, blind.HiddenFields(form, true))
+
+	// Uploads are only shown in blind mode when the organizer opts them in
+	blind.BlindShowFiles = true
+	assert.NotContains(t, blind.HiddenFields(form, true), "cv")
+
+	view := reviewResponseView(models.FormResponse{Data: map[string]interface{}{"name": "Ada", "pitch": "Robots"}}, blind.HiddenFields(form, true), true)
+	assert.Equal(t, map[string]interface{}{"pitch": "Robots"}, view["data"])
+	assert.NotContains(t, view, "userID")
+}
@@ -0,0 +1,305 @@
+package forms
+
+import (
+	"api/internal/middlewares"
+	"api/internal/types"
+	"fmt"
+	"net/http"
+	"regexp"
+	"shared/models"
+	"shared/mongodb"
+	"shared/selectors"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sourceNameRegex matches the names event selector sources can have, they're used in URLs
+var sourceNameRegex = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// maxSourceUploadSize limits the size of an uploaded CSV list
+const maxSourceUploadSize = 2 << 20
+
+// selectorSourceRequest is an event selector source, CSV sources upload their list in CSV
+type selectorSourceRequest struct {
+	models.SelectorSource
+	CSV string `json:"csv"`
+}
+
+func RegisterSelectorSourceRoutes(r *gin.RouterGroup, params *types.RouteParams) {
+	r.POST("", middlewares.JWTAuthMiddleware(), createSelectorSourceHandler(params))
+	r.PUT(":source_id", middlewares.JWTAuthMiddleware(), updateSelectorSourceHandler(params))
+	r.DELETE(":source_id", middlewares.JWTAuthMiddleware(), deleteSelectorSourceHandler(params))
+}
+
+func createSelectorSourceHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		var req selectorSourceRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if req.EventID.IsZero() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "eventID is required"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, req.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to modify this event"})
+			return
+		}
+
+		source, ok := buildSelectorSource(c, req)
+		if !ok {
+			return
+		}
+
+		source.CreatedBy = authenticatedUser.ID
+		result, err := params.MongoService.CreateSource(c, source)
+		if err == mongodb.ErrSourceAlreadyExists {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A source named %s already exists on this event", source.SourceName)})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create source"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"id": result.InsertedID})
+	}
+}
+
+func updateSelectorSourceHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		sourceID, err := primitive.ObjectIDFromHex(c.Param("source_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+			return
+		}
+
+		var req selectorSourceRequest
+		if err := utils.BindJSON(c, &req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		existing, err := params.MongoService.GetSelectorSource(c, sourceID)
+		if err != nil || existing.IsBuiltin() {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, existing.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to update this source"})
+			return
+		}
+
+		// Sources can't move between events or change kind
+		req.EventID = existing.EventID
+		req.Kind = existing.Kind
+
+		source, ok := buildSelectorSource(c, req)
+		if !ok {
+			return
+		}
+
+		if source.SourceName != existing.SourceName {
+			if _, err := params.MongoService.GetEventSelectorSource(c, source.EventID, source.SourceName); err != mongo.ErrNoDocuments {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A source named %s already exists on this event", source.SourceName)})
+				return
+			}
+
+			if !checkSourceUnused(c, params, *existing) {
+				return
+			}
+		}
+
+		// URL sources keep their current options until the listener fetches the new URL
+		if source.Kind == models.SelectorSourceURL {
+			source.Options = existing.Options
+			source.LastError = existing.LastError
+			if source.URL == existing.URL && source.Format == existing.Format {
+				source.LastUpdated = existing.LastUpdated
+			}
+		}
+
+		if _, err := params.MongoService.UpdateSource(c, source, sourceID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update source"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Source updated successfully"})
+	}
+}
+
+func deleteSelectorSourceHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		sourceID, err := primitive.ObjectIDFromHex(c.Param("source_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid source ID"})
+			return
+		}
+
+		source, err := params.MongoService.GetSelectorSource(c, sourceID)
+		if err != nil || source.IsBuiltin() {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Source not found"})
+			return
+		}
+
+		if !mongodb.CanUserModifyEvent(c, params.MongoService, authenticatedUser, source.EventID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to delete this source"})
+			return
+		}
+
+		if !checkSourceUnused(c, params, *source) {
+			return
+		}
+
+		if _, err := params.MongoService.DeleteSelectorSource(c, sourceID); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete source"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Source deleted successfully"})
+	}
+}
+
+// buildSelectorSource validates a source request and parses an uploaded list, writing the error response if it's invalid
+func buildSelectorSource(c *gin.Context, req selectorSourceRequest) (models.SelectorSource, bool) {
+	source := req.SelectorSource
+	if errors := utils.ValidateStruct(utils.Validator, req); len(errors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": strings.Join(errors, "\n")})
+		return source, false
+	}
+
+	switch {
+	case source.Kind == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind is required"})
+		return source, false
+	case source.Kind == models.SelectorSourceCSV && req.CSV == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "csv is required"})
+		return source, false
+	case source.Kind == models.SelectorSourceURL && source.URL == "":
+		c.JSON(http.StatusBadRequest, gin.H{"error": "url is required"})
+		return source, false
+	}
+
+	if source.Kind == models.SelectorSourceURL {
+		if err := selectors.CheckRemoteURL(source.URL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The URL must use https and point to a public host"})
+			return source, false
+		}
+	}
+
+	if !sourceNameRegex.MatchString(source.SourceName) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Source names can only contain lowercase letters, numbers and -"})
+		return source, false
+	}
+
+	if _, ok := selectors.Builtin(source.SourceName); ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is the name of a built-in source", source.SourceName)})
+		return source, false
+	}
+
+	if source.Format == "" {
+		source.Format = selectors.FormatCSV
+	}
+
+	source.ID = primitive.NilObjectID
+	source.LastUpdated = time.Time{}
+	source.LastError = ""
+	source.Options = nil
+
+	if source.Kind == models.SelectorSourceCSV {
+		if len(req.CSV) > maxSourceUploadSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "The uploaded list is too large"})
+			return source, false
+		}
+
+		options, err := selectors.ParseOptions(strings.NewReader(req.CSV), source.Format)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return source, false
+		}
+
+		source.URL = ""
+		source.RefreshEveryHours = 0
+		source.Options = options
+		source.LastUpdated = time.Now()
+	}
+
+	return source, true
+}
+
+// checkSourceUnused makes sure none of the event's forms use a source before it's deleted or renamed,
+// writing the error response if one does
+func checkSourceUnused(c *gin.Context, params *types.RouteParams, source models.SelectorSource) bool {
+	forms, err := params.MongoService.ListForms(c, bson.M{"eventID": source.EventID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error checking where the source is used"})
+		return false
+	}
+
+	var usedBy []string
+	for _, form := range forms {
+		for _, field := range form.Attrs {
+			if field.AdditionalOptions.UseDefaultValuesFrom == source.SourceName {
+				usedBy = append(usedBy, form.Name)
+				break
+			}
+		}
+	}
+
+	if len(usedBy) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is used by %s", source.SourceName, strings.Join(usedBy, ", "))})
+		return false
+	}
+
+	return true
+}
+
+// checkSelectorSources checks every field's UseDefaultValuesFrom is a built-in or one of the event's sources,
+// writing the error response if not
+func checkSelectorSources(c *gin.Context, params *types.RouteParams, form models.FormStructure, eventID primitive.ObjectID) bool {
+	for _, field := range form.Attrs {
+		name := field.AdditionalOptions.UseDefaultValuesFrom
+		if name == "" {
+			continue
+		}
+
+		if _, ok := selectors.Builtin(name); ok {
+			continue
+		}
+
+		_, err := params.MongoService.GetEventSelectorSource(c, eventID, name)
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s uses %s, which isn't a source on this event", field.Question, name)})
+			return false
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error validating form selector sources"})
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,74 @@
+package forms
+
+import (
+	"api/internal/types"
+	"net/http"
+	"shared/mongodb"
+	"shared/utils"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func listFormVersionsHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this form's versions"})
+			return
+		}
+
+		versions, err := params.MongoService.ListFormVersions(c, formID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list form versions"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"versions": versions})
+	}
+}
+
+func getFormVersionHandler(params *types.RouteParams) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticatedUser, ok := utils.GetUserFromContext(c, true)
+		if !ok {
+			return
+		}
+
+		formID, err := primitive.ObjectIDFromHex(c.Param("form_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid form ID"})
+			return
+		}
+
+		version, err := strconv.Atoi(c.Param("version"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+			return
+		}
+
+		if !mongodb.CanUserModifyForm(c, params.MongoService, authenticatedUser, formID, nil) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "You are not authorized to view this form's versions"})
+			return
+		}
+
+		formVersion, err := params.MongoService.GetFormVersion(c, formID, version)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Form version not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, formVersion)
+	}
+}
@@ -27,9 +27,21 @@ func SetupRoutes(r *gin.Engine, params *types.RouteParams) {
 	forms.RegisterDefaultSelectorValues(formGroup, params)
 	forms.RegisterRoutes(formGroup, params)
 
+	selectorSourceGroup := r.Group("/selector_sources")
+	forms.RegisterSelectorSourceRoutes(selectorSourceGroup, params)
+
 	pipelineGroup := r.Group("/pipelines")
 	pipelines.RegisterRoutes(pipelineGroup, params)
 
 	emailTemplateGroup := r.Group("/email_templates")
 	emails.RegisterEmailTemplateRoutes(emailTemplateGroup, params)
+
+	emailCampaignGroup := r.Group("/email_campaigns")
+	emails.RegisterEmailCampaignRoutes(emailCampaignGroup, params)
+
+	emailSuppressionGroup := r.Group("/email_suppressions")
+	emails.RegisterEmailSuppressionRoutes(emailSuppressionGroup, params)
+
+	emailComponentGroup := r.Group("/email_components")
+	emails.RegisterEmailComponentRoutes(emailComponentGroup, params)
 }
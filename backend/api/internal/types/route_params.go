@@ -2,6 +2,7 @@ package types
 
 import (
 	"shared/mongodb"
+	"shared/storage"
 
 	"github.com/IBM/sarama"
 )
@@ -9,4 +10,5 @@ import (
 type RouteParams struct {
 	MongoService  mongodb.MongoService
 	KafkaProducer sarama.SyncProducer
+	FileStorage   storage.FileStorage
 }
@@ -4,12 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"event-listener/internal/handlers"
+	"event-listener/internal/jobs"
 	"event-listener/internal/types"
 	"fmt"
 	"log"
 	"shared/kafka"
 	"shared/models"
 	"shared/mongodb"
+	"shared/storage"
 	"shared/utils"
 	"time"
 
@@ -20,6 +22,8 @@ import (
 
 var actionHandlers = map[string]types.EventHandler{}
 
+var scheduledJobs = []types.ScheduledJob{}
+
 type consumerGroupHandler struct {
 	mongoService *mongodb.Service
 }
@@ -203,6 +207,20 @@ func main() {
 		"Webhook":         handlers.NewWebhookHandler(mongoService),
 	}
 
+	scheduledJobs = []types.ScheduledJob{
+		jobs.NewEmailCampaignJob(mongoService),
+		jobs.NewOrganizerDigestJob(mongoService),
+		jobs.NewAnonymousConfirmationJob(mongoService),
+		jobs.NewSelectorSourcesJob(mongoService),
+	}
+
+	fileStorage, err := storage.NewFileStorage()
+	if err != nil {
+		log.Printf("File storage is not configured, orphaned uploads will not be cleaned up: %v", err)
+	} else {
+		scheduledJobs = append(scheduledJobs, jobs.NewOrphanedUploadsJob(mongoService, fileStorage))
+	}
+
 	if utils.RunningInAWSLambda() {
 		// Lambda start logic if applicable
 	} else {
@@ -218,6 +236,8 @@ func main() {
 			mongoService: mongoService,
 		}
 		ctx := context.Background()
+		startScheduledJobs(ctx, scheduledJobs)
+
 		for {
 			if err := consumer.Consume(ctx, topics, handler); err != nil {
 				log.Printf("Error from consumer: %v", err)
@@ -234,6 +254,28 @@ func main() {
 	cleanup()
 }
 
+// startScheduledJobs runs each job on its own ticker until the context is cancelled
+func startScheduledJobs(ctx context.Context, jobs []types.ScheduledJob) {
+	for _, job := range jobs {
+		go func(job types.ScheduledJob) {
+			ticker := time.NewTicker(job.Interval())
+			defer ticker.Stop()
+
+			for {
+				if err := job.Run(ctx); err != nil {
+					log.Printf("Error running scheduled job %s: %v", job.Name(), err)
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}(job)
+	}
+}
+
 // Helper function to manage some of the logic around writing the status of a pipeline action
 func writePipelineActionMessageProcessed(ctx context.Context, mongoService *mongodb.Service, pipelineRun *models.PipelineRun, actionID primitive.ObjectID, errMsg string) error {
 	// Note: this is a pretty messy function
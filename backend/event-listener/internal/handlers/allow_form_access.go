@@ -3,12 +3,31 @@ package handlers
 import (
 	"context"
 	"errors"
+	"event-listener/internal/mailer"
+	"fmt"
+	"log"
+	"os"
 	"shared/kafka"
 	"shared/models"
 	"shared/mongodb"
+	"shared/utils"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// publicAppURL is the externally reachable frontend url, used to build invite links
+var publicAppURL string
+
+func init() {
+	publicAppURL = strings.TrimSuffix(os.Getenv("PUBLIC_APP_URL"), "/")
+	if publicAppURL == "" {
+		log.Println("[WARNING] PUBLIC_APP_URL is not set, AllowFormAccess actions can't send invite links.")
+	}
+}
+
 type AllowFormAccessHandler struct {
 	mongo *mongodb.Service
 }
@@ -33,6 +52,10 @@ func (s AllowFormAccessHandler) HandleAction(action kafka.PipelineActionMessage)
 		return errors.New("could not find email in data")
 	}
 
+	if allowFormAccessAction.Options.IssueInvite {
+		return s.issueInvite(allowFormAccessAction, email)
+	}
+
 	newSubmitter := models.FormAllowedSubmitter{
 		Email: email,
 	}
@@ -65,3 +88,64 @@ func (s AllowFormAccessHandler) HandleAction(action kafka.PipelineActionMessage)
 
 	return nil
 }
+
+// issueInvite creates an invite bound to the email and sends its link with the action's invite email template
+func (s AllowFormAccessHandler) issueInvite(action *kafka.AllowFormAccessMessage, email string) error {
+	if publicAppURL == "" {
+		return errors.New("PUBLIC_APP_URL is not set")
+	}
+
+	form, err := s.mongo.GetForm(context.TODO(), action.ToFormID, false)
+	if err != nil {
+		return err
+	}
+
+	secretData, err := s.mongo.GetEventSecrets(context.TODO(), bson.M{"eventID": form.EventID}, false)
+	if err != nil || secretData.Email == nil {
+		return ErrRequiredSecretNotFound
+	}
+
+	emailTemplate, err := s.mongo.GetEmailTemplate(context.TODO(), action.Options.InviteEmailTemplateID)
+	if err != nil {
+		return ErrEmailTemplateNotFound
+	}
+
+	emailTemplate, _, err = mailer.ResolveTemplate(context.TODO(), s.mongo, emailTemplate)
+	if err != nil {
+		return err
+	}
+
+	// Invites sent by a pipeline are single use unless the action says otherwise
+	invite := models.FormInvite{
+		FormID:  form.ID,
+		EventID: form.EventID,
+		Label:   "Sent by pipeline",
+		Email:   email,
+		MaxUses: action.Options.InviteMaxUses,
+	}
+	if invite.MaxUses == 0 {
+		invite.MaxUses = 1
+	}
+	if action.Options.ExpiresInHours > 0 {
+		invite.ExpiresAt = time.Now().Add(time.Hour * time.Duration(action.Options.ExpiresInHours))
+	}
+
+	result, err := s.mongo.CreateFormInvite(context.TODO(), invite)
+	if err != nil {
+		return err
+	}
+	inviteID := result.InsertedID.(primitive.ObjectID)
+
+	data := make(map[string]interface{}, len(action.Data)+1)
+	for key, value := range action.Data {
+		data[key] = value
+	}
+	data[utils.FormInviteLinkPlaceholder] = formInviteLink(form, utils.GenerateFormInviteToken(inviteID))
+
+	return mailer.SendTemplate(context.TODO(), s.mongo, secretData.Email, form.EventID, emailTemplate, email, data)
+}
+
+// formInviteLink is the frontend page for the form with the invite token attached
+func formInviteLink(form *models.FormStructure, token string) string {
+	return fmt.Sprintf("%s/events/%s/participant/form/%s?invite=%s", publicAppURL, form.EventID.Hex(), form.ID.Hex(), token)
+}
@@ -3,14 +3,10 @@ package handlers
 import (
 	"context"
 	"errors"
-	"fmt"
-	"net/smtp"
+	"event-listener/internal/mailer"
 	"shared/kafka"
 	"shared/mongodb"
-	"strings"
-	"time"
 
-	"github.com/google/uuid"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -71,51 +67,15 @@ func (s SendEmailHandler) HandleAction(action kafka.PipelineActionMessage) error
 		return ErrNoToEmailFound
 	}
 
-	toAddresses := []string{to}
-	toHeader := "To: " + strings.Join(toAddresses, ", ") + "\r\n"
-
-	// TODO: BCC & CC
-	//to = append(to, emailTemplate.CC...)
-	//to = append(to, emailTemplate.BCC...)
-
-	// Prepare the email headers and body
-	subject := "Subject: " + emailTemplate.Subject + "\r\n"
-	from := "From: " + emailTemplate.From + "\r\n"
-
-	var replyTo string
-	if emailTemplate.ReplyTo != "" {
-		replyTo = "Reply-To: " + emailTemplate.ReplyTo + "\r\n"
-	} else {
-		replyTo = "Reply-To: " + emailTemplate.From + "\r\n"
-	}
-
-	dateHeader := "Date: " + time.Now().Format("Mon, 02 Jan 2006 15:04:05 -0700") + "\r\n"
-	messageID := fmt.Sprintf("Message-ID: <%s@%s>\r\n", uuid.NewString(), smtpConfig.SMTPServer)
-
-	var body string = emailTemplate.Body
-	mime := "MIME-Version: 1.0\r\n"
-	if emailTemplate.IsHTML {
-		mime += "Content-Type: text/html; charset=\"UTF-8\"\r\n"
-		body = "<html><body>" + emailTemplate.Body + "</body></html>"
-	} else {
-		mime += "Content-Type: text/plain; charset=\"UTF-8\"\r\n"
+	emailTemplate, components, err := mailer.ResolveTemplate(context.TODO(), s.mongo, emailTemplate)
+	if err != nil {
+		return err
 	}
 
-	message := []byte(subject + from + toHeader + replyTo + dateHeader + messageID + mime + "\r\n" + body)
-
-	// SMTP server configuration
-	smtpHost := smtpConfig.SMTPServer
-	smtpPort := fmt.Sprintf("%d", smtpConfig.Port)
-	address := smtpHost + ":" + smtpPort
-
-	// Authentication
-	auth := smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpHost)
-
-	// Sending email
-	err = smtp.SendMail(address, auth, emailTemplate.From, toAddresses, message)
-	if err != nil {
+	// Record the versions being sent so later edits don't change what the run shows was sent
+	if _, err := s.mongo.SetPipelineActionEmailTemplateVersion(context.TODO(), sendEmailAction.PipelineRunID, sendEmailAction.ActionID, emailTemplate.Version, components); err != nil {
 		return err
 	}
 
-	return nil
+	return mailer.SendTemplate(context.TODO(), s.mongo, smtpConfig, sendEmailAction.EventID, emailTemplate, to, sendEmailAction.Data)
 }
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"event-listener/internal/mailer"
+	"fmt"
+	"log"
+	"net/url"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AnonymousConfirmationJob emails the confirmation link for anonymous responses and deletes the ones that expired.
+// It also emails accounts registered with an email anonymous responses were sent with a link to claim them.
+type AnonymousConfirmationJob struct {
+	mongo *mongodb.Service
+}
+
+func NewAnonymousConfirmationJob(mongo *mongodb.Service) *AnonymousConfirmationJob {
+	return &AnonymousConfirmationJob{mongo: mongo}
+}
+
+func (j AnonymousConfirmationJob) Name() string {
+	return "anonymous-confirmations"
+}
+
+func (j AnonymousConfirmationJob) Interval() time.Duration {
+	return 15 * time.Second
+}
+
+func (j AnonymousConfirmationJob) Run(ctx context.Context) error {
+	if _, err := j.mongo.DeleteExpiredPendingResponses(ctx); err != nil {
+		return err
+	}
+
+	// Checked before claiming so the responses are sent once it's set
+	if mailer.PublicAPIURL() == "" {
+		return errors.New("PUBLIC_API_URL is not set")
+	}
+
+	if err := j.sendAccountLinks(ctx); err != nil {
+		return err
+	}
+
+	for {
+		pending, err := j.mongo.ClaimPendingResponseConfirmation(ctx)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+			return err
+		}
+
+		// The applicant can submit again to get another email once the resend cooldown has passed
+		if err := j.send(ctx, pending); err != nil {
+			log.Printf("Failed to send confirmation for pending response %s: %v", pending.ID.Hex(), err)
+		}
+	}
+}
+
+// sendAccountLinks emails the link confirming anonymous responses belong to the account registered with their email
+func (j AnonymousConfirmationJob) sendAccountLinks(ctx context.Context) error {
+	for {
+		user, err := j.mongo.ClaimAnonymousLinkRequest(ctx)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+			return err
+		}
+
+		if err := j.sendAccountLink(ctx, user); err != nil {
+			log.Printf("Failed to send the anonymous response link to user %s: %v", user.ID.Hex(), err)
+		}
+	}
+}
+
+// sendAccountLink sends the link through the event of the user's latest anonymous response, from the address its
+// confirmation emails are sent from
+func (j AnonymousConfirmationJob) sendAccountLink(ctx context.Context, user *models.User) error {
+	responses, err := j.mongo.ListResponses(ctx, mongodb.UnlinkedAnonymousFilter(user.Email),
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+
+	form, err := j.mongo.GetForm(ctx, responses[0].FormID, true)
+	if err != nil {
+		return err
+	}
+
+	secretData, err := j.mongo.GetEventSecrets(ctx, bson.M{"eventID": form.EventID}, false)
+	if err != nil || secretData.Email == nil {
+		return errors.New("event secrets not found")
+	}
+
+	emailTemplate, err := j.mongo.GetEmailTemplate(ctx, form.AnonymousConfirmationTemplateID)
+	if err != nil {
+		return errors.New("email template not found")
+	}
+
+	suppressed, err := j.mongo.IsEmailSuppressed(ctx, user.Email, form.EventID, false)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return mailer.ErrRecipientSuppressed
+	}
+
+	token, err := utils.GenerateAnonymousLinkToken(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+	link := fmt.Sprintf("%s/auth/link_anonymous_responses?token=%s", mailer.PublicAPIURL(), url.QueryEscape(token))
+
+	return mailer.Send(secretData.Email, mailer.Message{
+		From:    emailTemplate.From,
+		To:      []string{user.Email},
+		ReplyTo: emailTemplate.ReplyTo,
+		Subject: "Add your responses to your new account",
+		Body: "An account was registered with this email address. Responses you sent without an account aren't added to it " +
+			"until you confirm they're yours.\r\n\r\nOpen this link to add them: " + link +
+			"\r\n\r\nIf you didn't register, ignore this email and your responses stay unlinked.",
+	})
+}
+
+func (j AnonymousConfirmationJob) send(ctx context.Context, pending *models.PendingResponse) error {
+	form, err := j.mongo.GetForm(ctx, pending.FormID, true)
+	if err != nil {
+		return err
+	}
+
+	secretData, err := j.mongo.GetEventSecrets(ctx, bson.M{"eventID": form.EventID}, false)
+	if err != nil || secretData.Email == nil {
+		return errors.New("event secrets not found")
+	}
+
+	emailTemplate, err := j.mongo.GetEmailTemplate(ctx, form.AnonymousConfirmationTemplateID)
+	if err != nil {
+		return errors.New("email template not found")
+	}
+
+	emailTemplate, _, err = mailer.ResolveTemplate(ctx, j.mongo, emailTemplate)
+	if err != nil {
+		return err
+	}
+
+	data := make(map[string]interface{}, len(pending.Data)+1)
+	for key, value := range pending.Data {
+		data[key] = value
+	}
+	data[utils.ResponseConfirmLinkPlaceholder] = fmt.Sprintf("%s/forms/%s/responses/anonymous/confirm?token=%s",
+		mailer.PublicAPIURL(), form.ID.Hex(), url.QueryEscape(utils.GeneratePendingResponseToken(pending.ID)))
+
+	return mailer.SendTemplate(ctx, j.mongo, secretData.Email, form.EventID, emailTemplate, pending.Email, data)
+}
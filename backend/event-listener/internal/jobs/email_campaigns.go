@@ -0,0 +1,189 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"event-listener/internal/mailer"
+	"fmt"
+	"log"
+	"shared/models"
+	"shared/mongodb"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// defaultCampaignRatePerMinute is used when a campaign does not set its own rate
+const defaultCampaignRatePerMinute = 60
+
+// errCampaignClaimLost stops a sender whose campaign went stale and was claimed by another sender
+var errCampaignClaimLost = errors.New("campaign was claimed by another sender")
+
+// EmailCampaignJob sends any email campaign whose scheduled time has passed
+type EmailCampaignJob struct {
+	mongo *mongodb.Service
+}
+
+func NewEmailCampaignJob(mongo *mongodb.Service) *EmailCampaignJob {
+	return &EmailCampaignJob{mongo: mongo}
+}
+
+func (j EmailCampaignJob) Name() string {
+	return "email-campaigns"
+}
+
+func (j EmailCampaignJob) Interval() time.Duration {
+	return 30 * time.Second
+}
+
+func (j EmailCampaignJob) Run(ctx context.Context) error {
+	for {
+		campaign, err := j.mongo.ClaimScheduledEmailCampaign(ctx)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil
+			}
+			return err
+		}
+
+		status := models.EmailCampaignCompleted
+		errMsg := ""
+		if err := j.send(ctx, campaign); errors.Is(err, errCampaignClaimLost) {
+			log.Printf("Email campaign %s was resumed by another sender", campaign.ID.Hex())
+			continue
+		} else if err != nil {
+			log.Printf("Email campaign %s failed: %v", campaign.ID.Hex(), err)
+			status = models.EmailCampaignFailed
+			errMsg = err.Error()
+		}
+
+		if _, err := j.mongo.FinishEmailCampaign(ctx, campaign.ID, campaign.ClaimID, status, errMsg); err != nil {
+			return err
+		}
+	}
+}
+
+type campaignRecipient struct {
+	response models.FormResponse
+	email    string
+}
+
+func (j EmailCampaignJob) send(ctx context.Context, campaign *models.EmailCampaign) error {
+	secretData, err := j.mongo.GetEventSecrets(ctx, bson.M{"eventID": campaign.EventID}, false)
+	if err != nil || secretData.Email == nil {
+		return errors.New("event secrets not found")
+	}
+
+	emailTemplate, err := j.mongo.GetEmailTemplate(ctx, campaign.EmailTemplateID)
+	if err != nil {
+		return errors.New("email template not found")
+	}
+
+	emailTemplate, components, err := mailer.ResolveTemplate(ctx, j.mongo, emailTemplate)
+	if err != nil {
+		return err
+	}
+
+	// Fail the campaign up front rather than once per recipient
+	if emailTemplate.IsMarketing() && mailer.PublicAPIURL() == "" {
+		return mailer.ErrNoUnsubscribeLink
+	}
+
+	recipients, err := j.resolveRecipients(ctx, campaign)
+	if err != nil {
+		return err
+	}
+
+	result, err := j.mongo.StartEmailCampaignDelivery(ctx, campaign.ID, campaign.ClaimID, len(recipients), emailTemplate.Version, components)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errCampaignClaimLost
+	}
+
+	// A campaign resumed after its sender died skips whoever that sender already emailed
+	attempted := map[string]bool{}
+	for _, email := range campaign.Attempted {
+		attempted[email] = true
+	}
+
+	rate := campaign.RatePerMinute
+	if rate <= 0 {
+		rate = defaultCampaignRatePerMinute
+	}
+	throttle := time.NewTicker(time.Minute / time.Duration(rate))
+	defer throttle.Stop()
+
+	sent := 0
+	for _, recipient := range recipients {
+		if attempted[strings.ToLower(recipient.email)] {
+			continue
+		}
+
+		if sent > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-throttle.C:
+			}
+		}
+
+		var failure *models.EmailCampaignFailure
+		if err := mailer.SendTemplate(ctx, j.mongo, secretData.Email, campaign.EventID, emailTemplate, recipient.email, recipient.response.Data); err != nil {
+			failure = &models.EmailCampaignFailure{
+				ResponseID: recipient.response.ID,
+				Email:      recipient.email,
+				ErrorMsg:   err.Error(),
+				FailedAt:   time.Now(),
+			}
+		}
+
+		sent++
+
+		updated, err := j.mongo.RecordEmailCampaignDelivery(ctx, campaign.ID, campaign.ClaimID, recipient.email, failure)
+		if err == mongo.ErrNoDocuments {
+			return errCampaignClaimLost
+		} else if err != nil {
+			return err
+		}
+
+		if updated.Status == models.EmailCampaignCancelled {
+			log.Printf("Email campaign %s was cancelled after %d emails", campaign.ID.Hex(), sent)
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// resolveRecipients finds every response matching the campaign's filter, one email per address
+func (j EmailCampaignJob) resolveRecipients(ctx context.Context, campaign *models.EmailCampaign) ([]campaignRecipient, error) {
+	responses, err := j.mongo.ListResponses(ctx, mongodb.ResponseFilterToBSON(campaign.Filter), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list responses: %w", err)
+	}
+
+	// Oldest responses first so the send order is stable
+	sort.Slice(responses, func(a, b int) bool {
+		return responses[a].CreatedAt.Before(responses[b].CreatedAt)
+	})
+
+	seen := map[string]bool{}
+	var recipients []campaignRecipient
+	for _, response := range responses {
+		email, ok := response.Data[campaign.EmailFieldID].(string)
+		email = strings.TrimSpace(email)
+		if !ok || email == "" || seen[strings.ToLower(email)] {
+			continue
+		}
+
+		seen[strings.ToLower(email)] = true
+		recipients = append(recipients, campaignRecipient{response: response, email: email})
+	}
+
+	return recipients, nil
+}
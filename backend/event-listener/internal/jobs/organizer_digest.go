@@ -0,0 +1,284 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"event-listener/internal/mailer"
+	"fmt"
+	"log"
+	"shared/models"
+	"shared/mongodb"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// digestSubmissionWarningRatio is how full a form has to be before organizers are warned about MaxSubmissions
+	digestSubmissionWarningRatio = 0.9
+
+	// digestClosingWarning is how long before CloseSubmissionsAt organizers are warned
+	digestClosingWarning = 48 * time.Hour
+
+	// digestMaxFailureMessages limits how many pipeline errors are listed per pipeline
+	digestMaxFailureMessages = 3
+)
+
+// OrganizerDigestJob sends each subscribed organizer a summary of their event since their last digest
+type OrganizerDigestJob struct {
+	mongo *mongodb.Service
+}
+
+func NewOrganizerDigestJob(mongo *mongodb.Service) *OrganizerDigestJob {
+	return &OrganizerDigestJob{mongo: mongo}
+}
+
+func (j OrganizerDigestJob) Name() string {
+	return "organizer-digests"
+}
+
+func (j OrganizerDigestJob) Interval() time.Duration {
+	return time.Minute
+}
+
+func (j OrganizerDigestJob) Run(ctx context.Context) error {
+	now := time.Now()
+	subscriptions, err := j.mongo.ListDueDigestSubscriptions(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, subscription := range subscriptions {
+		// Claim before sending so a digest is never sent twice, a failed digest is skipped rather than retried every minute
+		claimed, err := j.mongo.ClaimDigestSubscription(ctx, subscription, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := j.send(ctx, subscription, now); err != nil {
+			log.Printf("Failed to send digest %s: %v", subscription.ID.Hex(), err)
+		}
+	}
+
+	return nil
+}
+
+type digestFormCount struct {
+	form  models.FormStructure
+	count int64
+}
+
+type digestPipelineFailures struct {
+	pipeline models.PipelineConfiguration
+	count    int
+	errors   []string
+}
+
+// organizerDigest is everything that happened on an event between since and until
+type organizerDigest struct {
+	event        models.Event
+	since        time.Time
+	until        time.Time
+	newResponses []digestFormCount
+	failures     []digestPipelineFailures
+	warnings     []string
+}
+
+func (d organizerDigest) isEmpty() bool {
+	return len(d.newResponses) == 0 && len(d.failures) == 0 && len(d.warnings) == 0
+}
+
+func (j OrganizerDigestJob) send(ctx context.Context, subscription models.DigestSubscription, now time.Time) error {
+	events, err := j.mongo.ListEventsMetadata(ctx, bson.M{"_id": subscription.EventID})
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return errors.New("event not found")
+	}
+	event := events[0]
+
+	// The organizer may have been removed from the event since subscribing
+	isOrganizer, err := j.mongo.IsEventOrganizer(ctx, subscription.EventID, subscription.UserID)
+	if err != nil {
+		return err
+	}
+	if !isOrganizer {
+		return nil
+	}
+
+	user, err := j.mongo.FindUserByID(ctx, subscription.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to find organizer: %w", err)
+	}
+
+	secretData, err := j.mongo.GetEventSecrets(ctx, bson.M{"eventID": subscription.EventID}, false)
+	if err != nil || secretData.Email == nil {
+		return errors.New("event secrets not found")
+	}
+
+	from := digestFromAddress(secretData.Email)
+	if from == "" {
+		return errors.New("the event's email configuration has no address to send digests from")
+	}
+
+	digest, err := j.build(ctx, event, subscription.LastSentAt, now)
+	if err != nil {
+		return err
+	}
+
+	// Nothing happened, so nothing to send
+	if digest.isEmpty() {
+		return nil
+	}
+
+	return mailer.Send(secretData.Email, mailer.Message{
+		From:    from,
+		To:      []string{user.Email},
+		Subject: fmt.Sprintf("%s: %s digest", event.Metadata.Name, subscription.Frequency),
+		Body:    digest.render(),
+	})
+}
+
+// build collects the new responses, pipeline failures and forms nearing their limits between since and until
+func (j OrganizerDigestJob) build(ctx context.Context, event models.Event, since time.Time, until time.Time) (organizerDigest, error) {
+	digest := organizerDigest{event: event, since: since, until: until}
+
+	forms, err := j.mongo.ListForms(ctx, bson.M{"eventID": event.ID, "isDeleted": bson.M{"$ne": true}})
+	if err != nil {
+		return digest, fmt.Errorf("failed to list forms: %w", err)
+	}
+
+	for _, form := range forms {
+		newCount, err := j.mongo.CountResponses(ctx, bson.M{"formID": form.ID, "isDeleted": bson.M{"$ne": true}, "createdAt": bson.M{"$gte": since, "$lt": until}})
+		if err != nil {
+			return digest, fmt.Errorf("failed to count responses: %w", err)
+		}
+		if newCount > 0 {
+			digest.newResponses = append(digest.newResponses, digestFormCount{form: form, count: newCount})
+		}
+
+		// Forms are only reported once, in the digest covering the moment they crossed a limit
+		// Only responses holding a place count towards the limit, waitlisted and released ones don't
+		if form.MaxSubmissions > 0 {
+			total, err := j.mongo.CountResponses(ctx, mongodb.PlaceHoldersFilter(form.ID))
+			if err != nil {
+				return digest, fmt.Errorf("failed to count responses: %w", err)
+			}
+
+			newPlaces := mongodb.PlaceHoldersFilter(form.ID)
+			newPlaces["createdAt"] = bson.M{"$gte": since, "$lt": until}
+			newPlaceCount, err := j.mongo.CountResponses(ctx, newPlaces)
+			if err != nil {
+				return digest, fmt.Errorf("failed to count responses: %w", err)
+			}
+
+			threshold := int64(float64(form.MaxSubmissions) * digestSubmissionWarningRatio)
+			if total >= threshold && total-newPlaceCount < threshold {
+				digest.warnings = append(digest.warnings, fmt.Sprintf("%s has %d of its %d maximum submissions", form.Name, total, form.MaxSubmissions))
+			}
+		}
+
+		if !form.CloseSubmissionsAt.IsZero() {
+			warnAt := form.CloseSubmissionsAt.Add(-digestClosingWarning)
+			if !warnAt.Before(since) && warnAt.Before(until) {
+				digest.warnings = append(digest.warnings, fmt.Sprintf("%s closes for submissions at %s", form.Name, form.CloseSubmissionsAt.UTC().Format(time.RFC1123)))
+			}
+		}
+	}
+
+	pipelines, err := j.mongo.ListPipelines(ctx, bson.M{"eventID": event.ID})
+	if err != nil {
+		return digest, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+
+	if len(pipelines) > 0 {
+		pipelineIDs := make([]primitive.ObjectID, len(pipelines))
+		for i, pipeline := range pipelines {
+			pipelineIDs[i] = pipeline.ID
+		}
+
+		runs, err := j.mongo.ListPipelineRuns(ctx, bson.M{
+			"pipelineID":  bson.M{"$in": pipelineIDs},
+			"status":      models.PipelineRunFailure,
+			"completedAt": bson.M{"$gte": since, "$lt": until},
+		}, nil)
+		if err != nil {
+			return digest, fmt.Errorf("failed to list pipeline runs: %w", err)
+		}
+
+		for _, pipeline := range pipelines {
+			failures := digestPipelineFailures{pipeline: pipeline}
+			for _, run := range runs {
+				if run.PipelineID != pipeline.ID {
+					continue
+				}
+
+				failures.count++
+				for _, action := range run.ActionStatuses {
+					if action.ErrorMsg != "" && len(failures.errors) < digestMaxFailureMessages {
+						failures.errors = append(failures.errors, action.ErrorMsg)
+					}
+				}
+			}
+
+			if failures.count > 0 {
+				digest.failures = append(digest.failures, failures)
+			}
+		}
+	}
+
+	return digest, nil
+}
+
+// render formats the digest as a plain text email
+func (d organizerDigest) render() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Here is what happened on %s between %s and %s (UTC).\r\n", d.event.Metadata.Name, d.since.UTC().Format(time.RFC1123), d.until.UTC().Format(time.RFC1123))
+
+	if len(d.newResponses) > 0 {
+		b.WriteString("\r\nNew responses:\r\n")
+		for _, form := range d.newResponses {
+			fmt.Fprintf(&b, "  - %s: %d\r\n", form.form.Name, form.count)
+		}
+	}
+
+	if len(d.failures) > 0 {
+		b.WriteString("\r\nFailed pipeline runs:\r\n")
+		for _, failure := range d.failures {
+			fmt.Fprintf(&b, "  - %s: %d\r\n", failure.pipeline.Name, failure.count)
+			for _, errMsg := range failure.errors {
+				fmt.Fprintf(&b, "      %s\r\n", errMsg)
+			}
+		}
+	}
+
+	if len(d.warnings) > 0 {
+		b.WriteString("\r\nNeeds attention:\r\n")
+		for _, warning := range d.warnings {
+			fmt.Fprintf(&b, "  - %s\r\n", warning)
+		}
+	}
+
+	return b.String()
+}
+
+// digestFromAddress picks an address the event's SMTP server will accept mail from
+func digestFromAddress(smtpConfig *models.EmailSecret) string {
+	if strings.Contains(smtpConfig.Username, "@") {
+		return smtpConfig.Username
+	}
+
+	for _, allowed := range smtpConfig.AllowedFrom {
+		if !strings.HasPrefix(allowed, "@") {
+			return allowed
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"shared/mongodb"
+	"shared/storage"
+	"time"
+)
+
+// orphanedUploadMaxAge is how long an applicant has to submit a response after uploading a file
+const orphanedUploadMaxAge = 24 * time.Hour
+
+// OrphanedUploadsJob deletes uploaded files that were never attached to a response
+type OrphanedUploadsJob struct {
+	mongo   *mongodb.Service
+	storage storage.FileStorage
+}
+
+func NewOrphanedUploadsJob(mongo *mongodb.Service, fileStorage storage.FileStorage) *OrphanedUploadsJob {
+	return &OrphanedUploadsJob{mongo: mongo, storage: fileStorage}
+}
+
+func (j OrphanedUploadsJob) Name() string {
+	return "orphaned-uploads"
+}
+
+func (j OrphanedUploadsJob) Interval() time.Duration {
+	return time.Hour
+}
+
+func (j OrphanedUploadsJob) Run(ctx context.Context) error {
+	uploads, err := j.mongo.ListOrphanedFileUploads(ctx, time.Now().Add(-orphanedUploadMaxAge))
+	if err != nil {
+		return err
+	}
+
+	for _, upload := range uploads {
+		// The file is removed first so a failure leaves the record for the next run to retry
+		if err := j.storage.Delete(ctx, upload.StorageKey); err != nil && err != storage.ErrFileNotFound {
+			log.Printf("Failed to delete orphaned upload %s: %v", upload.ID.Hex(), err)
+			continue
+		}
+
+		if _, err := j.mongo.DeleteFileUpload(ctx, upload.ID); err != nil {
+			return err
+		}
+	}
+
+	if len(uploads) > 0 {
+		log.Printf("Deleted %d orphaned uploads", len(uploads))
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"shared/models"
+	"shared/mongodb"
+	"shared/selectors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// selectorFetchTimeout limits how long a single source can take to fetch
+const selectorFetchTimeout = 30 * time.Second
+
+// SelectorSourcesJob refreshes the cached options of remote selector sources once they're older than their
+// max age, so the API never fetches them while answering a request
+type SelectorSourcesJob struct {
+	mongo *mongodb.Service
+}
+
+func NewSelectorSourcesJob(mongo *mongodb.Service) *SelectorSourcesJob {
+	return &SelectorSourcesJob{mongo: mongo}
+}
+
+func (j SelectorSourcesJob) Name() string {
+	return "selector-sources"
+}
+
+func (j SelectorSourcesJob) Interval() time.Duration {
+	return 5 * time.Minute
+}
+
+func (j SelectorSourcesJob) Run(ctx context.Context) error {
+	for _, source := range selectors.Builtins() {
+		if source.MaxAge() == 0 {
+			continue
+		}
+
+		cached, err := j.mongo.GetSourceByName(ctx, source.Name())
+		if err == mongo.ErrNoDocuments {
+			result, err := j.mongo.CreateSource(ctx, models.SelectorSource{SourceName: source.Name()})
+			if err != nil {
+				return err
+			}
+			cached = &models.SelectorSource{ID: result.InsertedID.(primitive.ObjectID)}
+		} else if err != nil {
+			return err
+		}
+
+		j.refresh(ctx, source, *cached)
+	}
+
+	eventSources, err := j.mongo.ListSelectorSources(ctx, bson.M{"kind": models.SelectorSourceURL})
+	if err != nil {
+		return err
+	}
+
+	for _, cached := range eventSources {
+		j.refresh(ctx, selectors.NewEventRemoteSource(cached), cached)
+	}
+
+	return nil
+}
+
+// refresh fetches the source if its cached options are stale, a failure is recorded on the source
+// and retried on the next run
+func (j SelectorSourcesJob) refresh(ctx context.Context, source selectors.Source, cached models.SelectorSource) {
+	if time.Since(cached.LastUpdated) < source.MaxAge() {
+		return
+	}
+
+	fetchCtx, cancel := context.WithTimeout(ctx, selectorFetchTimeout)
+	defer cancel()
+
+	options, fetchErr := source.Fetch(fetchCtx)
+	if fetchErr != nil {
+		log.Printf("Failed to refresh selector source %s: %v", source.Name(), fetchErr)
+	}
+
+	// Organizers see the recorded error, so network details stay in the log
+	if err := j.mongo.SaveSelectorSourceRefresh(ctx, cached.ID, options, selectors.RefreshError(fetchErr)); err != nil {
+		log.Printf("Failed to save selector source %s: %v", source.Name(), err)
+	}
+}
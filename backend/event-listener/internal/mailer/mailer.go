@@ -0,0 +1,187 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/smtp"
+	"net/url"
+	"os"
+	"shared/models"
+	"shared/mongodb"
+	"shared/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrRecipientSuppressed is returned when the recipient is on a suppression list for the email being sent
+var ErrRecipientSuppressed = errors.New("recipient has unsubscribed from these emails")
+
+// ErrNoUnsubscribeLink is returned instead of sending a marketing email that would have no way to unsubscribe
+var ErrNoUnsubscribeLink = errors.New("PUBLIC_API_URL is not set, marketing emails can't include an unsubscribe link")
+
+// publicAPIURL is the externally reachable API url, used to build unsubscribe links
+var publicAPIURL string
+
+func init() {
+	publicAPIURL = strings.TrimSuffix(os.Getenv("PUBLIC_API_URL"), "/")
+	if publicAPIURL == "" {
+		log.Println("[WARNING] PUBLIC_API_URL is not set, marketing emails won't be sent and anonymous responses can't be confirmed.")
+	}
+}
+
+// PublicAPIURL returns the externally reachable API url links in emails point at, empty if it isn't set
+func PublicAPIURL() string {
+	return publicAPIURL
+}
+
+// Message is a single rendered email ready to be sent over SMTP
+type Message struct {
+	From    string
+	To      []string
+	ReplyTo string
+	Subject string
+	Body    string
+	IsHTML  bool
+	Headers map[string]string
+}
+
+// ResolveTemplate returns a copy of the template with its body composed with the event's layout and partials,
+// and the versions of the layout and partials it used. Templates should be resolved once before being passed to SendTemplate.
+func ResolveTemplate(ctx context.Context, mongo *mongodb.Service, template *models.EmailTemplate) (*models.EmailTemplate, []models.EmailComponentRef, error) {
+	components, err := mongo.ListEmailComponents(ctx, bson.M{"eventID": template.EventID})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, used, err := utils.ComposeEmailTemplate(*template, components)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to compose email template: %w", err)
+	}
+
+	resolved := *template
+	resolved.Body = body
+	return &resolved, used, nil
+}
+
+// SendTemplate renders a template for a single recipient and sends it, unless the recipient is suppressed.
+// Marketing templates check the event's suppression list as well as the global one and get an unsubscribe link.
+func SendTemplate(ctx context.Context, mongo *mongodb.Service, smtpConfig *models.EmailSecret, eventID primitive.ObjectID, template *models.EmailTemplate, to string, data map[string]interface{}) error {
+	suppressed, err := mongo.IsEmailSuppressed(ctx, to, eventID, template.IsMarketing())
+	if err != nil {
+		return err
+	}
+
+	if suppressed {
+		return ErrRecipientSuppressed
+	}
+
+	msg := NewMessageFromTemplate(template, to, data)
+	if template.IsMarketing() {
+		if err := addUnsubscribeLink(&msg, to, eventID); err != nil {
+			return err
+		}
+	}
+
+	return Send(smtpConfig, msg)
+}
+
+// addUnsubscribeLink adds the List-Unsubscribe headers and a footer link to the message
+func addUnsubscribeLink(msg *Message, to string, eventID primitive.ObjectID) error {
+	if publicAPIURL == "" {
+		return ErrNoUnsubscribeLink
+	}
+
+	token, err := utils.GenerateUnsubscribeToken(to, eventID)
+	if err != nil {
+		return err
+	}
+	unsubscribeURL := publicAPIURL + "/email_suppressions/unsubscribe?token=" + url.QueryEscape(token)
+
+	if msg.Headers == nil {
+		msg.Headers = map[string]string{}
+	}
+	msg.Headers["List-Unsubscribe"] = "<" + unsubscribeURL + ">"
+	msg.Headers["List-Unsubscribe-Post"] = "List-Unsubscribe=One-Click"
+
+	if msg.IsHTML {
+		msg.Body += `<p style="font-size:12px"><a href="` + unsubscribeURL + `">Unsubscribe</a> from these emails.</p>`
+	} else {
+		msg.Body += "\r\n\r\nUnsubscribe from these emails: " + unsubscribeURL
+	}
+
+	return nil
+}
+
+// NewMessageFromTemplate renders an email template's subject and body against a response's data. The data comes
+// from applicants, so it's kept on one line in the subject and escaped in HTML bodies.
+func NewMessageFromTemplate(template *models.EmailTemplate, to string, data map[string]interface{}) Message {
+	body := utils.RenderEmailTemplate(template.Body, data)
+	if template.IsHTML {
+		body = utils.RenderEmailHTML(template.Body, data)
+	}
+
+	return Message{
+		From:    template.From,
+		To:      []string{to},
+		ReplyTo: template.ReplyTo,
+		Subject: utils.RenderEmailSubject(template.Subject, data),
+		Body:    body,
+		IsHTML:  template.IsHTML,
+	}
+}
+
+// Send delivers the message through the event's SMTP server
+func Send(smtpConfig *models.EmailSecret, msg Message) error {
+	toHeader := "To: " + strings.Join(msg.To, ", ") + "\r\n"
+
+	// TODO: BCC & CC
+	//to = append(to, emailTemplate.CC...)
+	//to = append(to, emailTemplate.BCC...)
+
+	// Prepare the email headers and body
+	subject := "Subject: " + msg.Subject + "\r\n"
+	from := "From: " + msg.From + "\r\n"
+
+	var replyTo string
+	if msg.ReplyTo != "" {
+		replyTo = "Reply-To: " + msg.ReplyTo + "\r\n"
+	} else {
+		replyTo = "Reply-To: " + msg.From + "\r\n"
+	}
+
+	dateHeader := "Date: " + time.Now().Format("Mon, 02 Jan 2006 15:04:05 -0700") + "\r\n"
+	messageID := fmt.Sprintf("Message-ID: <%s@%s>\r\n", uuid.NewString(), smtpConfig.SMTPServer)
+
+	var body string = msg.Body
+	mime := "MIME-Version: 1.0\r\n"
+	if msg.IsHTML {
+		mime += "Content-Type: text/html; charset=\"UTF-8\"\r\n"
+		body = "<html><body>" + msg.Body + "</body></html>"
+	} else {
+		mime += "Content-Type: text/plain; charset=\"UTF-8\"\r\n"
+	}
+
+	extraHeaders := ""
+	for name, value := range msg.Headers {
+		extraHeaders += name + ": " + value + "\r\n"
+	}
+
+	message := []byte(subject + from + toHeader + replyTo + dateHeader + messageID + extraHeaders + mime + "\r\n" + body)
+
+	// SMTP server configuration
+	smtpHost := smtpConfig.SMTPServer
+	smtpPort := fmt.Sprintf("%d", smtpConfig.Port)
+	address := smtpHost + ":" + smtpPort
+
+	// Authentication
+	auth := smtp.PlainAuth("", smtpConfig.Username, smtpConfig.Password, smtpHost)
+
+	// Sending email
+	return smtp.SendMail(address, auth, msg.From, msg.To, message)
+}
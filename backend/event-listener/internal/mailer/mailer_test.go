@@ -0,0 +1,20 @@
+package mailer
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessageFromTemplate(t *testing.T) {
+	data := map[string]interface{}{"name": "Ada\r\nBcc: everyone@example.com <script>"}
+
+	html := NewMessageFromTemplate(&models.EmailTemplate{Subject: "Hi ${name}", Body: "<p>${name}</p>", IsHTML: true}, "ada@example.com", data)
+	assert.Equal(t, "Hi Ada Bcc: everyone@example.com <script>", html.Subject)
+	assert.Equal(t, "<p>Ada\r\nBcc: everyone@example.com &lt;script&gt;</p>", html.Body)
+
+	// Plain text bodies can't carry markup so values are left as they are
+	text := NewMessageFromTemplate(&models.EmailTemplate{Subject: "Hi", Body: "${name}"}, "ada@example.com", data)
+	assert.Equal(t, "Ada\r\nBcc: everyone@example.com <script>", text.Body)
+}
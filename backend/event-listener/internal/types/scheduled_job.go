@@ -0,0 +1,13 @@
+package types
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduledJob is work the event listener runs periodically, outside of any pipeline
+type ScheduledJob interface {
+	Name() string
+	Interval() time.Duration
+	Run(ctx context.Context) error
+}
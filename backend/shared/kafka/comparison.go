@@ -13,6 +13,23 @@ func FieldChangeCheck(
 		return false
 	}
 
+	// Tags match when the response has the tag
+	if tags, ok := newValue.([]string); ok {
+		hasTag := false
+		for _, tag := range tags {
+			hasTag = hasTag || tag == condition.Value
+		}
+
+		switch condition.Comparison {
+		case models.ComparisonEq:
+			return hasTag
+		case models.ComparisonNeq:
+			return !hasTag
+		default:
+			return false
+		}
+	}
+
 	switch condition.Comparison {
 	case models.ComparisonEq:
 		return newValue == condition.Value
@@ -23,3 +40,31 @@ func FieldChangeCheck(
 	}
 
 }
+
+// ReviewScoreCheck reports whether a response's review summary meets a ReviewScored event's condition
+func ReviewScoreCheck(
+	reviewScored *models.ReviewScored,
+	summary *models.ResponseReviewSummary,
+) bool {
+	if summary == nil || summary.Count < reviewScored.MinReviews {
+		return false
+	}
+
+	condition := reviewScored.Condition
+	switch condition.Comparison {
+	case models.ComparisonEq:
+		return summary.Score == condition.Value
+	case models.ComparisonNeq:
+		return summary.Score != condition.Value
+	case models.ComparisonGt:
+		return summary.Score > condition.Value
+	case models.ComparisonGte:
+		return summary.Score >= condition.Value
+	case models.ComparisonLt:
+		return summary.Score < condition.Value
+	case models.ComparisonLte:
+		return summary.Score <= condition.Value
+	default:
+		return false
+	}
+}
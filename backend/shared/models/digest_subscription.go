@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestHourly DigestFrequency = "hourly"
+	DigestDaily  DigestFrequency = "daily"
+)
+
+// Interval returns how often a digest is sent, zero when digests are off
+func (f DigestFrequency) Interval() time.Duration {
+	switch f {
+	case DigestHourly:
+		return time.Hour
+	case DigestDaily:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// DigestSubscription is an organizer's digest settings for one event
+type DigestSubscription struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID    primitive.ObjectID `bson:"eventID" json:"eventID"`
+	UserID     primitive.ObjectID `bson:"userID" json:"userID"`
+	Frequency  DigestFrequency    `bson:"frequency" json:"frequency" validate:"required,oneof=off hourly daily"`
+	LastSentAt time.Time          `bson:"lastSentAt" json:"lastSentAt"`
+	NextSendAt time.Time          `bson:"nextSendAt" json:"nextSendAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
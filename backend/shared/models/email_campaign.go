@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailCampaignStatus string
+
+const (
+	EmailCampaignScheduled EmailCampaignStatus = "Scheduled"
+	EmailCampaignSending   EmailCampaignStatus = "Sending"
+	EmailCampaignCompleted EmailCampaignStatus = "Completed"
+	EmailCampaignCancelled EmailCampaignStatus = "Cancelled"
+	EmailCampaignFailed    EmailCampaignStatus = "Failed"
+)
+
+// EmailCampaign is a one-off email sent to every response matching a filter, outside of any pipeline
+type EmailCampaign struct {
+	ID              primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID         primitive.ObjectID  `bson:"eventID" json:"eventID" validate:"required"`
+	Name            string              `bson:"name" json:"name" validate:"required"`
+	EmailTemplateID primitive.ObjectID  `bson:"emailTemplateID" json:"emailTemplateID" validate:"required"`
+	EmailFieldID    string              `bson:"emailFieldID" json:"emailFieldID" validate:"required"`
+	Filter          ResponseFilter      `bson:"filter" json:"filter" validate:"required"`
+	RatePerMinute   int                 `bson:"ratePerMinute" json:"ratePerMinute" validate:"min=0,max=600"`
+	Status          EmailCampaignStatus `bson:"status" json:"status"`
+	ScheduledAt     time.Time           `bson:"scheduledAt" json:"scheduledAt"`
+	StartedAt       time.Time           `bson:"startedAt,omitempty" json:"startedAt,omitempty"`
+	CompletedAt     time.Time           `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	CreatedBy       primitive.ObjectID  `bson:"createdBy" json:"createdBy"`
+	CreatedAt       time.Time           `bson:"createdAt" json:"createdAt"`
+
+	// Progress, written by the event listener while the campaign is sending
+	TotalRecipients    int                    `bson:"totalRecipients" json:"totalRecipients"`
+	TemplateVersion    int                    `bson:"templateVersion" json:"templateVersion"`
+	TemplateComponents []EmailComponentRef    `bson:"templateComponents,omitempty" json:"templateComponents,omitempty"`
+	SentCount          int                    `bson:"sentCount" json:"sentCount"`
+	FailedCount        int                    `bson:"failedCount" json:"failedCount"`
+	Failures           []EmailCampaignFailure `bson:"failures" json:"failures"`
+	ErrorMsg           string                 `bson:"errorMsg,omitempty" json:"errorMsg,omitempty"`
+
+	// Lease held by the sender, a campaign whose heartbeat goes stale is claimed again and resumed
+	ClaimID     primitive.ObjectID `bson:"claimID,omitempty" json:"-"`
+	HeartbeatAt time.Time          `bson:"heartbeatAt,omitempty" json:"heartbeatAt,omitempty"`
+	// Lowercased addresses already sent to, so a resumed campaign skips them
+	Attempted []string `bson:"attempted,omitempty" json:"-"`
+}
+
+// EmailCampaignFailure records a single recipient the campaign could not deliver to
+type EmailCampaignFailure struct {
+	ResponseID primitive.ObjectID `bson:"responseID" json:"responseID"`
+	Email      string             `bson:"email" json:"email"`
+	ErrorMsg   string             `bson:"errorMsg" json:"errorMsg"`
+	FailedAt   time.Time          `bson:"failedAt" json:"failedAt"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailComponentKind string
+
+const (
+	// A layout wraps a template's body, it must contain a {{content}} slot
+	EmailComponentLayout EmailComponentKind = "layout"
+
+	// A partial is included in templates and layouts with {{> name}}
+	EmailComponentPartial EmailComponentKind = "partial"
+)
+
+// EmailComponent is a layout or partial shared by the email templates of an event
+type EmailComponent struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID   primitive.ObjectID `bson:"eventID" json:"eventID" validate:"required"`
+	Kind      EmailComponentKind `bson:"kind" json:"kind" validate:"required,oneof=layout partial"`
+	Name      string             `bson:"name" json:"name" validate:"required,max=64"`
+	Body      string             `bson:"body" json:"body"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+
+	// Version is incremented on every update, each one is kept in email_component_versions
+	Version   int                `bson:"version" json:"version"`
+	UpdatedBy primitive.ObjectID `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"`
+}
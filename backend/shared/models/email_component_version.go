@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailComponentVersion is an immutable copy of an email layout or partial as it was saved
+type EmailComponentVersion struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ComponentID primitive.ObjectID `bson:"componentID" json:"componentID"`
+	EventID     primitive.ObjectID `bson:"eventID" json:"eventID"`
+	Version     int                `bson:"version" json:"version"`
+	Component   EmailComponent     `bson:"component" json:"component"`
+	CreatedBy   primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// EmailComponentRef is the version of a layout or partial an email was rendered with
+type EmailComponentRef struct {
+	ComponentID primitive.ObjectID `bson:"componentID" json:"componentID"`
+	Version     int                `bson:"version" json:"version"`
+}
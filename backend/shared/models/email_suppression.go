@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailSuppressionReason string
+
+const (
+	EmailSuppressionUnsubscribed EmailSuppressionReason = "unsubscribed"
+	EmailSuppressionManual       EmailSuppressionReason = "manual"
+	EmailSuppressionBounced      EmailSuppressionReason = "bounced"
+)
+
+// EmailSuppression stops emails from being sent to an address.
+// An entry with a zero EventID is global and applies to every event.
+type EmailSuppression struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+	EventID   primitive.ObjectID     `bson:"eventID" json:"eventID"`
+	Email     string                 `bson:"email" json:"email" validate:"required,email"`
+	Reason    EmailSuppressionReason `bson:"reason" json:"reason" validate:"required,oneof=unsubscribed manual bounced"`
+	Note      string                 `bson:"note,omitempty" json:"note,omitempty" validate:"max=500"`
+	CreatedBy primitive.ObjectID     `bson:"createdBy,omitempty" json:"createdBy,omitempty"` // zero when the recipient unsubscribed themselves
+	CreatedAt time.Time              `bson:"createdAt" json:"createdAt"`
+}
+
+// IsGlobal returns true if the suppression applies to every event
+func (e *EmailSuppression) IsGlobal() bool {
+	return e.EventID.IsZero()
+}
@@ -6,6 +6,18 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+type EmailTemplateCategory string
+
+const (
+	// Transactional emails are about the recipient's own application, eg: submission receipts and decisions.
+	// They are only blocked by a global suppression.
+	EmailTemplateTransactional EmailTemplateCategory = "transactional"
+
+	// Marketing emails are announcements and reminders, they carry an unsubscribe link
+	// and are blocked by both the event's and the global suppression list.
+	EmailTemplateMarketing EmailTemplateCategory = "marketing"
+)
+
 type EmailTemplate struct {
 	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	EventID        primitive.ObjectID `bson:"eventID" json:"eventID" validate:"required"`
@@ -20,4 +32,18 @@ type EmailTemplate struct {
 	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
 	Description    string             `bson:"description" json:"description"`
 	IsHTML         bool               `bson:"isHTML" json:"isHTML"`
+
+	Category EmailTemplateCategory `bson:"category" json:"category" validate:"omitempty,oneof=transactional marketing"`
+
+	// LayoutID is the event layout the body is wrapped in, zero for no layout
+	LayoutID primitive.ObjectID `bson:"layoutID" json:"layoutID"`
+
+	// Version is incremented on every update, each one is kept in email_template_versions
+	Version   int                `bson:"version" json:"version"`
+	UpdatedBy primitive.ObjectID `bson:"updatedBy,omitempty" json:"updatedBy,omitempty"`
+}
+
+// IsMarketing returns true if the template should be treated as a marketing email, templates default to transactional
+func (e *EmailTemplate) IsMarketing() bool {
+	return e.Category == EmailTemplateMarketing
 }
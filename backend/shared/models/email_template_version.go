@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EmailTemplateVersion is an immutable copy of an email template as it was saved
+type EmailTemplateVersion struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	TemplateID primitive.ObjectID `bson:"templateID" json:"templateID"`
+	EventID    primitive.ObjectID `bson:"eventID" json:"eventID"`
+	Version    int                `bson:"version" json:"version"`
+	Template   EmailTemplate      `bson:"template" json:"template"`
+	CreatedBy  primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+}
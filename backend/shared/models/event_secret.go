@@ -21,14 +21,19 @@ type EmailSecret struct {
 	Username   string             `bson:"username" json:"username,omitempty"`
 	Password   string             `bson:"password" json:"password,omitempty"`
 	UpdatedAt  primitive.DateTime `bson:"updatedAt" json:"updatedAt,omitempty"`
+
+	// AllowedFrom lists the From addresses the SMTP server accepts, either full addresses or "@domain.com".
+	// This isn't a secret so it's kept when the secret is stripped.
+	AllowedFrom []string `bson:"allowedFrom,omitempty" json:"allowedFrom,omitempty"`
 }
 
 func (e *EmailSecret) StripSecret() interface{} {
 	return &EmailSecret{
-		SMTPServer: "",
-		Port:       0,
-		Username:   "",
-		Password:   "",
-		UpdatedAt:  e.UpdatedAt,
+		SMTPServer:  "",
+		Port:        0,
+		Username:    "",
+		Password:    "",
+		UpdatedAt:   e.UpdatedAt,
+		AllowedFrom: e.AllowedFrom,
 	}
 }
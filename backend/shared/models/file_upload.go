@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FileUpload is a file uploaded for a form's file field. Response data references it by ID,
+// uploads that never get attached to a response or draft are cleaned up.
+type FileUpload struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	FormID      primitive.ObjectID `bson:"formID" json:"formID"`
+	EventID     primitive.ObjectID `bson:"eventID" json:"eventID"`
+	FieldKey    string             `bson:"fieldKey" json:"fieldKey"`
+	UserID      primitive.ObjectID `bson:"userID" json:"userID"`
+	ResponseID  primitive.ObjectID `bson:"responseID" json:"responseID"`
+	DraftID     primitive.ObjectID `bson:"draftID,omitempty" json:"draftID,omitempty"`
+	FileName    string             `bson:"fileName" json:"fileName"`
+	ContentType string             `bson:"contentType" json:"contentType"`
+	Size        int64              `bson:"size" json:"size"`
+	StorageKey  string             `bson:"storageKey" json:"-"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
@@ -6,15 +6,34 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Kinds of event selector sources
+const (
+	SelectorSourceCSV = "csv"
+	SelectorSourceURL = "url"
+)
+
 /*
-This section is for modeling the default values for Selectors when we fetch data from external sources to cache
-Like MLH's School List
+This section is for modeling the default values for Selectors, fields use them with UseDefaultValuesFrom.
+Built-in sources fetched from external sources like MLH's School List are cached here with a zero EventID,
+organizers can also add sources to their event from an uploaded CSV list or a URL that's refreshed in the background.
 */
 type SelectorSource struct {
-	ID          primitive.ObjectID `bson:"_id"`
-	SourceName  string             `bson:"sourceName" json:"sourceName"`
-	LastUpdated time.Time          `bson:"lastUpdated" json:"lastUpdated"`
-	Options     []string           `bson:"options" json:"options"`
+	ID                primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	EventID           primitive.ObjectID `bson:"eventID,omitempty" json:"eventID,omitempty"`
+	SourceName        string             `bson:"sourceName" json:"sourceName" validate:"required,max=64"`
+	Kind              string             `bson:"kind,omitempty" json:"kind,omitempty" validate:"omitempty,oneof=csv url"`
+	URL               string             `bson:"url,omitempty" json:"url,omitempty" validate:"omitempty,url,startswith=https://"`
+	Format            string             `bson:"format,omitempty" json:"format,omitempty" validate:"omitempty,oneof=csv lines json"`
+	RefreshEveryHours int                `bson:"refreshEveryHours,omitempty" json:"refreshEveryHours,omitempty" validate:"min=0,max=720"`
+	LastUpdated       time.Time          `bson:"lastUpdated" json:"lastUpdated"`
+	LastError         string             `bson:"lastError,omitempty" json:"lastError,omitempty"` // why the last refresh failed, cleared when one succeeds
+	Options           []string           `bson:"options" json:"options,omitempty"`
+	CreatedBy         primitive.ObjectID `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+}
+
+// IsBuiltin returns true if the source is a cached built-in rather than one of an event's sources
+func (s *SelectorSource) IsBuiltin() bool {
+	return s.EventID.IsZero()
 }
 
 // Address represents a physical address
@@ -39,6 +58,10 @@ type FieldValidation struct {
 	Min     int                    `json:"min,omitempty" bson:"min"`
 	Max     int                    `json:"max,omitempty" bson:"max"`
 	IsEmail EmailValidationOptions `json:"isEmail,omitempty" bson:"isEmail"`
+
+	// File fields, MaxFileSize is in bytes and AllowedMimeTypes can use wildcards like image/*
+	MaxFileSize      int64    `json:"maxFileSize,omitempty" bson:"maxFileSize,omitempty"`
+	AllowedMimeTypes []string `json:"allowedMimeTypes,omitempty" bson:"allowedMimeTypes,omitempty"`
 }
 
 // AdditionalOptions for extra field-specific settings
@@ -52,6 +75,25 @@ type AdditionalOptions struct {
 // FormFieldType for defining the type of a form field
 type FormFieldType string
 
+const (
+	FieldTypeNumber            FormFieldType = "number"
+	FieldTypeText              FormFieldType = "text"
+	FieldTypeDate              FormFieldType = "date"
+	FieldTypeTimestamp         FormFieldType = "timestamp"
+	FieldTypeTelephone         FormFieldType = "telephone"
+	FieldTypeTextArea          FormFieldType = "textarea"
+	FieldTypeSelect            FormFieldType = "select"
+	FieldTypeMultiSelect       FormFieldType = "multiselect"
+	FieldTypeCustomSelect      FormFieldType = "customselect"
+	FieldTypeCustomMultiSelect FormFieldType = "custommultiselect"
+	FieldTypeCheckbox          FormFieldType = "checkbox"
+	FieldTypeRadio             FormFieldType = "radio"
+	FieldTypeAddress           FormFieldType = "address"
+	FieldTypeColorPicker       FormFieldType = "colorpicker"
+	FieldTypeRichText          FormFieldType = "richtext"
+	FieldTypeFile              FormFieldType = "file"
+)
+
 // FieldValue represents the value a field can hold
 type FieldValue string // TODO: maybe should union type
 
@@ -69,6 +111,52 @@ type FormField struct {
 	Disabled             bool              `json:"disabled,omitempty" bson:"disabled"`
 	AdditionalOptions    AdditionalOptions `json:"additionalOptions,omitempty" bson:"additionalOptions,omitempty"`
 	IsInternal           bool              `json:"isInternal" bson:"isInternal"`
+
+	// SectionKey places the field on one of the form's sections, empty for forms without sections
+	SectionKey string `json:"sectionKey,omitempty" bson:"sectionKey,omitempty"`
+
+	// VisibleIf hides the field unless every condition matches, hidden fields are never required or stored
+	VisibleIf []FormFieldCondition `json:"visibleIf,omitempty" bson:"visibleIf,omitempty" validate:"dive"`
+
+	// Prefill fills the field in from the applicant's profile or the form link's query parameters
+	Prefill *FieldPrefill `json:"prefill,omitempty" bson:"prefill,omitempty"`
+}
+
+// ProfileAttribute is a user profile value a field can be prefilled from
+type ProfileAttribute string
+
+const (
+	ProfileFirstName   ProfileAttribute = "firstName"
+	ProfileLastName    ProfileAttribute = "lastName"
+	ProfileFullName    ProfileAttribute = "fullName"
+	ProfileEmail       ProfileAttribute = "email"
+	ProfileSchoolEmail ProfileAttribute = "schoolEmail"
+	ProfileBirthday    ProfileAttribute = "birthday"
+)
+
+// FieldPrefill sets where a field's initial value comes from, either ProfileAttribute or QueryParam.
+// Locked profile fields always take the profile's value, WriteBack saves the submitted value to the profile instead.
+type FieldPrefill struct {
+	ProfileAttribute ProfileAttribute `json:"profileAttribute,omitempty" bson:"profileAttribute,omitempty" validate:"omitempty,oneof=firstName lastName fullName email schoolEmail birthday"`
+	QueryParam       string           `json:"queryParam,omitempty" bson:"queryParam,omitempty" validate:"omitempty,max=100"`
+	Locked           bool             `json:"locked,omitempty" bson:"locked,omitempty"`
+	WriteBack        bool             `json:"writeBack,omitempty" bson:"writeBack,omitempty"`
+}
+
+// FormFieldCondition matches another field's value in the same response.
+// For fields with multiple values eq checks the value is one of them.
+type FormFieldCondition struct {
+	FieldKey   string     `json:"fieldKey" bson:"fieldKey" validate:"required"`
+	Comparison Comparison `json:"comparison" bson:"comparison" validate:"required,comparison"`
+	Value      string     `json:"value" bson:"value"`
+}
+
+// FormSection is a page of a multi-step form, its fields are the ones with a matching SectionKey
+type FormSection struct {
+	Key         string               `json:"key" bson:"key" validate:"required"`
+	Title       string               `json:"title" bson:"title"`
+	Description string               `json:"description,omitempty" bson:"description,omitempty" validate:"max=1000"`
+	VisibleIf   []FormFieldCondition `json:"visibleIf,omitempty" bson:"visibleIf,omitempty" validate:"dive"`
 }
 
 // FormAllowedSubmitter represents a user who is allowed to submit a form with additional options
@@ -80,8 +168,11 @@ type FormAllowedSubmitter struct {
 // FormStructure represents the overall structure of a form
 type FormStructure struct {
 	Attrs                    []FormField            `json:"attrs" bson:"attrs" validate:"dive"`
+	Sections                 []FormSection          `json:"sections,omitempty" bson:"sections,omitempty" validate:"dive"`
 	ID                       primitive.ObjectID     `json:"id,omitempty" bson:"_id,omitempty"`
 	AllowMultipleSubmissions bool                   `json:"allowMultipleSubmissions,omitempty" bson:"allowMultipleSubmissions"`
+	AllowResponseEdits       bool                   `json:"allowResponseEdits,omitempty" bson:"allowResponseEdits"`
+	EnableWaitlist           bool                   `json:"enableWaitlist,omitempty" bson:"enableWaitlist"`
 	CloseSubmissionsAt       time.Time              `json:"closeSubmissionsAt,omitempty" bson:"closeSubmissionsAt"`
 	OpenSubmissionsAt        time.Time              `json:"openSubmissionsAt,omitempty" bson:"openSubmissionsAt"`
 	Name                     string                 `json:"name,omitempty" bson:"name"`
@@ -95,10 +186,32 @@ type FormStructure struct {
 	SubmissionMessage        string                 `json:"submissionMessage,omitempty" bson:"submissionMessage"`
 	IsRestricted             bool                   `json:"isRestricted,omitempty" bson:"isRestricted"`
 	AllowedSubmitters        []FormAllowedSubmitter `json:"allowedSubmitters,omitempty" bson:"allowedSubmitters" validate:"dive"`
+	AllowedDomains           []FormAllowedDomain    `json:"allowedDomains,omitempty" bson:"allowedDomains" validate:"dive"`
+
+	// AllowAnonymous accepts responses without an account, they're only saved once the email they give is confirmed
+	// with a link sent using AnonymousConfirmationTemplateID
+	AllowAnonymous                  bool               `json:"allowAnonymous,omitempty" bson:"allowAnonymous"`
+	AnonymousConfirmationTemplateID primitive.ObjectID `json:"anonymousConfirmationTemplateID,omitempty" bson:"anonymousConfirmationTemplateID,omitempty" validate:"required_if=AllowAnonymous true"`
+
+	// The form's current version, set once the form is published
+	Version   int                `json:"version,omitempty" bson:"version"`
+	VersionID primitive.ObjectID `json:"versionID,omitempty" bson:"versionID"`
 }
 
 // StripSecrets removes any sensitive information from the form
 func (f *FormStructure) StripSecrets() {
 	// Note: it would be nice to just abstract this out to filter when secret:"true" and for all models
 	f.AllowedSubmitters = nil
+	f.AllowedDomains = nil
+}
+
+// StripInternalFields removes the organizer only fields so the form can be shown to applicants
+func (f *FormStructure) StripInternalFields() {
+	attrs := make([]FormField, 0, len(f.Attrs))
+	for _, attr := range f.Attrs {
+		if !attr.IsInternal {
+			attrs = append(attrs, attr)
+		}
+	}
+	f.Attrs = attrs
 }
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FormAllowedDomain lets any address at the domain submit a restricted form
+type FormAllowedDomain struct {
+	Domain          string    `json:"domain" bson:"domain" validate:"required,fqdn"`
+	AllowSubdomains bool      `json:"allowSubdomains,omitempty" bson:"allowSubdomains"`
+	ExpiresAt       time.Time `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+}
+
+// FormInvite is an invite link to a restricted form, it can be limited to a number of uses, an expiry and a single email.
+// The token in the link is signed rather than stored, revoking the invite is what stops it working.
+type FormInvite struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	FormID    primitive.ObjectID `json:"formID" bson:"formID"`
+	EventID   primitive.ObjectID `json:"eventID" bson:"eventID"`
+	Label     string             `json:"label,omitempty" bson:"label,omitempty" validate:"max=200"`
+	Email     string             `json:"email,omitempty" bson:"email,omitempty" validate:"omitempty,email"`
+	MaxUses   int                `json:"maxUses,omitempty" bson:"maxUses" validate:"min=0"`
+	Uses      int                `json:"uses" bson:"uses"`
+	ExpiresAt time.Time          `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+	RevokedAt time.Time          `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+	CreatedBy primitive.ObjectID `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+
+	// Token is only filled in for organizers, it's derived from the ID
+	Token string `json:"token,omitempty" bson:"-"`
+}
+
+// FormAccessRule is the kind of rule that let a user submit a form
+type FormAccessRule string
+
+const (
+	FormAccessOpen   FormAccessRule = "open"
+	FormAccessEmail  FormAccessRule = "email"
+	FormAccessDomain FormAccessRule = "domain"
+	FormAccessInvite FormAccessRule = "invite"
+
+	// FormAccessAnonymous responses were submitted without an account, the grant's email is the one they confirmed
+	FormAccessAnonymous FormAccessRule = "anonymous"
+)
+
+// FormAccessGrant records which rule let a user submit a form
+type FormAccessGrant struct {
+	Rule     FormAccessRule     `json:"rule" bson:"rule"`
+	Email    string             `json:"email,omitempty" bson:"email,omitempty"`
+	Domain   string             `json:"domain,omitempty" bson:"domain,omitempty"`
+	InviteID primitive.ObjectID `json:"inviteID,omitempty" bson:"inviteID,omitempty"`
+}
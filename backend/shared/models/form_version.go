@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FormVersion is an immutable copy of a published form's fields and sections.
+// A new version is saved whenever a published form's schema changes, responses record the version they were submitted against.
+type FormVersion struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	FormID    primitive.ObjectID `bson:"formID" json:"formID"`
+	EventID   primitive.ObjectID `bson:"eventID" json:"eventID"`
+	Version   int                `bson:"version" json:"version"`
+	Attrs     []FormField        `bson:"attrs" json:"attrs"`
+	Sections  []FormSection      `bson:"sections,omitempty" json:"sections,omitempty"`
+	CreatedBy primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
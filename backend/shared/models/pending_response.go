@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PendingResponseTTL is how long an anonymous response waits for its email to be confirmed
+const PendingResponseTTL = 48 * time.Hour
+
+const (
+	// PendingResponseResendCooldown is how long after a confirmation email submitting again can send another
+	PendingResponseResendCooldown = 10 * time.Minute
+
+	// MaxPendingResponseConfirmations limits how many confirmation emails a pending response sends
+	MaxPendingResponseConfirmations = 5
+)
+
+// PendingResponse is an anonymous response waiting for its email to be confirmed, at most one per email and form.
+// It isn't counted or sent to pipelines until it's confirmed and saved as a FormResponse.
+type PendingResponse struct {
+	ID                 primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+	FormID             primitive.ObjectID     `bson:"formID" json:"formID"`
+	Email              string                 `bson:"email" json:"email"`
+	Data               map[string]interface{} `bson:"data" json:"data"`
+	CreatedAt          time.Time              `bson:"createdAt" json:"createdAt"`
+	ExpiresAt          time.Time              `bson:"expiresAt" json:"expiresAt"`
+	ConfirmationSentAt time.Time              `bson:"confirmationSentAt,omitempty" json:"confirmationSentAt,omitempty"`
+	ConfirmedAt        time.Time              `bson:"confirmedAt,omitempty" json:"confirmedAt,omitempty"`
+
+	// Submitting again asks for another email, it's sent once the cooldown since the last one has passed
+	ConfirmationsSent int  `bson:"confirmationsSent,omitempty" json:"confirmationsSent,omitempty"`
+	ResendRequested   bool `bson:"resendRequested,omitempty" json:"resendRequested,omitempty"`
+
+	// RequestIP is the address the response was first submitted from, for limiting pending responses per address
+	RequestIP string `bson:"requestIP,omitempty" json:"requestIP,omitempty"`
+}
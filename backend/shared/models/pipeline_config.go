@@ -12,6 +12,10 @@ type Comparison string
 const (
 	ComparisonEq  Comparison = "eq"
 	ComparisonNeq Comparison = "neq"
+	ComparisonGt  Comparison = "gt"
+	ComparisonGte Comparison = "gte"
+	ComparisonLt  Comparison = "lt"
+	ComparisonLte Comparison = "lte"
 )
 
 //
@@ -26,6 +30,9 @@ type PipelineEvent struct {
 	// Embed each specific event type
 	FormSubmission *FormSubmission `bson:"formSubmission,omitempty" json:"formSubmission,omitempty"`
 	FieldChange    *FieldChange    `bson:"fieldChange,omitempty" json:"fieldChange,omitempty"`
+
+	WaitlistPromotion *WaitlistPromotion `bson:"waitlistPromotion,omitempty" json:"waitlistPromotion,omitempty"`
+	ReviewScored      *ReviewScored      `bson:"reviewScored,omitempty" json:"reviewScored,omitempty"`
 }
 
 // FormSubmission represents a form submission event
@@ -33,6 +40,24 @@ type FormSubmission struct {
 	OnFormID primitive.ObjectID `bson:"onFormID" json:"onFormID" validate:"required"`
 }
 
+// WaitlistPromotion represents a waitlisted response being given a place on a full form
+type WaitlistPromotion struct {
+	OnFormID primitive.ObjectID `bson:"onFormID" json:"onFormID" validate:"required"`
+}
+
+// ReviewScored represents a response's review score starting to match the condition once it has at least MinReviews reviews
+type ReviewScored struct {
+	OnFormID   primitive.ObjectID   `bson:"onFormID" json:"onFormID" validate:"required"`
+	MinReviews int                  `bson:"minReviews" json:"minReviews" validate:"min=1"`
+	Condition  ReviewScoreCondition `bson:"condition" json:"condition" validate:"required"`
+}
+
+// ReviewScoreCondition compares a response's review score, a percentage from 0 to 100
+type ReviewScoreCondition struct {
+	Comparison Comparison `bson:"comparison" json:"comparison" validate:"required,oneof=eq neq gt gte lt lte"`
+	Value      float64    `bson:"value" json:"value" validate:"min=0,max=100"`
+}
+
 // FieldChange represents a field change event
 type FieldChange struct {
 	OnFormID  primitive.ObjectID   `bson:"onFormID" json:"onFormID" validate:"required"`
@@ -72,6 +97,12 @@ type SendEmail struct {
 
 type FormAllowedAccessOptions struct {
 	ExpiresInHours int `bson:"expiresInHours" json:"expiresInHours" validate:"required"`
+
+	// IssueInvite creates an invite for the email instead of adding it to the whitelist,
+	// the invite link is sent with InviteEmailTemplateID as the ${invite_link} placeholder
+	IssueInvite           bool               `bson:"issueInvite,omitempty" json:"issueInvite,omitempty"`
+	InviteMaxUses         int                `bson:"inviteMaxUses,omitempty" json:"inviteMaxUses,omitempty" validate:"min=0"`
+	InviteEmailTemplateID primitive.ObjectID `bson:"inviteEmailTemplateID,omitempty" json:"inviteEmailTemplateID,omitempty" validate:"required_if=IssueInvite true"`
 }
 
 // AllowFormAccess represents the action to allow access to a form
@@ -21,6 +21,12 @@ type PipelineActionStatus struct {
 	StartedAt   time.Time          `bson:"startedAt" json:"startedAt"`
 	CompletedAt time.Time          `bson:"completedAt" json:"completedAt"`
 	ErrorMsg    string             `bson:"errorMsg" json:"errorMsg"`
+
+	// EmailTemplateVersion is the template version a SendEmail action actually sent
+	EmailTemplateVersion int `bson:"emailTemplateVersion,omitempty" json:"emailTemplateVersion,omitempty"`
+
+	// EmailComponents are the versions of the layout and partials the template was rendered with
+	EmailComponents []EmailComponentRef `bson:"emailComponents,omitempty" json:"emailComponents,omitempty"`
 }
 
 type PipelineRun struct {
@@ -1,11 +1,49 @@
 package models
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ResponseStatus is where a response stands against the form's capacity
+type ResponseStatus string
+
+const (
+	// ResponseSubmitted responses take up one of the form's MaxSubmissions, responses without a status are submitted
+	ResponseSubmitted ResponseStatus = "submitted"
+
+	// ResponseWaitlisted responses were accepted after the form was full and wait to be promoted in WaitlistPosition order
+	ResponseWaitlisted ResponseStatus = "waitlisted"
+
+	// ResponseReleased responses gave their place back, or left the waitlist, when they were rejected or declined
+	ResponseReleased ResponseStatus = "released"
+)
+
+// Decision is where an application stands with the organizers, kept apart from ResponseStatus which is about the form's capacity
+type Decision string
+
+const (
+	// DecisionPending responses haven't been decided on yet, responses without a decision are pending
+	DecisionPending    Decision = "pending"
+	DecisionAccepted   Decision = "accepted"
+	DecisionRejected   Decision = "rejected"
+	DecisionWaitlisted Decision = "waitlisted"
+
+	// Accepted applicants confirm or decline their place themselves
+	DecisionConfirmed Decision = "confirmed"
+	DecisionDeclined  Decision = "declined"
+)
+
+// DecisionFieldID is the field ID FieldChange pipelines use to watch the response's decision,
+// field keys are UUIDs so it can't clash with one
+const DecisionFieldID = "decision"
+
+// TagsFieldID is the field ID FieldChange pipelines and response filters use for the response's tags,
+// eq matches responses with the tag and neq responses without it
+const TagsFieldID = "tags"
+
 // FormResponse represents a form response
 type FormResponse struct {
 	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
@@ -14,4 +52,89 @@ type FormResponse struct {
 	UserID    primitive.ObjectID     `bson:"userID" json:"userID"`
 	CreatedAt time.Time              `bson:"createdAt" json:"createdAt" validate:"required"`
 	UpdatedAt time.Time              `bson:"updatedAt" json:"updatedAt"`
+
+	// AnonymousEmail is the confirmed email of a response submitted without an account, UserID is set
+	// once an account registers with it
+	AnonymousEmail string `bson:"anonymousEmail,omitempty" json:"anonymousEmail,omitempty"`
+
+	// FormVersionID is the form version the data was last validated against, zero for responses from before versioning
+	FormVersionID primitive.ObjectID `bson:"formVersionID,omitempty" json:"formVersionID,omitempty"`
+
+	// Waitlisted responses get a position and are promoted to submitted when a place frees up
+	Status           ResponseStatus `bson:"status,omitempty" json:"status,omitempty"`
+	WaitlistPosition int            `bson:"waitlistPosition,omitempty" json:"waitlistPosition,omitempty"`
+	PromotedAt       time.Time      `bson:"promotedAt,omitempty" json:"promotedAt,omitempty"`
+
+	// AccessGrant is the access rule that let the user submit a restricted form
+	AccessGrant *FormAccessGrant `bson:"accessGrant,omitempty" json:"accessGrant,omitempty"`
+
+	// Decision is the organizers' decision on the application, empty until one is made
+	Decision  Decision           `bson:"decision,omitempty" json:"decision,omitempty"`
+	DecidedAt time.Time          `bson:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	DecidedBy primitive.ObjectID `bson:"decidedBy,omitempty" json:"decidedBy,omitempty"`
+
+	// Tags are organizer labels like "needs-travel", they're kept out of the data and hidden from the applicant
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
+
+	// Review summarises the response's submitted reviews, it's nil until one is submitted
+	Review *ResponseReviewSummary `bson:"review,omitempty" json:"review,omitempty"`
+
+	// Withdrawn responses are soft deleted so organizers keep a record of them
+	IsDeleted bool      `bson:"isDeleted" json:"isDeleted,omitempty"`
+	DeletedAt time.Time `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+}
+
+// SubmitterKey identifies who submitted the response for the one submission per user limit,
+// anonymous responses keep their email as the key even once they're linked to an account
+func (r *FormResponse) SubmitterKey() string {
+	if r.AnonymousEmail != "" {
+		return "email:" + strings.ToLower(r.AnonymousEmail)
+	}
+	return r.UserID.Hex()
+}
+
+// IsWaitlisted reports whether the response is waiting for a place on the form
+func (r *FormResponse) IsWaitlisted() bool {
+	return r.Status == ResponseWaitlisted
+}
+
+// HoldsPlace reports whether the response takes up one of the form's MaxSubmissions
+func (r *FormResponse) HoldsPlace() bool {
+	return r.Status != ResponseWaitlisted && r.Status != ResponseReleased
+}
+
+// ResponseFilter selects a segment of a form's responses
+type ResponseFilter struct {
+	FormID     primitive.ObjectID       `bson:"formID" json:"formID" validate:"required"`
+	Conditions []ResponseFieldCondition `bson:"conditions" json:"conditions" validate:"dive"`
+}
+
+// ResponseFieldCondition matches a single field in the response data
+type ResponseFieldCondition struct {
+	FieldID    string     `bson:"fieldID" json:"fieldID" validate:"required"`
+	Comparison Comparison `bson:"comparison" json:"comparison" validate:"required,comparison"`
+	Value      string     `bson:"value" json:"value"`
+}
+
+// ReleasesPlace reports whether a response with the decision gives its place on the form back
+func (d Decision) ReleasesPlace() bool {
+	return d == DecisionRejected || d == DecisionDeclined
+}
+
+// CurrentDecision is the response's decision, pending when none has been made
+func (r *FormResponse) CurrentDecision() Decision {
+	if r.Decision == "" {
+		return DecisionPending
+	}
+	return r.Decision
+}
+
+// HasTag reports whether the response is tagged with the tag
+func (r *FormResponse) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResponseChangeSource is what made a change to a response
+type ResponseChangeSource string
+
+const (
+	// ResponseChangeAPI changes were made by a signed in user, an organizer or the applicant
+	ResponseChangeAPI ResponseChangeSource = "api"
+
+	// ResponseChangePipeline changes were made by a pipeline action
+	ResponseChangePipeline ResponseChangeSource = "pipeline"
+
+	// ResponseChangeSystem changes follow from something else, like a waitlisted response being promoted
+	ResponseChangeSystem ResponseChangeSource = "system"
+)
+
+// Fields a ResponseFieldChange can be about besides the form's fields, field keys are UUIDs so they can't clash
+const (
+	ResponseStatusFieldID    = "status"
+	ResponseWithdrawnFieldID = "withdrawn"
+)
+
+// ResponseChange records a single change to a response with the data it replaced, so the response can be restored to it
+type ResponseChange struct {
+	ID         primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	ResponseID primitive.ObjectID   `bson:"responseID" json:"responseID"`
+	FormID     primitive.ObjectID   `bson:"formID" json:"formID"`
+	ChangedAt  time.Time            `bson:"changedAt" json:"changedAt"`
+	ChangedBy  primitive.ObjectID   `bson:"changedBy,omitempty" json:"changedBy,omitempty"`
+	Source     ResponseChangeSource `bson:"source" json:"source"`
+	PipelineID primitive.ObjectID   `bson:"pipelineID,omitempty" json:"pipelineID,omitempty"`
+
+	// Action describes the change, eg: edit, decision, withdraw, promotion or restore
+	Action       string             `bson:"action" json:"action"`
+	RestoredFrom primitive.ObjectID `bson:"restoredFrom,omitempty" json:"restoredFrom,omitempty"`
+
+	Changes []ResponseFieldChange `bson:"changes" json:"changes"`
+
+	// PreviousData is the response's data before the change, along with the form version it matched
+	PreviousData          map[string]interface{} `bson:"previousData" json:"previousData"`
+	PreviousFormVersionID primitive.ObjectID     `bson:"previousFormVersionID,omitempty" json:"previousFormVersionID,omitempty"`
+}
+
+// ResponseFieldChange is a field whose value changed, Before or After is nil when the field was added or removed.
+// Field is a form field's key, DecisionFieldID, ResponseStatusFieldID or ResponseWithdrawnFieldID.
+type ResponseFieldChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
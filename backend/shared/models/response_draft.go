@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResponseDraft is an applicant's unfinished response to a form, at most one per user and form.
+// Drafts aren't validated or counted as responses until they're submitted.
+type ResponseDraft struct {
+	ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+	FormID    primitive.ObjectID     `bson:"formID" json:"formID"`
+	UserID    primitive.ObjectID     `bson:"userID" json:"userID"`
+	Data      map[string]interface{} `bson:"data" json:"data"`
+	CreatedAt time.Time              `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time              `bson:"updatedAt" json:"updatedAt"`
+}
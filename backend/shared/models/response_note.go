@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ResponseNote is a note an organizer or reviewer leaves on a response, applicants never see them
+type ResponseNote struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ResponseID primitive.ObjectID `bson:"responseID" json:"responseID"`
+	FormID     primitive.ObjectID `bson:"formID" json:"formID"`
+	AuthorID   primitive.ObjectID `bson:"authorID" json:"authorID"`
+	Body       string             `bson:"body" json:"body" validate:"required,max=5000"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
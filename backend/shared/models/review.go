@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReviewStrategy is how new review assignments are shared out between reviewers
+type ReviewStrategy string
+
+const (
+	// ReviewRoundRobin hands responses to the reviewers in turn
+	ReviewRoundRobin ReviewStrategy = "roundRobin"
+
+	// ReviewLoadBalanced hands each response to the reviewers with the fewest unfinished reviews on the form
+	ReviewLoadBalanced ReviewStrategy = "loadBalanced"
+)
+
+// ReviewRubric is how reviewers score a form's responses, a form has at most one
+type ReviewRubric struct {
+	ID       primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	FormID   primitive.ObjectID `json:"formID" bson:"formID"`
+	EventID  primitive.ObjectID `json:"eventID" bson:"eventID"`
+	Criteria []RubricCriterion  `json:"criteria" bson:"criteria" validate:"required,min=1,dive"`
+
+	// BlindMode hides who submitted a response from its reviewers, along with identifying answers: fields prefilled
+	// from the applicant's profile, telephone, address and file fields and any fields listed in BlindFields.
+	// Uploads like CVs usually name the applicant, BlindShowFiles lets reviewers see them anyway.
+	BlindMode      bool     `json:"blindMode,omitempty" bson:"blindMode"`
+	BlindFields    []string `json:"blindFields,omitempty" bson:"blindFields,omitempty"`
+	BlindShowFiles bool     `json:"blindShowFiles,omitempty" bson:"blindShowFiles,omitempty"`
+
+	UpdatedAt time.Time          `json:"updatedAt" bson:"updatedAt"`
+	UpdatedBy primitive.ObjectID `json:"updatedBy,omitempty" bson:"updatedBy,omitempty"`
+}
+
+// HiddenFields are the fields of the form a reviewer can't see, internal fields unless they're an organizer
+// and in blind mode the fields that could identify the applicant
+func (r *ReviewRubric) HiddenFields(form *FormStructure, isOrganizer bool) map[string]bool {
+	hidden := map[string]bool{}
+	for _, field := range form.Attrs {
+		if field.IsInternal && !isOrganizer {
+			hidden[field.Key] = true
+		}
+
+		if !r.BlindMode {
+			continue
+		}
+		if field.Type == FieldTypeTelephone || field.Type == FieldTypeAddress || (field.Type == FieldTypeFile && !r.BlindShowFiles) {
+			hidden[field.Key] = true
+		}
+		if field.Prefill != nil && field.Prefill.ProfileAttribute != "" {
+			hidden[field.Key] = true
+		}
+	}
+
+	if r.BlindMode {
+		for _, key := range r.BlindFields {
+			hidden[key] = true
+		}
+	}
+	return hidden
+}
+
+// RubricCriterion is scored from 0 to MaxScore, its weight is relative to the rubric's other criteria
+type RubricCriterion struct {
+	Key         string  `json:"key" bson:"key" validate:"required,max=100"`
+	Label       string  `json:"label" bson:"label" validate:"required,max=200"`
+	Description string  `json:"description,omitempty" bson:"description,omitempty" validate:"max=2000"`
+	Weight      float64 `json:"weight" bson:"weight" validate:"gt=0"`
+	MaxScore    int     `json:"maxScore" bson:"maxScore" validate:"min=1,max=100"`
+}
+
+// Score combines a review's criterion scores into a weighted percentage from 0 to 100
+func (r *ReviewRubric) Score(scores map[string]float64) float64 {
+	var total, weights float64
+	for _, criterion := range r.Criteria {
+		total += criterion.Weight * scores[criterion.Key] / float64(criterion.MaxScore)
+		weights += criterion.Weight
+	}
+
+	if weights == 0 {
+		return 0
+	}
+	return total / weights * 100
+}
+
+// Review is a reviewer's assignment to score a response, it's submitted once the reviewer has scored it.
+// Reviews are kept apart from the response, the response only holds their summary.
+type Review struct {
+	ID         primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	FormID     primitive.ObjectID `json:"formID" bson:"formID"`
+	ResponseID primitive.ObjectID `json:"responseID" bson:"responseID"`
+	ReviewerID primitive.ObjectID `json:"reviewerID" bson:"reviewerID"`
+	AssignedBy primitive.ObjectID `json:"assignedBy,omitempty" bson:"assignedBy,omitempty"`
+	AssignedAt time.Time          `json:"assignedAt" bson:"assignedAt"`
+
+	Scores      map[string]float64 `json:"scores,omitempty" bson:"scores,omitempty"`
+	Comment     string             `json:"comment,omitempty" bson:"comment,omitempty"`
+	Score       float64            `json:"score" bson:"score"`
+	SubmittedAt time.Time          `json:"submittedAt,omitempty" bson:"submittedAt,omitempty"`
+}
+
+// IsSubmitted reports whether the reviewer has scored the response
+func (r *Review) IsSubmitted() bool {
+	return !r.SubmittedAt.IsZero()
+}
+
+// ResponseReviewSummary is the average score of a response's submitted reviews, it's kept on the response
+// so responses can be sorted by it
+type ResponseReviewSummary struct {
+	Score     float64   `json:"score" bson:"score"`
+	Count     int       `json:"count" bson:"count"`
+	UpdatedAt time.Time `json:"updatedAt" bson:"updatedAt"`
+}
@@ -15,4 +15,8 @@ type User struct {
 	SchoolEmail  string             `bson:"schoolEmail,omitempty" json:"schoolEmail,omitempty"`
 	Birthday     time.Time          `bson:"birthday" json:"birthday"`
 	PasswordHash string             `bson:"passwordHash" json:"-"` // Don't return the password hash
+
+	// AnonymousLinkRequested is set when the user registers with an email anonymous responses were sent with,
+	// the event listener emails the address a link to confirm they belong to the account
+	AnonymousLinkRequested bool `bson:"anonymousLinkRequested,omitempty" json:"-"`
 }
@@ -14,4 +14,13 @@ var (
 
 	// ErrUserNotAuthorized is returned when the user does not have admin permission to modify the document
 	ErrUserNotAuthorized = errors.New("user is not authorized to modify the document")
+
+	// ErrFormFull is returned when a form has reached its maximum number of submissions
+	ErrFormFull = errors.New("form has reached maximum number of submissions")
+
+	// ErrAlreadySubmitted is returned when the user has already submitted a form that only allows one submission
+	ErrAlreadySubmitted = errors.New("user has already submitted this form")
+
+	// ErrSourceAlreadyExists is returned when a selector source with the same name already exists
+	ErrSourceAlreadyExists = errors.New("source already exists")
 )
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"shared/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ResponseFilterToBSON converts a ResponseFilter into a query on the responses collection
+func ResponseFilterToBSON(f models.ResponseFilter) bson.M {
+	filter := bson.M{"formID": f.FormID, "isDeleted": bson.M{"$ne": true}}
+
+	var conditions []bson.M
+	for _, condition := range f.Conditions {
+		key := "data." + condition.FieldID
+		if condition.FieldID == models.TagsFieldID {
+			key = "tags"
+		}
+		switch condition.Comparison {
+		case models.ComparisonEq:
+			conditions = append(conditions, bson.M{key: condition.Value})
+		case models.ComparisonNeq:
+			conditions = append(conditions, bson.M{key: bson.M{"$ne": condition.Value}})
+		}
+	}
+
+	// $and so that multiple conditions on the same field don't overwrite each other
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	return filter
+}
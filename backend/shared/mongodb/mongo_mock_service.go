@@ -5,6 +5,7 @@ import (
 	"errors"
 	"shared/models"
 	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
@@ -193,6 +194,26 @@ func (m *MockMongoService) UpdateSource(ctx context.Context, source models.Selec
 	return &mongo.UpdateResult{ModifiedCount: 1}, nil
 }
 
+func (m *MockMongoService) GetEventSelectorSource(ctx context.Context, eventID primitive.ObjectID, name string) (*models.SelectorSource, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*models.SelectorSource, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListSelectorSources(ctx context.Context, filter bson.M) ([]models.SelectorSource, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SaveSelectorSourceRefresh(ctx context.Context, sourceID primitive.ObjectID, options []string, refreshErr error) error {
+	return nil
+}
+
 // matchesFilter is a helper function that matches an event against a filter.
 // This is a simplified example. You would need to implement matching logic
 // based on your application's requirements.
@@ -356,14 +377,26 @@ func (m *MockMongoService) CreateResponse(ctx context.Context, submission models
 	return nil, nil
 }
 
-func (m *MockMongoService) ListResponses(ctx context.Context, filter bson.M) ([]models.FormResponse, error) {
+func (m *MockMongoService) ListResponses(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.FormResponse, error) {
 	return nil, nil
 }
 
+func (m *MockMongoService) StreamResponses(ctx context.Context, filter bson.M, options *options.FindOptions, fn func(response models.FormResponse) error) error {
+	return nil
+}
+
 func (m *MockMongoService) UpdateResponse(ctx context.Context, submission models.FormResponse, submissionID primitive.ObjectID) (*mongo.UpdateResult, error) {
 	return nil, nil
 }
 
+func (m *MockMongoService) SetResponseDecision(ctx context.Context, responseID primitive.ObjectID, previous models.Decision, decision models.Decision, decidedBy primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
+func (m *MockMongoService) SetResponseTags(ctx context.Context, responseID primitive.ObjectID, previous []string, tags []string) (bool, error) {
+	return false, nil
+}
+
 func (m *MockMongoService) DeleteResponse(ctx context.Context, submissionID primitive.ObjectID) (*mongo.DeleteResult, error) {
 	return nil, nil
 }
@@ -423,3 +456,323 @@ func (m *MockMongoService) DeleteEventSecrets(ctx context.Context, secretID prim
 func (m *MockMongoService) DeleteEventSecret(ctx context.Context, eventID primitive.ObjectID, secretID primitive.ObjectID) (*mongo.DeleteResult, error) {
 	return nil, nil
 }
+
+func (m *MockMongoService) CountResponses(ctx context.Context, filter bson.M) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockMongoService) AggregateResponses(ctx context.Context, pipeline mongo.Pipeline) ([]bson.M, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListEmailCampaigns(ctx context.Context, filter bson.M) ([]models.EmailCampaign, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*models.EmailCampaign, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateEmailCampaign(ctx context.Context, campaign models.EmailCampaign) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CancelEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ClaimScheduledEmailCampaign(ctx context.Context) (*models.EmailCampaign, error) {
+	return nil, mongo.ErrNoDocuments
+}
+
+func (m *MockMongoService) StartEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, totalRecipients int, templateVersion int, templateComponents []models.EmailComponentRef) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) RecordEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, email string, failure *models.EmailCampaignFailure) (*models.EmailCampaign, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) FinishEmailCampaign(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, status models.EmailCampaignStatus, errorMsg string) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListEmailSuppressions(ctx context.Context, filter bson.M) ([]models.EmailSuppression, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*models.EmailSuppression, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateEmailSuppression(ctx context.Context, suppression models.EmailSuppression) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) IsEmailSuppressed(ctx context.Context, email string, eventID primitive.ObjectID, includeEventLevel bool) (bool, error) {
+	return false, nil
+}
+
+func (m *MockMongoService) ListEmailTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]models.EmailTemplateVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetEmailTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.EmailTemplateVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SetPipelineActionEmailTemplateVersion(ctx context.Context, pipelineRunID primitive.ObjectID, actionID primitive.ObjectID, version int, components []models.EmailComponentRef) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListEmailComponents(ctx context.Context, filter bson.M) ([]models.EmailComponent, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*models.EmailComponent, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateEmailComponent(ctx context.Context, component models.EmailComponent) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) UpdateEmailComponent(ctx context.Context, component models.EmailComponent, componentID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListEmailComponentVersions(ctx context.Context, componentID primitive.ObjectID) ([]models.EmailComponentVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetEmailComponentVersion(ctx context.Context, componentID primitive.ObjectID, version int) (*models.EmailComponentVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) FindUserByID(ctx context.Context, userID primitive.ObjectID) (*models.User, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) IsEventOrganizer(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
+func (m *MockMongoService) GetDigestSubscription(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (*models.DigestSubscription, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) UpsertDigestSubscription(ctx context.Context, subscription models.DigestSubscription) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListDueDigestSubscriptions(ctx context.Context, now time.Time) ([]models.DigestSubscription, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ClaimDigestSubscription(ctx context.Context, subscription models.DigestSubscription, sentAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *MockMongoService) CreateFileUpload(ctx context.Context, upload models.FileUpload) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*models.FileUpload, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) AttachFileUploads(ctx context.Context, responseID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListOrphanedFileUploads(ctx context.Context, createdBefore time.Time) ([]models.FileUpload, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) AttachDraftFileUploads(ctx context.Context, draftID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetResponseDraft(ctx context.Context, formID primitive.ObjectID, userID primitive.ObjectID) (*models.ResponseDraft, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SaveResponseDraft(ctx context.Context, draft models.ResponseDraft) (*models.ResponseDraft, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteResponseDraft(ctx context.Context, draftID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) WithdrawResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateFormVersion(ctx context.Context, version models.FormVersion) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListFormVersions(ctx context.Context, formID primitive.ObjectID) ([]models.FormVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetFormVersion(ctx context.Context, formID primitive.ObjectID, version int) (*models.FormVersion, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ReserveFormSubmission(ctx context.Context, form models.FormStructure, response models.FormResponse) (int, error) {
+	return 0, nil
+}
+
+func (m *MockMongoService) ReleaseFormSubmission(ctx context.Context, response models.FormResponse, heldPlace bool) (*models.FormResponse, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ReleaseResponsePlace(ctx context.Context, responseID primitive.ObjectID) (*models.FormResponse, *models.FormResponse, error) {
+	return nil, nil, nil
+}
+
+func (m *MockMongoService) ReclaimResponsePlace(ctx context.Context, form models.FormStructure, responseID primitive.ObjectID) (models.ResponseStatus, int, error) {
+	return "", 0, nil
+}
+
+func (m *MockMongoService) CreateFormInvite(ctx context.Context, invite models.FormInvite) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*models.FormInvite, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListFormInvites(ctx context.Context, formID primitive.ObjectID) ([]models.FormInvite, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) RevokeFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) UseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (bool, error) {
+	return false, nil
+}
+
+func (m *MockMongoService) ReleaseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SavePendingResponse(ctx context.Context, pending models.PendingResponse) (*models.PendingResponse, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CountPendingResponses(ctx context.Context, filter bson.M) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockMongoService) ClaimPendingResponseConfirmation(ctx context.Context) (*models.PendingResponse, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ConfirmPendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*models.PendingResponse, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ReleasePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeletePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteExpiredPendingResponses(ctx context.Context) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) LinkAnonymousResponses(ctx context.Context, email string, userID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ClaimAnonymousLinkRequest(ctx context.Context) (*models.User, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetReviewRubric(ctx context.Context, formID primitive.ObjectID) (*models.ReviewRubric, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SaveReviewRubric(ctx context.Context, rubric models.ReviewRubric) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateReviews(ctx context.Context, reviews []models.Review) error {
+	return nil
+}
+
+func (m *MockMongoService) ListReviews(ctx context.Context, filter bson.M) ([]models.Review, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetReview(ctx context.Context, responseID primitive.ObjectID, reviewerID primitive.ObjectID) (*models.Review, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SubmitReview(ctx context.Context, review models.Review) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteReview(ctx context.Context, reviewID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) SummarizeResponseReviews(ctx context.Context, responseID primitive.ObjectID) (*models.ResponseReviewSummary, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) RecordResponseChange(ctx context.Context, change models.ResponseChange) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListResponseChanges(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseChange, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetResponseChange(ctx context.Context, changeID primitive.ObjectID) (*models.ResponseChange, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListResponseTags(ctx context.Context, formID primitive.ObjectID) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) CreateResponseNote(ctx context.Context, note models.ResponseNote) (*mongo.InsertOneResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) ListResponseNotes(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseNote, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) GetResponseNote(ctx context.Context, noteID primitive.ObjectID) (*models.ResponseNote, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) UpdateResponseNote(ctx context.Context, noteID primitive.ObjectID, body string) (*mongo.UpdateResult, error) {
+	return nil, nil
+}
+
+func (m *MockMongoService) DeleteResponseNote(ctx context.Context, noteID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return nil, nil
+}
@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"shared/models"
 	"shared/utils"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +36,11 @@ type MongoService interface {
 	CreateSource(ctx context.Context, source models.SelectorSource) (*mongo.InsertOneResult, error)
 	UpdateSource(ctx context.Context, source models.SelectorSource, sourceID primitive.ObjectID) (*mongo.UpdateResult, error)
 	GetSourceByName(ctx context.Context, name string) (*models.SelectorSource, error)
+	GetEventSelectorSource(ctx context.Context, eventID primitive.ObjectID, name string) (*models.SelectorSource, error)
+	GetSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*models.SelectorSource, error)
+	ListSelectorSources(ctx context.Context, filter bson.M) ([]models.SelectorSource, error)
+	DeleteSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*mongo.DeleteResult, error)
+	SaveSelectorSourceRefresh(ctx context.Context, sourceID primitive.ObjectID, options []string, refreshErr error) error
 	GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error)
 	ListForms(ctx context.Context, filter bson.M) ([]models.FormStructure, error)
 	CreateForm(ctx context.Context, form models.FormStructure) (*mongo.InsertOneResult, error)
@@ -45,9 +52,13 @@ type MongoService interface {
 	GetPipeline(ctx context.Context, pipelineID primitive.ObjectID) (*models.PipelineConfiguration, error)
 	ListPipelines(ctx context.Context, filter bson.M) ([]models.PipelineConfiguration, error)
 	DeletePipeline(ctx context.Context, pipelineID primitive.ObjectID) (*mongo.DeleteResult, error)
-	ListResponses(ctx context.Context, filter bson.M) ([]models.FormResponse, error)
+	ListResponses(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.FormResponse, error)
+	StreamResponses(ctx context.Context, filter bson.M, options *options.FindOptions, fn func(response models.FormResponse) error) error
 	CreateResponse(ctx context.Context, response models.FormResponse) (*mongo.InsertOneResult, error)
 	UpdateResponse(ctx context.Context, response models.FormResponse, responseID primitive.ObjectID) (*mongo.UpdateResult, error)
+	SetResponseDecision(ctx context.Context, responseID primitive.ObjectID, previous models.Decision, decision models.Decision, decidedBy primitive.ObjectID) (bool, error)
+	SetResponseTags(ctx context.Context, responseID primitive.ObjectID, previous []string, tags []string) (bool, error)
+	WithdrawResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.UpdateResult, error)
 	DeleteResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.DeleteResult, error)
 	CreatePipelineRun(ctx context.Context, pipelineRun models.PipelineRun) (*mongo.InsertOneResult, error)
 	GetPipelineRun(ctx context.Context, filter bson.M) (*models.PipelineRun, error)
@@ -61,6 +72,85 @@ type MongoService interface {
 	GetEventSecrets(ctx context.Context, filter bson.M, stripSecrets bool) (*models.EventSecrets, error)
 	CreateOrUpdateEventSecrets(ctx context.Context, secret models.EventSecrets) (*mongo.UpdateResult, error)
 	DeleteEventSecrets(ctx context.Context, secretID primitive.ObjectID) (*mongo.DeleteResult, error)
+	CountResponses(ctx context.Context, filter bson.M) (int64, error)
+	AggregateResponses(ctx context.Context, pipeline mongo.Pipeline) ([]bson.M, error)
+	ListEmailCampaigns(ctx context.Context, filter bson.M) ([]models.EmailCampaign, error)
+	GetEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*models.EmailCampaign, error)
+	CreateEmailCampaign(ctx context.Context, campaign models.EmailCampaign) (*mongo.InsertOneResult, error)
+	CancelEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*mongo.UpdateResult, error)
+	ClaimScheduledEmailCampaign(ctx context.Context) (*models.EmailCampaign, error)
+	StartEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, totalRecipients int, templateVersion int, templateComponents []models.EmailComponentRef) (*mongo.UpdateResult, error)
+	RecordEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, email string, failure *models.EmailCampaignFailure) (*models.EmailCampaign, error)
+	FinishEmailCampaign(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, status models.EmailCampaignStatus, errorMsg string) (*mongo.UpdateResult, error)
+	ListEmailSuppressions(ctx context.Context, filter bson.M) ([]models.EmailSuppression, error)
+	GetEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*models.EmailSuppression, error)
+	CreateEmailSuppression(ctx context.Context, suppression models.EmailSuppression) (*mongo.UpdateResult, error)
+	DeleteEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*mongo.DeleteResult, error)
+	IsEmailSuppressed(ctx context.Context, email string, eventID primitive.ObjectID, includeEventLevel bool) (bool, error)
+	ListEmailTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]models.EmailTemplateVersion, error)
+	GetEmailTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.EmailTemplateVersion, error)
+	SetPipelineActionEmailTemplateVersion(ctx context.Context, pipelineRunID primitive.ObjectID, actionID primitive.ObjectID, version int, components []models.EmailComponentRef) (*mongo.UpdateResult, error)
+	ListEmailComponents(ctx context.Context, filter bson.M) ([]models.EmailComponent, error)
+	GetEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*models.EmailComponent, error)
+	CreateEmailComponent(ctx context.Context, component models.EmailComponent) (*mongo.InsertOneResult, error)
+	UpdateEmailComponent(ctx context.Context, component models.EmailComponent, componentID primitive.ObjectID) (*mongo.UpdateResult, error)
+	DeleteEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*mongo.DeleteResult, error)
+	ListEmailComponentVersions(ctx context.Context, componentID primitive.ObjectID) ([]models.EmailComponentVersion, error)
+	GetEmailComponentVersion(ctx context.Context, componentID primitive.ObjectID, version int) (*models.EmailComponentVersion, error)
+	FindUserByID(ctx context.Context, userID primitive.ObjectID) (*models.User, error)
+	IsEventOrganizer(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (bool, error)
+	GetDigestSubscription(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (*models.DigestSubscription, error)
+	UpsertDigestSubscription(ctx context.Context, subscription models.DigestSubscription) (*mongo.UpdateResult, error)
+	ListDueDigestSubscriptions(ctx context.Context, now time.Time) ([]models.DigestSubscription, error)
+	ClaimDigestSubscription(ctx context.Context, subscription models.DigestSubscription, sentAt time.Time) (bool, error)
+	CreateFileUpload(ctx context.Context, upload models.FileUpload) (*mongo.InsertOneResult, error)
+	GetFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*models.FileUpload, error)
+	AttachFileUploads(ctx context.Context, responseID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error)
+	ListOrphanedFileUploads(ctx context.Context, createdBefore time.Time) ([]models.FileUpload, error)
+	DeleteFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*mongo.DeleteResult, error)
+	AttachDraftFileUploads(ctx context.Context, draftID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error)
+	GetResponseDraft(ctx context.Context, formID primitive.ObjectID, userID primitive.ObjectID) (*models.ResponseDraft, error)
+	SaveResponseDraft(ctx context.Context, draft models.ResponseDraft) (*models.ResponseDraft, error)
+	DeleteResponseDraft(ctx context.Context, draftID primitive.ObjectID) (*mongo.DeleteResult, error)
+	CreateFormVersion(ctx context.Context, version models.FormVersion) (*mongo.InsertOneResult, error)
+	ListFormVersions(ctx context.Context, formID primitive.ObjectID) ([]models.FormVersion, error)
+	GetFormVersion(ctx context.Context, formID primitive.ObjectID, version int) (*models.FormVersion, error)
+	ReserveFormSubmission(ctx context.Context, form models.FormStructure, response models.FormResponse) (int, error)
+	ReleaseFormSubmission(ctx context.Context, response models.FormResponse, heldPlace bool) (*models.FormResponse, error)
+	ReleaseResponsePlace(ctx context.Context, responseID primitive.ObjectID) (*models.FormResponse, *models.FormResponse, error)
+	ReclaimResponsePlace(ctx context.Context, form models.FormStructure, responseID primitive.ObjectID) (models.ResponseStatus, int, error)
+	CreateFormInvite(ctx context.Context, invite models.FormInvite) (*mongo.InsertOneResult, error)
+	GetFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*models.FormInvite, error)
+	ListFormInvites(ctx context.Context, formID primitive.ObjectID) ([]models.FormInvite, error)
+	RevokeFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error)
+	UseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (bool, error)
+	ReleaseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error)
+	SavePendingResponse(ctx context.Context, pending models.PendingResponse) (*models.PendingResponse, error)
+	CountPendingResponses(ctx context.Context, filter bson.M) (int64, error)
+	ClaimPendingResponseConfirmation(ctx context.Context) (*models.PendingResponse, error)
+	ConfirmPendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*models.PendingResponse, error)
+	ReleasePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.UpdateResult, error)
+	DeletePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.DeleteResult, error)
+	DeleteExpiredPendingResponses(ctx context.Context) (*mongo.DeleteResult, error)
+	LinkAnonymousResponses(ctx context.Context, email string, userID primitive.ObjectID) (*mongo.UpdateResult, error)
+	ClaimAnonymousLinkRequest(ctx context.Context) (*models.User, error)
+	GetReviewRubric(ctx context.Context, formID primitive.ObjectID) (*models.ReviewRubric, error)
+	SaveReviewRubric(ctx context.Context, rubric models.ReviewRubric) (*mongo.UpdateResult, error)
+	CreateReviews(ctx context.Context, reviews []models.Review) error
+	ListReviews(ctx context.Context, filter bson.M) ([]models.Review, error)
+	GetReview(ctx context.Context, responseID primitive.ObjectID, reviewerID primitive.ObjectID) (*models.Review, error)
+	SubmitReview(ctx context.Context, review models.Review) (*mongo.UpdateResult, error)
+	DeleteReview(ctx context.Context, reviewID primitive.ObjectID) (*mongo.DeleteResult, error)
+	SummarizeResponseReviews(ctx context.Context, responseID primitive.ObjectID) (*models.ResponseReviewSummary, error)
+	RecordResponseChange(ctx context.Context, change models.ResponseChange) (*mongo.InsertOneResult, error)
+	ListResponseChanges(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseChange, error)
+	GetResponseChange(ctx context.Context, changeID primitive.ObjectID) (*models.ResponseChange, error)
+	ListResponseTags(ctx context.Context, formID primitive.ObjectID) ([]string, error)
+	CreateResponseNote(ctx context.Context, note models.ResponseNote) (*mongo.InsertOneResult, error)
+	ListResponseNotes(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseNote, error)
+	GetResponseNote(ctx context.Context, noteID primitive.ObjectID) (*models.ResponseNote, error)
+	UpdateResponseNote(ctx context.Context, noteID primitive.ObjectID, body string) (*mongo.UpdateResult, error)
+	DeleteResponseNote(ctx context.Context, noteID primitive.ObjectID) (*mongo.DeleteResult, error)
 }
 
 // Service implements MongoService with a mongo.Client.
@@ -98,6 +188,16 @@ func (s *Service) FindUserByEmail(ctx context.Context, email string) (*models.Us
 	return &user, nil
 }
 
+// FindUserByID finds a user by their ID.
+func (s *Service) FindUserByID(ctx context.Context, userID primitive.ObjectID) (*models.User, error) {
+	var user models.User
+	err := s.Database.Collection("users").FindOne(ctx, bson.M{"_id": userID}).Decode(&user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 // InsertUser inserts a new user into the database.
 func (s *Service) InsertUser(ctx context.Context, user models.User) (*mongo.InsertOneResult, error) {
 	// First check if email is already registered
@@ -289,35 +389,128 @@ func (s *Service) UpdateUserDetails(ctx context.Context, userId primitive.Object
 	return nil
 }
 
-// GetSourceByName retrieves a SelectorSource by its name
+// GetSourceByName retrieves a built-in SelectorSource's cached options by its name
 func (s *Service) GetSourceByName(ctx context.Context, name string) (*models.SelectorSource, error) {
 	var source models.SelectorSource
-	err := s.Database.Collection("sources").FindOne(ctx, bson.M{"sourceName": name}).Decode(&source)
+	filter := bson.M{"sourceName": name, "eventID": bson.M{"$exists": false}}
+	err := s.Database.Collection("sources").FindOne(ctx, filter).Decode(&source)
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// GetEventSelectorSource retrieves one of an event's selector sources by its name
+func (s *Service) GetEventSelectorSource(ctx context.Context, eventID primitive.ObjectID, name string) (*models.SelectorSource, error) {
+	var source models.SelectorSource
+	err := s.Database.Collection("sources").FindOne(ctx, bson.M{"sourceName": name, "eventID": eventID}).Decode(&source)
+	if err != nil {
+		return nil, err
+	}
+	return &source, nil
+}
+
+// GetSelectorSource retrieves a selector source by its ID
+func (s *Service) GetSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*models.SelectorSource, error) {
+	var source models.SelectorSource
+	err := s.Database.Collection("sources").FindOne(ctx, bson.M{"_id": sourceID}).Decode(&source)
 	if err != nil {
 		return nil, err
 	}
 	return &source, nil
 }
 
+// ListSelectorSources retrieves selector sources based on a filter, without their options since lists can be large
+func (s *Service) ListSelectorSources(ctx context.Context, filter bson.M) ([]models.SelectorSource, error) {
+	var sources []models.SelectorSource
+
+	opts := options.Find().SetProjection(bson.M{"options": 0}).SetSort(bson.D{{Key: "sourceName", Value: 1}})
+	cursor, err := s.Database.Collection("sources").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var source models.SelectorSource
+		if err := cursor.Decode(&source); err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, source)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If sources is null then return an empty slice instead
+	if sources == nil {
+		return []models.SelectorSource{}, nil
+	}
+
+	return sources, nil
+}
+
+// CreateSource adds a selector source, names are unique among the built-ins and within each event
 func (s *Service) CreateSource(ctx context.Context, source models.SelectorSource) (*mongo.InsertOneResult, error) {
-	_, err := s.GetSourceByName(ctx, source.SourceName)
+	var err error
+	if source.IsBuiltin() {
+		_, err = s.GetSourceByName(ctx, source.SourceName)
+	} else {
+		_, err = s.GetEventSelectorSource(ctx, source.EventID, source.SourceName)
+	}
 	if err == nil {
-		return nil, errors.New("source already exists")
+		return nil, ErrSourceAlreadyExists
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	if source.Options == nil {
+		source.Options = []string{}
 	}
 	return s.Database.Collection("sources").InsertOne(ctx, source)
 }
 
+// UpdateSource replaces an event source's settings and options, the event and creator are kept
 func (s *Service) UpdateSource(ctx context.Context, source models.SelectorSource, sourceID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	if source.Options == nil {
+		source.Options = []string{}
+	}
+
 	update := bson.M{"$set": bson.M{
-		"sourceName":  source.SourceName,
-		"lastUpdated": source.LastUpdated,
-		"options":     source.Options,
+		"sourceName":        source.SourceName,
+		"kind":              source.Kind,
+		"url":               source.URL,
+		"format":            source.Format,
+		"refreshEveryHours": source.RefreshEveryHours,
+		"lastUpdated":       source.LastUpdated,
+		"lastError":         source.LastError,
+		"options":           source.Options,
 	}}
 
 	filter := bson.M{"_id": sourceID}
 	return s.Database.Collection("sources").UpdateOne(ctx, filter, update)
 }
 
+// DeleteSelectorSource removes a selector source by its ID
+func (s *Service) DeleteSelectorSource(ctx context.Context, sourceID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("sources").DeleteOne(ctx, bson.M{"_id": sourceID})
+}
+
+// SaveSelectorSourceRefresh records the result of refreshing a source. A failed refresh keeps the previous options
+// so forms keep working, only the error is recorded.
+func (s *Service) SaveSelectorSourceRefresh(ctx context.Context, sourceID primitive.ObjectID, sourceOptions []string, refreshErr error) error {
+	update := bson.M{"$set": bson.M{"options": sourceOptions, "lastUpdated": time.Now(), "lastError": ""}}
+	if refreshErr != nil {
+		update = bson.M{"$set": bson.M{"lastError": refreshErr.Error()}}
+	}
+
+	_, err := s.Database.Collection("sources").UpdateOne(ctx, bson.M{"_id": sourceID}, update)
+	return err
+}
+
 // GetForm retrieves a form by its ID
 func (s *Service) GetForm(ctx context.Context, formID primitive.ObjectID, stripSecrets bool) (*models.FormStructure, error) {
 	var form models.FormStructure
@@ -338,7 +531,10 @@ func (s *Service) CreateForm(ctx context.Context, form models.FormStructure) (*m
 	form.CreatedAt = time.Now()
 	form.UpdatedAt = time.Now()
 	form.IsDeleted = false
-	form.Status = "draft"
+	// Forms start as drafts unless they're created published
+	if form.Status != "published" {
+		form.Status = "draft"
+	}
 	return s.Database.Collection("forms").InsertOne(ctx, form)
 }
 
@@ -352,6 +548,56 @@ func (s *Service) UpdateForm(ctx context.Context, form models.FormStructure, for
 	return s.Database.Collection("forms").UpdateOne(ctx, filter, update)
 }
 
+// CreateFormVersion saves a copy of a form's schema
+func (s *Service) CreateFormVersion(ctx context.Context, version models.FormVersion) (*mongo.InsertOneResult, error) {
+	version.CreatedAt = time.Now()
+	return s.Database.Collection("form_versions").InsertOne(ctx, version)
+}
+
+// ListFormVersions lists every saved version of a form, oldest first
+func (s *Service) ListFormVersions(ctx context.Context, formID primitive.ObjectID) ([]models.FormVersion, error) {
+	var versions []models.FormVersion
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: 1}})
+	cursor, err := s.Database.Collection("form_versions").Find(ctx, bson.M{"formID": formID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var version models.FormVersion
+		if err := cursor.Decode(&version); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If versions is null then return an empty slice instead
+	if versions == nil {
+		return []models.FormVersion{}, nil
+	}
+
+	return versions, nil
+}
+
+// GetFormVersion retrieves one saved version of a form
+func (s *Service) GetFormVersion(ctx context.Context, formID primitive.ObjectID, version int) (*models.FormVersion, error) {
+	var formVersion models.FormVersion
+
+	err := s.Database.Collection("form_versions").FindOne(ctx, bson.M{"formID": formID, "version": version}).Decode(&formVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &formVersion, nil
+}
+
 // AddAllowedSubmitter adds a new allowed submitter to a form
 func (s *Service) AddAllowedSubmitter(ctx context.Context, formID primitive.ObjectID, submitter models.FormAllowedSubmitter) (*mongo.UpdateResult, error) {
 	// Prepare the update
@@ -452,11 +698,11 @@ func (s *Service) DeletePipeline(ctx context.Context, pipelineID primitive.Objec
 	return s.Database.Collection("pipeline_configs").DeleteOne(ctx, filter)
 }
 
-// ListResponses retrieves responses based on a filter
-func (s *Service) ListResponses(ctx context.Context, filter bson.M) ([]models.FormResponse, error) {
+// ListResponses retrieves responses based on a filter, options can sort and limit them
+func (s *Service) ListResponses(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.FormResponse, error) {
 	var responses []models.FormResponse
 
-	cursor, err := s.Database.Collection("responses").Find(ctx, filter)
+	cursor, err := s.Database.Collection("responses").Find(ctx, filter, options)
 	if err != nil {
 		return nil, err
 	}
@@ -483,19 +729,381 @@ func (s *Service) ListResponses(ctx context.Context, filter bson.M) ([]models.Fo
 	return responses, nil
 }
 
+// StreamResponses calls fn with each response matching the filter as it's read from the cursor, so large forms
+// don't have to be held in memory. An error from fn stops the stream and is returned.
+func (s *Service) StreamResponses(ctx context.Context, filter bson.M, options *options.FindOptions, fn func(response models.FormResponse) error) error {
+	cursor, err := s.Database.Collection("responses").Find(ctx, filter, options)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var response models.FormResponse
+		if err := cursor.Decode(&response); err != nil {
+			return err
+		}
+
+		if err := fn(response); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
 // CreateResponse creates a new response
 func (s *Service) CreateResponse(ctx context.Context, response models.FormResponse) (*mongo.InsertOneResult, error) {
 	return s.Database.Collection("responses").InsertOne(ctx, response)
 }
 
-// UpdateResponse updates a response by its ID
+// UpdateResponse saves a response's edited data and the form version it was validated against. Only those fields
+// are written so an edit doesn't undo a decision, tags, review scores or a promotion saved since the response was read.
 func (s *Service) UpdateResponse(ctx context.Context, response models.FormResponse, responseID primitive.ObjectID) (*mongo.UpdateResult, error) {
-	response.UpdatedAt = time.Now()
-	update := bson.M{"$set": response}
+	set := bson.M{"data": response.Data, "updatedAt": time.Now()}
+	update := bson.M{"$set": set}
+	if response.FormVersionID.IsZero() {
+		update["$unset"] = bson.M{"formVersionID": ""}
+	} else {
+		set["formVersionID"] = response.FormVersionID
+	}
+
 	filter := bson.M{"_id": responseID}
 	return s.Database.Collection("responses").UpdateOne(ctx, filter, update)
 }
 
+// SetResponseDecision changes a response's decision if it's still the previous one, it reports whether the decision
+// was changed. Responses without a decision are pending.
+func (s *Service) SetResponseDecision(ctx context.Context, responseID primitive.ObjectID, previous models.Decision, decision models.Decision, decidedBy primitive.ObjectID) (bool, error) {
+	filter := bson.M{"_id": responseID, "decision": previous}
+	if previous == models.DecisionPending {
+		filter["decision"] = bson.M{"$in": bson.A{models.DecisionPending, nil}}
+	}
+
+	update := bson.M{"$set": bson.M{"decision": decision, "decidedAt": time.Now(), "decidedBy": decidedBy}}
+	result, err := s.Database.Collection("responses").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// SetResponseTags replaces a response's tags if they're still the previous ones, it reports whether they were replaced.
+// Tags are saved sorted so the same set always compares equal.
+func (s *Service) SetResponseTags(ctx context.Context, responseID primitive.ObjectID, previous []string, tags []string) (bool, error) {
+	filter := bson.M{"_id": responseID, "tags": previous}
+	if len(previous) == 0 {
+		filter["tags"] = bson.M{"$in": bson.A{nil, bson.A{}}}
+	}
+
+	update := bson.M{"$set": bson.M{"tags": tags}}
+	if len(tags) == 0 {
+		update = bson.M{"$unset": bson.M{"tags": ""}}
+	}
+
+	result, err := s.Database.Collection("responses").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount == 1, nil
+}
+
+// WithdrawResponse soft deletes a response, withdrawn responses are kept but no longer count as submissions
+func (s *Service) WithdrawResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": responseID, "isDeleted": bson.M{"$ne": true}}
+	update := bson.M{"$set": bson.M{"isDeleted": true, "deletedAt": time.Now()}}
+	return s.Database.Collection("responses").UpdateOne(ctx, filter, update)
+}
+
+// formSubmitterID is the ID of the document claiming a user's only submission to a form
+func formSubmitterID(formID primitive.ObjectID, response models.FormResponse) string {
+	return formID.Hex() + ":" + response.SubmitterKey()
+}
+
+// submitterResponsesFilter matches the responses the submitter of the response has made to the form
+func submitterResponsesFilter(formID primitive.ObjectID, response models.FormResponse) bson.M {
+	filter := bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}}
+	if response.AnonymousEmail != "" {
+		filter["anonymousEmail"] = strings.ToLower(response.AnonymousEmail)
+	} else {
+		filter["userID"] = response.UserID
+	}
+	return filter
+}
+
+// ReserveFormSubmission atomically claims a submission on the form for the response's submitter before it's saved.
+// Forms that don't allow multiple submissions get one claim document per user or anonymous email, so a second concurrent submission
+// fails on the duplicate ID, and the form's submission counter is only incremented while it's under MaxSubmissions.
+// When the form is full and has a waitlist the returned waitlist position is above zero, the response should be waitlisted.
+// Returns ErrAlreadySubmitted or ErrFormFull when the submission isn't allowed.
+func (s *Service) ReserveFormSubmission(ctx context.Context, form models.FormStructure, response models.FormResponse) (int, error) {
+	submitters := s.Database.Collection("form_submitters")
+	claimed := false
+
+	if !form.AllowMultipleSubmissions {
+		claim := bson.M{"_id": formSubmitterID(form.ID, response), "formID": form.ID, "userID": response.UserID, "createdAt": time.Now()}
+		if _, err := submitters.InsertOne(ctx, claim); err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return 0, ErrAlreadySubmitted
+			}
+			return 0, err
+		}
+		claimed = true
+
+		// Responses from before claims were recorded don't have one, the claim is kept since the user has submitted
+		existing, err := s.CountResponses(ctx, submitterResponsesFilter(form.ID, response))
+		if err != nil {
+			submitters.DeleteOne(ctx, bson.M{"_id": formSubmitterID(form.ID, response)})
+			return 0, err
+		}
+		if existing > 0 {
+			return 0, ErrAlreadySubmitted
+		}
+	}
+
+	err := s.incrementSubmissionCount(ctx, form)
+	if err == ErrFormFull && form.EnableWaitlist {
+		position, err := s.nextWaitlistPosition(ctx, form.ID)
+		if err == nil {
+			return position, nil
+		}
+	}
+
+	if err != nil {
+		if claimed {
+			submitters.DeleteOne(ctx, bson.M{"_id": formSubmitterID(form.ID, response)})
+		}
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+// submissionCounter is a form's document in form_submission_counts. Waitlisted counts the responses on the waitlist,
+// it's nil on counters from before it was kept.
+type submissionCounter struct {
+	Count        int  `bson:"count"`
+	WaitlistNext int  `bson:"waitlistNext"`
+	Waitlisted   *int `bson:"waitlisted"`
+}
+
+// nextWaitlistPosition takes the next position on a full form's waitlist. The counter exists once the form is full,
+// positions only go up so the waitlist order is stable.
+func (s *Service) nextWaitlistPosition(ctx context.Context, formID primitive.ObjectID) (int, error) {
+	var counter submissionCounter
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	update := bson.M{"$inc": bson.M{"waitlistNext": 1, "waitlisted": 1}}
+	err := s.Database.Collection("form_submission_counts").FindOneAndUpdate(ctx, bson.M{"_id": formID}, update, opts).Decode(&counter)
+	if err != nil {
+		return 0, err
+	}
+	return counter.WaitlistNext, nil
+}
+
+// incrementSubmissionCount adds one to the form's submission counter if it's under MaxSubmissions and, on forms with
+// a waitlist, nobody is waitlisted, so a new submission can't take a place ahead of the waitlist.
+// The counter is created from the current responses the first time it's used.
+func (s *Service) incrementSubmissionCount(ctx context.Context, form models.FormStructure) error {
+	counters := s.Database.Collection("form_submission_counts")
+
+	filter := bson.M{"_id": form.ID}
+	if form.MaxSubmissions > 0 {
+		filter["count"] = bson.M{"$lt": form.MaxSubmissions}
+	}
+	if form.EnableWaitlist {
+		filter["waitlisted"] = bson.M{"$lte": 0}
+	}
+
+	for seeded := false; ; seeded = true {
+		result, err := counters.UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"count": 1}})
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 1 {
+			return nil
+		}
+
+		// Either the form is full, or its counter doesn't exist yet or doesn't count the waitlist
+		var counter submissionCounter
+		err = counters.FindOne(ctx, bson.M{"_id": form.ID}).Decode(&counter)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+		if seeded || (err == nil && (!form.EnableWaitlist || counter.Waitlisted != nil)) {
+			return ErrFormFull
+		}
+
+		count, err := s.CountResponses(ctx, PlaceHoldersFilter(form.ID))
+		if err != nil {
+			return err
+		}
+		waitlisted, err := s.CountResponses(ctx, waitlistedFilter(form.ID))
+		if err != nil {
+			return err
+		}
+
+		// A concurrent submission may seed the counter first, in which case the upsert fails on the duplicate ID
+		seed := bson.M{"$setOnInsert": bson.M{"count": count}, "$set": bson.M{"waitlisted": waitlisted}}
+		_, err = counters.UpdateOne(ctx, bson.M{"_id": form.ID, "waitlisted": bson.M{"$exists": false}}, seed, options.Update().SetUpsert(true))
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return err
+		}
+	}
+}
+
+// waitlistedFilter matches the form's responses on its waitlist
+func waitlistedFilter(formID primitive.ObjectID) bson.M {
+	return bson.M{"formID": formID, "status": models.ResponseWaitlisted, "isDeleted": bson.M{"$ne": true}}
+}
+
+// releasePlace gives back one of the form's places. While responses are waitlisted the place goes to the first of them
+// in the same update, so a new submission can't take it in between, and the promoted response is returned.
+func (s *Service) releasePlace(ctx context.Context, formID primitive.ObjectID) (*models.FormResponse, error) {
+	waiting := bson.M{"$gt": bson.A{"$waitlisted", 0}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"count":      bson.M{"$cond": bson.A{waiting, "$count", bson.M{"$max": bson.A{bson.M{"$subtract": bson.A{"$count", 1}}, 0}}}},
+		"waitlisted": bson.M{"$cond": bson.A{waiting, bson.M{"$subtract": bson.A{"$waitlisted", 1}}, "$waitlisted"}},
+	}}}}
+
+	var before submissionCounter
+	err := s.Database.Collection("form_submission_counts").FindOneAndUpdate(ctx, bson.M{"_id": formID}, update).Decode(&before)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if before.Waitlisted == nil || *before.Waitlisted <= 0 {
+		return nil, nil
+	}
+	return s.promoteWaitlistHead(ctx, formID)
+}
+
+// promoteWaitlistHead promotes the first waitlisted response to the place it was handed
+func (s *Service) promoteWaitlistHead(ctx context.Context, formID primitive.ObjectID) (*models.FormResponse, error) {
+	update := bson.M{
+		"$set":   bson.M{"status": models.ResponseSubmitted, "promotedAt": time.Now()},
+		"$unset": bson.M{"waitlistPosition": ""},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "waitlistPosition", Value: 1}}).SetReturnDocument(options.After)
+
+	var promoted models.FormResponse
+	err := s.Database.Collection("responses").FindOneAndUpdate(ctx, waitlistedFilter(formID), update, opts).Decode(&promoted)
+	if err == mongo.ErrNoDocuments {
+		// The waitlist count was out of step with the responses, the place stays free and the count is corrected
+		waitlisted, err := s.CountResponses(ctx, waitlistedFilter(formID))
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.Database.Collection("form_submission_counts").UpdateOne(ctx,
+			bson.M{"_id": formID, "count": bson.M{"$gt": 0}},
+			bson.M{"$inc": bson.M{"count": -1}, "$set": bson.M{"waitlisted": waitlisted}},
+		)
+		return nil, err
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &promoted, nil
+}
+
+// leaveWaitlist takes a response that's no longer waitlisted off the form's waitlist count
+func (s *Service) leaveWaitlist(ctx context.Context, formID primitive.ObjectID) error {
+	_, err := s.Database.Collection("form_submission_counts").UpdateOne(ctx,
+		bson.M{"_id": formID, "waitlisted": bson.M{"$gt": 0}},
+		bson.M{"$inc": bson.M{"waitlisted": -1}},
+	)
+	return err
+}
+
+// PlaceHoldersFilter matches the form's responses that take up one of its MaxSubmissions
+func PlaceHoldersFilter(formID primitive.ObjectID) bson.M {
+	return bson.M{
+		"formID":    formID,
+		"isDeleted": bson.M{"$ne": true},
+		"status":    bson.M{"$nin": bson.A{models.ResponseWaitlisted, models.ResponseReleased}},
+	}
+}
+
+// ReleaseFormSubmission gives back a submission reserved by ReserveFormSubmission, when the response couldn't be saved or was withdrawn.
+// heldPlace is false for waitlisted responses, which never took up one of the form's places. A freed place goes to the
+// first waitlisted response, which is returned.
+func (s *Service) ReleaseFormSubmission(ctx context.Context, response models.FormResponse, heldPlace bool) (*models.FormResponse, error) {
+	var promoted *models.FormResponse
+	var err error
+	if heldPlace {
+		promoted, err = s.releasePlace(ctx, response.FormID)
+	} else {
+		err = s.leaveWaitlist(ctx, response.FormID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Database.Collection("form_submitters").DeleteOne(ctx, bson.M{"_id": formSubmitterID(response.FormID, response)})
+	return promoted, err
+}
+
+// ReleaseResponsePlace marks a rejected or declined response as released. A response that held a place gives it to the
+// first waitlisted response, or back to the form's counter when nobody is waiting, a waitlisted one leaves the waitlist.
+// Unlike ReleaseFormSubmission the submitter's claim is kept, so they can't submit again. It returns the response as
+// it was, nil when it was already released, and the response promoted to its place.
+func (s *Service) ReleaseResponsePlace(ctx context.Context, responseID primitive.ObjectID) (*models.FormResponse, *models.FormResponse, error) {
+	filter := bson.M{"_id": responseID, "status": bson.M{"$ne": models.ResponseReleased}}
+	update := bson.M{"$set": bson.M{"status": models.ResponseReleased}, "$unset": bson.M{"waitlistPosition": ""}}
+
+	var released models.FormResponse
+	err := s.Database.Collection("responses").FindOneAndUpdate(ctx, filter, update).Decode(&released)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil, nil
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if !released.HoldsPlace() {
+		if released.IsWaitlisted() {
+			err = s.leaveWaitlist(ctx, released.FormID)
+		}
+		return &released, nil, err
+	}
+
+	promoted, err := s.releasePlace(ctx, released.FormID)
+	return &released, promoted, err
+}
+
+// ReclaimResponsePlace gives a released response a place on the form again, when its decision is changed back from
+// rejected or declined. When the form is full it's waitlisted instead, or ErrFormFull is returned if the form
+// has no waitlist. It returns the response's new status and waitlist position.
+func (s *Service) ReclaimResponsePlace(ctx context.Context, form models.FormStructure, responseID primitive.ObjectID) (models.ResponseStatus, int, error) {
+	status, position := models.ResponseSubmitted, 0
+	err := s.incrementSubmissionCount(ctx, form)
+	if err == ErrFormFull && form.EnableWaitlist {
+		status = models.ResponseWaitlisted
+		position, err = s.nextWaitlistPosition(ctx, form.ID)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	set := bson.M{"status": status}
+	if position > 0 {
+		set["waitlistPosition"] = position
+	}
+
+	result, err := s.Database.Collection("responses").UpdateOne(ctx, bson.M{"_id": responseID, "status": models.ResponseReleased}, bson.M{"$set": set})
+	if err == nil && result.MatchedCount == 0 {
+		err = errors.New("response is not released")
+	}
+	if err != nil {
+		if status == models.ResponseSubmitted {
+			s.Database.Collection("form_submission_counts").UpdateOne(ctx, bson.M{"_id": form.ID, "count": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"count": -1}})
+		}
+		return "", 0, err
+	}
+
+	return status, position, nil
+}
+
 // DeleteResponse
 func (s *Service) DeleteResponse(ctx context.Context, responseID primitive.ObjectID) (*mongo.DeleteResult, error) {
 	filter := bson.M{"_id": responseID}
@@ -521,6 +1129,14 @@ func (s *Service) UpdatePipelineRun(ctx context.Context, pipelineRun models.Pipe
 	return s.Database.Collection("pipeline_runs").UpdateOne(ctx, filter, update)
 }
 
+// SetPipelineActionEmailTemplateVersion records the email template version a pipeline action sent,
+// and the versions of the layout and partials it was rendered with
+func (s *Service) SetPipelineActionEmailTemplateVersion(ctx context.Context, pipelineRunID primitive.ObjectID, actionID primitive.ObjectID, version int, components []models.EmailComponentRef) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": pipelineRunID, "actionStatuses.actionID": actionID}
+	update := bson.M{"$set": bson.M{"actionStatuses.$.emailTemplateVersion": version, "actionStatuses.$.emailComponents": components}}
+	return s.Database.Collection("pipeline_runs").UpdateOne(ctx, filter, update)
+}
+
 func (s *Service) ListPipelineRuns(ctx context.Context, filter bson.M, options *options.FindOptions) ([]models.PipelineRun, error) {
 	var pipelineRuns []models.PipelineRun
 
@@ -582,57 +1198,186 @@ func (s *Service) ListEmailTemplates(ctx context.Context, filter bson.M) ([]mode
 	return emailTemplates, nil
 }
 
-// CreateEmailTemplate creates a new email template
+// CreateEmailTemplate creates a new email template and stores it as version 1
 func (s *Service) CreateEmailTemplate(ctx context.Context, emailTemplate models.EmailTemplate) (*mongo.InsertOneResult, error) {
-	return s.Database.Collection("email_templates").InsertOne(ctx, emailTemplate)
-}
+	emailTemplate.Version = 1
+	result, err := s.Database.Collection("email_templates").InsertOne(ctx, emailTemplate)
+	if err != nil {
+		return nil, err
+	}
 
-// UpdateEmailTemplate updates an email template by its ID
-func (s *Service) UpdateEmailTemplate(ctx context.Context, emailTemplate models.EmailTemplate, emailTemplateID primitive.ObjectID) (*mongo.UpdateResult, error) {
-	update := bson.M{"$set": emailTemplate}
-	filter := bson.M{"_id": emailTemplateID}
-	return s.Database.Collection("email_templates").UpdateOne(ctx, filter, update)
-}
+	emailTemplate.ID = result.InsertedID.(primitive.ObjectID)
+	if err := s.insertEmailTemplateVersion(ctx, emailTemplate); err != nil {
+		return nil, err
+	}
 
-// DeleteEmailTemplate deletes an email template by its ID
-func (s *Service) DeleteEmailTemplate(ctx context.Context, emailTemplateID primitive.ObjectID) (*mongo.DeleteResult, error) {
-	return s.Database.Collection("email_templates").DeleteOne(ctx, bson.M{"_id": emailTemplateID})
+	return result, nil
 }
 
-// GetEmailTemplate retrieves an email template by its ID
-func (s *Service) GetEmailTemplate(ctx context.Context, emailTemplateID primitive.ObjectID) (*models.EmailTemplate, error) {
-	var emailTemplate models.EmailTemplate
+// UpdateEmailTemplate updates an email template by its ID, the previous versions are kept in email_template_versions
+func (s *Service) UpdateEmailTemplate(ctx context.Context, emailTemplate models.EmailTemplate, emailTemplateID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	if err := s.snapshotUnversionedEmailTemplate(ctx, emailTemplateID); err != nil {
+		return nil, err
+	}
 
-	err := s.Database.Collection("email_templates").FindOne(ctx, bson.M{"_id": emailTemplateID}).Decode(&emailTemplate)
+	raw, err := bson.Marshal(emailTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	return &emailTemplate, nil
-}
-
-// GetEventSecret retrieves secrets based on a filter
-func (s *Service) GetEventSecrets(ctx context.Context, filter bson.M, stripSecrets bool) (*models.EventSecrets, error) {
-	var data models.EventSecrets
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
 
-	err := s.Database.Collection("event_secrets").FindOne(ctx, filter).Decode(&data)
+	// The version is only ever incremented here so concurrent updates each get their own number
+	delete(fields, "_id")
+	delete(fields, "version")
+
+	var updated models.EmailTemplate
+	err = s.Database.Collection("email_templates").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": emailTemplateID},
+		bson.M{"$set": fields, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return &mongo.UpdateResult{}, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if stripSecrets {
-		if data.Email != nil {
-			stripped := data.Email.StripSecret()
-			if strippedEmail, ok := stripped.(*models.EmailSecret); ok {
-				data.Email = strippedEmail
-			}
-		}
+	if err := s.insertEmailTemplateVersion(ctx, updated); err != nil {
+		return nil, err
 	}
 
-	return &data, nil
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
 }
 
-// CreateOrUpdateEventSecrets creates a new event secret
+// snapshotUnversionedEmailTemplate saves a template created before versioning as version 1,
+// so the template as it was before its first update is kept
+func (s *Service) snapshotUnversionedEmailTemplate(ctx context.Context, emailTemplateID primitive.ObjectID) error {
+	count, err := s.Database.Collection("email_template_versions").CountDocuments(ctx, bson.M{"templateID": emailTemplateID}, options.Count().SetLimit(1))
+	if err != nil || count > 0 {
+		return err
+	}
+
+	// Only one concurrent update sees the template without a version
+	var current models.EmailTemplate
+	err = s.Database.Collection("email_templates").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": emailTemplateID, "version": bson.M{"$in": bson.A{0, nil}}},
+		bson.M{"$set": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.insertEmailTemplateVersion(ctx, current)
+}
+
+func (s *Service) insertEmailTemplateVersion(ctx context.Context, emailTemplate models.EmailTemplate) error {
+	version := models.EmailTemplateVersion{
+		TemplateID: emailTemplate.ID,
+		EventID:    emailTemplate.EventID,
+		Version:    emailTemplate.Version,
+		Template:   emailTemplate,
+		CreatedBy:  emailTemplate.UpdatedBy,
+		CreatedAt:  time.Now(),
+	}
+
+	_, err := s.Database.Collection("email_template_versions").InsertOne(ctx, version)
+	return err
+}
+
+// ListEmailTemplateVersions lists every saved version of a template, newest first
+func (s *Service) ListEmailTemplateVersions(ctx context.Context, templateID primitive.ObjectID) ([]models.EmailTemplateVersion, error) {
+	var versions []models.EmailTemplateVersion
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := s.Database.Collection("email_template_versions").Find(ctx, bson.M{"templateID": templateID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var version models.EmailTemplateVersion
+		if err := cursor.Decode(&version); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If versions is null then return an empty slice instead
+	if versions == nil {
+		return []models.EmailTemplateVersion{}, nil
+	}
+
+	return versions, nil
+}
+
+// GetEmailTemplateVersion retrieves one saved version of a template
+func (s *Service) GetEmailTemplateVersion(ctx context.Context, templateID primitive.ObjectID, version int) (*models.EmailTemplateVersion, error) {
+	var templateVersion models.EmailTemplateVersion
+
+	err := s.Database.Collection("email_template_versions").FindOne(ctx, bson.M{"templateID": templateID, "version": version}).Decode(&templateVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateVersion, nil
+}
+
+// DeleteEmailTemplate deletes an email template by its ID
+func (s *Service) DeleteEmailTemplate(ctx context.Context, emailTemplateID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("email_templates").DeleteOne(ctx, bson.M{"_id": emailTemplateID})
+}
+
+// GetEmailTemplate retrieves an email template by its ID
+func (s *Service) GetEmailTemplate(ctx context.Context, emailTemplateID primitive.ObjectID) (*models.EmailTemplate, error) {
+	var emailTemplate models.EmailTemplate
+
+	err := s.Database.Collection("email_templates").FindOne(ctx, bson.M{"_id": emailTemplateID}).Decode(&emailTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &emailTemplate, nil
+}
+
+// GetEventSecret retrieves secrets based on a filter
+func (s *Service) GetEventSecrets(ctx context.Context, filter bson.M, stripSecrets bool) (*models.EventSecrets, error) {
+	var data models.EventSecrets
+
+	err := s.Database.Collection("event_secrets").FindOne(ctx, filter).Decode(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	if stripSecrets {
+		if data.Email != nil {
+			stripped := data.Email.StripSecret()
+			if strippedEmail, ok := stripped.(*models.EmailSecret); ok {
+				data.Email = strippedEmail
+			}
+		}
+	}
+
+	return &data, nil
+}
+
+// CreateOrUpdateEventSecrets creates a new event secret
 func (s *Service) CreateOrUpdateEventSecrets(ctx context.Context, newSecret models.EventSecrets) (*mongo.UpdateResult, error) {
 	filter := bson.M{"eventID": newSecret.EventID}
 
@@ -684,3 +1429,1110 @@ func (s *Service) DeleteEventSecrets(ctx context.Context, eventID primitive.Obje
 	filter := bson.M{"eventID": eventID}
 	return s.Database.Collection("event_secrets").DeleteOne(ctx, filter)
 }
+
+// CountResponses counts the responses matching a filter
+func (s *Service) CountResponses(ctx context.Context, filter bson.M) (int64, error) {
+	return s.Database.Collection("responses").CountDocuments(ctx, filter)
+}
+
+// AggregateResponses runs an aggregation pipeline on the responses collection
+func (s *Service) AggregateResponses(ctx context.Context, pipeline mongo.Pipeline) ([]bson.M, error) {
+	cursor, err := s.Database.Collection("responses").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []bson.M{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListEmailCampaigns retrieves email campaigns based on a filter, newest first
+func (s *Service) ListEmailCampaigns(ctx context.Context, filter bson.M) ([]models.EmailCampaign, error) {
+	var campaigns []models.EmailCampaign
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := s.Database.Collection("email_campaigns").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var campaign models.EmailCampaign
+		if err := cursor.Decode(&campaign); err != nil {
+			return nil, err
+		}
+
+		campaigns = append(campaigns, campaign)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If campaigns is null then return an empty slice instead
+	if campaigns == nil {
+		return []models.EmailCampaign{}, nil
+	}
+
+	return campaigns, nil
+}
+
+// GetEmailCampaign retrieves an email campaign by its ID
+func (s *Service) GetEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*models.EmailCampaign, error) {
+	var campaign models.EmailCampaign
+	err := s.Database.Collection("email_campaigns").FindOne(ctx, bson.M{"_id": campaignID}).Decode(&campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// CreateEmailCampaign creates a new email campaign, it will be picked up by the event listener once ScheduledAt has passed
+func (s *Service) CreateEmailCampaign(ctx context.Context, campaign models.EmailCampaign) (*mongo.InsertOneResult, error) {
+	campaign.CreatedAt = time.Now()
+	campaign.Status = models.EmailCampaignScheduled
+	if campaign.ScheduledAt.IsZero() {
+		campaign.ScheduledAt = campaign.CreatedAt
+	}
+	campaign.SentCount = 0
+	campaign.FailedCount = 0
+	campaign.Failures = []models.EmailCampaignFailure{}
+	return s.Database.Collection("email_campaigns").InsertOne(ctx, campaign)
+}
+
+// CancelEmailCampaign cancels a campaign that has not finished yet.
+// A campaign that is currently sending stops before its next email.
+func (s *Service) CancelEmailCampaign(ctx context.Context, campaignID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filter := bson.M{
+		"_id":    campaignID,
+		"status": bson.M{"$in": []models.EmailCampaignStatus{models.EmailCampaignScheduled, models.EmailCampaignSending}},
+	}
+	update := bson.M{"$set": bson.M{"status": models.EmailCampaignCancelled, "completedAt": time.Now()}}
+	return s.Database.Collection("email_campaigns").UpdateOne(ctx, filter, update)
+}
+
+// EmailCampaignLeaseTimeout is how long a sending campaign can go without a heartbeat before another sender
+// can claim it, the event listener heartbeats with every email so this only passes when the sender died
+const EmailCampaignLeaseTimeout = 5 * time.Minute
+
+// ClaimScheduledEmailCampaign atomically moves the oldest due campaign to sending and returns it with a new claimID.
+// A sending campaign whose heartbeat is older than EmailCampaignLeaseTimeout is claimed again to resume it.
+// Returns mongo.ErrNoDocuments if there is nothing to send.
+func (s *Service) ClaimScheduledEmailCampaign(ctx context.Context) (*models.EmailCampaign, error) {
+	now := time.Now()
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.EmailCampaignScheduled, "scheduledAt": bson.M{"$lte": now}},
+		// Campaigns claimed before heartbeats were recorded have none and are always stale
+		{"status": models.EmailCampaignSending, "heartbeatAt": bson.M{"$not": bson.M{"$gte": now.Add(-EmailCampaignLeaseTimeout)}}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": models.EmailCampaignSending, "claimID": primitive.NewObjectID(), "heartbeatAt": now},
+		// Keeps the first start when a stale campaign is resumed
+		"$min": bson.M{"startedAt": now},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "scheduledAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var campaign models.EmailCampaign
+	err := s.Database.Collection("email_campaigns").FindOneAndUpdate(ctx, filter, update, opts).Decode(&campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// StartEmailCampaignDelivery records how many recipients a sending campaign resolved to and the template version,
+// layout and partials it sends. Matches nothing once another sender has claimed the campaign.
+func (s *Service) StartEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, totalRecipients int, templateVersion int, templateComponents []models.EmailComponentRef) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": campaignID, "claimID": claimID}
+	update := bson.M{"$set": bson.M{
+		"totalRecipients":    totalRecipients,
+		"templateVersion":    templateVersion,
+		"templateComponents": templateComponents,
+		"heartbeatAt":        time.Now(),
+	}}
+	return s.Database.Collection("email_campaigns").UpdateOne(ctx, filter, update)
+}
+
+// RecordEmailCampaignDelivery records one delivery attempt to email, failure is nil when the email was sent.
+// It also renews the campaign's heartbeat. Returns the updated campaign so the sender can notice a cancellation,
+// or mongo.ErrNoDocuments once another sender has claimed the campaign.
+func (s *Service) RecordEmailCampaignDelivery(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, email string, failure *models.EmailCampaignFailure) (*models.EmailCampaign, error) {
+	update := bson.M{
+		"$inc":      bson.M{"sentCount": 1},
+		"$set":      bson.M{"heartbeatAt": time.Now()},
+		"$addToSet": bson.M{"attempted": normalizeEmail(email)},
+	}
+	if failure != nil {
+		update["$inc"] = bson.M{"failedCount": 1}
+		update["$push"] = bson.M{"failures": failure}
+	}
+	// The attempted list grows with every email, the sender doesn't need it back
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(bson.M{"attempted": 0})
+
+	var campaign models.EmailCampaign
+	filter := bson.M{"_id": campaignID, "claimID": claimID}
+	err := s.Database.Collection("email_campaigns").FindOneAndUpdate(ctx, filter, update, opts).Decode(&campaign)
+	if err != nil {
+		return nil, err
+	}
+	return &campaign, nil
+}
+
+// FinishEmailCampaign moves a sending campaign to its final status, a cancelled campaign or one another sender
+// has claimed is left as is
+func (s *Service) FinishEmailCampaign(ctx context.Context, campaignID primitive.ObjectID, claimID primitive.ObjectID, status models.EmailCampaignStatus, errorMsg string) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": campaignID, "claimID": claimID, "status": models.EmailCampaignSending}
+	set := bson.M{"status": status, "completedAt": time.Now()}
+	if errorMsg != "" {
+		set["errorMsg"] = errorMsg
+	}
+	return s.Database.Collection("email_campaigns").UpdateOne(ctx, filter, bson.M{"$set": set})
+}
+
+// normalizeEmail is used so suppressions match regardless of case or surrounding whitespace
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// ListEmailSuppressions retrieves email suppressions based on a filter
+func (s *Service) ListEmailSuppressions(ctx context.Context, filter bson.M) ([]models.EmailSuppression, error) {
+	var suppressions []models.EmailSuppression
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := s.Database.Collection("email_suppressions").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var suppression models.EmailSuppression
+		if err := cursor.Decode(&suppression); err != nil {
+			return nil, err
+		}
+
+		suppressions = append(suppressions, suppression)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If suppressions is null then return an empty slice instead
+	if suppressions == nil {
+		return []models.EmailSuppression{}, nil
+	}
+
+	return suppressions, nil
+}
+
+// GetEmailSuppression retrieves an email suppression by its ID
+func (s *Service) GetEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*models.EmailSuppression, error) {
+	var suppression models.EmailSuppression
+	err := s.Database.Collection("email_suppressions").FindOne(ctx, bson.M{"_id": suppressionID}).Decode(&suppression)
+	if err != nil {
+		return nil, err
+	}
+	return &suppression, nil
+}
+
+// CreateEmailSuppression adds an email to a suppression list, suppressing an address twice keeps the original entry
+func (s *Service) CreateEmailSuppression(ctx context.Context, suppression models.EmailSuppression) (*mongo.UpdateResult, error) {
+	suppression.Email = normalizeEmail(suppression.Email)
+	suppression.CreatedAt = time.Now()
+
+	filter := bson.M{"email": suppression.Email, "eventID": suppression.EventID}
+	update := bson.M{"$setOnInsert": suppression}
+	opts := options.Update().SetUpsert(true)
+	return s.Database.Collection("email_suppressions").UpdateOne(ctx, filter, update, opts)
+}
+
+// DeleteEmailSuppression removes an email suppression by its ID
+func (s *Service) DeleteEmailSuppression(ctx context.Context, suppressionID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("email_suppressions").DeleteOne(ctx, bson.M{"_id": suppressionID})
+}
+
+// IsEmailSuppressed checks the global suppression list, and the event's list if includeEventLevel is set.
+// Unsubscribing only stops marketing emails, so without includeEventLevel global unsubscribes are ignored.
+func (s *Service) IsEmailSuppressed(ctx context.Context, email string, eventID primitive.ObjectID, includeEventLevel bool) (bool, error) {
+	filter := bson.M{"email": normalizeEmail(email), "eventID": primitive.NilObjectID, "reason": bson.M{"$ne": models.EmailSuppressionUnsubscribed}}
+	if includeEventLevel {
+		filter = bson.M{"email": normalizeEmail(email), "eventID": bson.M{"$in": []primitive.ObjectID{primitive.NilObjectID, eventID}}}
+	}
+
+	count, err := s.Database.Collection("email_suppressions").CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ListEmailComponents retrieves email layouts and partials based on a filter
+func (s *Service) ListEmailComponents(ctx context.Context, filter bson.M) ([]models.EmailComponent, error) {
+	var components []models.EmailComponent
+
+	cursor, err := s.Database.Collection("email_components").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var component models.EmailComponent
+		if err := cursor.Decode(&component); err != nil {
+			return nil, err
+		}
+
+		components = append(components, component)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If components is null then return an empty slice instead
+	if components == nil {
+		return []models.EmailComponent{}, nil
+	}
+
+	return components, nil
+}
+
+// GetEmailComponent retrieves an email layout or partial by its ID
+func (s *Service) GetEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*models.EmailComponent, error) {
+	var component models.EmailComponent
+
+	err := s.Database.Collection("email_components").FindOne(ctx, bson.M{"_id": componentID}).Decode(&component)
+	if err != nil {
+		return nil, err
+	}
+
+	return &component, nil
+}
+
+// CreateEmailComponent creates a new email layout or partial and stores it as version 1
+func (s *Service) CreateEmailComponent(ctx context.Context, component models.EmailComponent) (*mongo.InsertOneResult, error) {
+	component.Version = 1
+	result, err := s.Database.Collection("email_components").InsertOne(ctx, component)
+	if err != nil {
+		return nil, err
+	}
+
+	component.ID = result.InsertedID.(primitive.ObjectID)
+	if err := s.insertEmailComponentVersion(ctx, component); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UpdateEmailComponent updates an email layout or partial by its ID, the previous versions are kept in email_component_versions
+func (s *Service) UpdateEmailComponent(ctx context.Context, component models.EmailComponent, componentID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	if err := s.snapshotUnversionedEmailComponent(ctx, componentID); err != nil {
+		return nil, err
+	}
+
+	raw, err := bson.Marshal(component)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+
+	// The version is only ever incremented here so concurrent updates each get their own number
+	delete(fields, "_id")
+	delete(fields, "version")
+
+	var updated models.EmailComponent
+	err = s.Database.Collection("email_components").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": componentID},
+		bson.M{"$set": fields, "$inc": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return &mongo.UpdateResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.insertEmailComponentVersion(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+}
+
+// snapshotUnversionedEmailComponent saves a component created before versioning as version 1,
+// so the component as it was before its first update is kept
+func (s *Service) snapshotUnversionedEmailComponent(ctx context.Context, componentID primitive.ObjectID) error {
+	count, err := s.Database.Collection("email_component_versions").CountDocuments(ctx, bson.M{"componentID": componentID}, options.Count().SetLimit(1))
+	if err != nil || count > 0 {
+		return err
+	}
+
+	// Only one concurrent update sees the component without a version
+	var current models.EmailComponent
+	err = s.Database.Collection("email_components").FindOneAndUpdate(
+		ctx,
+		bson.M{"_id": componentID, "version": bson.M{"$in": bson.A{0, nil}}},
+		bson.M{"$set": bson.M{"version": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&current)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return s.insertEmailComponentVersion(ctx, current)
+}
+
+func (s *Service) insertEmailComponentVersion(ctx context.Context, component models.EmailComponent) error {
+	version := models.EmailComponentVersion{
+		ComponentID: component.ID,
+		EventID:     component.EventID,
+		Version:     component.Version,
+		Component:   component,
+		CreatedBy:   component.UpdatedBy,
+		CreatedAt:   time.Now(),
+	}
+
+	_, err := s.Database.Collection("email_component_versions").InsertOne(ctx, version)
+	return err
+}
+
+// ListEmailComponentVersions lists every saved version of a layout or partial, newest first
+func (s *Service) ListEmailComponentVersions(ctx context.Context, componentID primitive.ObjectID) ([]models.EmailComponentVersion, error) {
+	var versions []models.EmailComponentVersion
+
+	opts := options.Find().SetSort(bson.D{{Key: "version", Value: -1}})
+	cursor, err := s.Database.Collection("email_component_versions").Find(ctx, bson.M{"componentID": componentID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var version models.EmailComponentVersion
+		if err := cursor.Decode(&version); err != nil {
+			return nil, err
+		}
+
+		versions = append(versions, version)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If versions is null then return an empty slice instead
+	if versions == nil {
+		return []models.EmailComponentVersion{}, nil
+	}
+
+	return versions, nil
+}
+
+// GetEmailComponentVersion retrieves one saved version of a layout or partial
+func (s *Service) GetEmailComponentVersion(ctx context.Context, componentID primitive.ObjectID, version int) (*models.EmailComponentVersion, error) {
+	var componentVersion models.EmailComponentVersion
+
+	err := s.Database.Collection("email_component_versions").FindOne(ctx, bson.M{"componentID": componentID, "version": version}).Decode(&componentVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	return &componentVersion, nil
+}
+
+// DeleteEmailComponent deletes an email layout or partial by its ID
+func (s *Service) DeleteEmailComponent(ctx context.Context, componentID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("email_components").DeleteOne(ctx, bson.M{"_id": componentID})
+}
+
+// IsEventOrganizer checks if the user organizes the event, for callers without a request context to authenticate with
+func (s *Service) IsEventOrganizer(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (bool, error) {
+	count, err := s.Database.Collection("events").CountDocuments(ctx, bson.M{"_id": eventID, "organizerIDs": userID})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// GetDigestSubscription retrieves an organizer's digest settings for an event
+func (s *Service) GetDigestSubscription(ctx context.Context, eventID primitive.ObjectID, userID primitive.ObjectID) (*models.DigestSubscription, error) {
+	var subscription models.DigestSubscription
+
+	err := s.Database.Collection("digest_subscriptions").FindOne(ctx, bson.M{"eventID": eventID, "userID": userID}).Decode(&subscription)
+	if err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// UpsertDigestSubscription saves an organizer's digest settings for an event, the first digest is scheduled one interval from now
+func (s *Service) UpsertDigestSubscription(ctx context.Context, subscription models.DigestSubscription) (*mongo.UpdateResult, error) {
+	now := time.Now()
+	filter := bson.M{"eventID": subscription.EventID, "userID": subscription.UserID}
+	update := bson.M{
+		"$set": bson.M{
+			"frequency":  subscription.Frequency,
+			"nextSendAt": now.Add(subscription.Frequency.Interval()),
+			"updatedAt":  now,
+		},
+		"$setOnInsert": bson.M{
+			"eventID":    subscription.EventID,
+			"userID":     subscription.UserID,
+			"lastSentAt": now,
+		},
+	}
+
+	return s.Database.Collection("digest_subscriptions").UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+}
+
+// ListDueDigestSubscriptions lists the subscriptions whose next digest should have been sent by now
+func (s *Service) ListDueDigestSubscriptions(ctx context.Context, now time.Time) ([]models.DigestSubscription, error) {
+	var subscriptions []models.DigestSubscription
+
+	filter := bson.M{
+		"frequency":  bson.M{"$in": []models.DigestFrequency{models.DigestHourly, models.DigestDaily}},
+		"nextSendAt": bson.M{"$lte": now},
+	}
+	cursor, err := s.Database.Collection("digest_subscriptions").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var subscription models.DigestSubscription
+		if err := cursor.Decode(&subscription); err != nil {
+			return nil, err
+		}
+
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If subscriptions is null then return an empty slice instead
+	if subscriptions == nil {
+		return []models.DigestSubscription{}, nil
+	}
+
+	return subscriptions, nil
+}
+
+// ClaimDigestSubscription moves a due subscription on to its next send time.
+// Returns false if the subscription was changed or claimed by someone else since it was listed.
+func (s *Service) ClaimDigestSubscription(ctx context.Context, subscription models.DigestSubscription, sentAt time.Time) (bool, error) {
+	filter := bson.M{"_id": subscription.ID, "nextSendAt": subscription.NextSendAt, "frequency": subscription.Frequency}
+	update := bson.M{"$set": bson.M{
+		"lastSentAt": sentAt,
+		"nextSendAt": sentAt.Add(subscription.Frequency.Interval()),
+	}}
+
+	result, err := s.Database.Collection("digest_subscriptions").UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// CreateFileUpload records a file uploaded to storage, it's orphaned until attached to a response
+func (s *Service) CreateFileUpload(ctx context.Context, upload models.FileUpload) (*mongo.InsertOneResult, error) {
+	upload.ResponseID = primitive.NilObjectID
+	upload.CreatedAt = time.Now()
+	return s.Database.Collection("file_uploads").InsertOne(ctx, upload)
+}
+
+// GetFileUpload retrieves a file upload by its ID
+func (s *Service) GetFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*models.FileUpload, error) {
+	var upload models.FileUpload
+
+	err := s.Database.Collection("file_uploads").FindOne(ctx, bson.M{"_id": uploadID}).Decode(&upload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// AttachFileUploads attaches the uploads to a response and detaches any the response no longer references,
+// detached uploads are picked up by the orphan cleanup
+func (s *Service) AttachFileUploads(ctx context.Context, responseID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error) {
+	if uploadIDs == nil {
+		uploadIDs = []primitive.ObjectID{}
+	}
+
+	collection := s.Database.Collection("file_uploads")
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"responseID": responseID, "_id": bson.M{"$nin": uploadIDs}},
+		bson.M{"$set": bson.M{"responseID": primitive.NilObjectID}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Once submitted the upload belongs to the response rather than the draft it came from
+	return collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": uploadIDs}},
+		bson.M{"$set": bson.M{"responseID": responseID}, "$unset": bson.M{"draftID": ""}},
+	)
+}
+
+// AttachDraftFileUploads keeps the uploads referenced by a draft from being cleaned up,
+// uploads the draft no longer references are released
+func (s *Service) AttachDraftFileUploads(ctx context.Context, draftID primitive.ObjectID, uploadIDs []primitive.ObjectID) (*mongo.UpdateResult, error) {
+	if uploadIDs == nil {
+		uploadIDs = []primitive.ObjectID{}
+	}
+
+	collection := s.Database.Collection("file_uploads")
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"draftID": draftID, "_id": bson.M{"$nin": uploadIDs}},
+		bson.M{"$unset": bson.M{"draftID": ""}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return collection.UpdateMany(ctx,
+		bson.M{"_id": bson.M{"$in": uploadIDs}, "responseID": primitive.NilObjectID},
+		bson.M{"$set": bson.M{"draftID": draftID}},
+	)
+}
+
+// ListOrphanedFileUploads lists uploads created before the cutoff that aren't attached to a response or draft
+func (s *Service) ListOrphanedFileUploads(ctx context.Context, createdBefore time.Time) ([]models.FileUpload, error) {
+	var uploads []models.FileUpload
+
+	filter := bson.M{"responseID": primitive.NilObjectID, "draftID": nil, "createdAt": bson.M{"$lt": createdBefore}}
+	cursor, err := s.Database.Collection("file_uploads").Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var upload models.FileUpload
+		if err := cursor.Decode(&upload); err != nil {
+			return nil, err
+		}
+
+		uploads = append(uploads, upload)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If uploads is null then return an empty slice instead
+	if uploads == nil {
+		return []models.FileUpload{}, nil
+	}
+
+	return uploads, nil
+}
+
+// DeleteFileUpload deletes a file upload record, the stored file must be deleted separately
+func (s *Service) DeleteFileUpload(ctx context.Context, uploadID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("file_uploads").DeleteOne(ctx, bson.M{"_id": uploadID})
+}
+
+// GetResponseDraft retrieves a user's draft response to a form
+func (s *Service) GetResponseDraft(ctx context.Context, formID primitive.ObjectID, userID primitive.ObjectID) (*models.ResponseDraft, error) {
+	var draft models.ResponseDraft
+
+	err := s.Database.Collection("response_drafts").FindOne(ctx, bson.M{"formID": formID, "userID": userID}).Decode(&draft)
+	if err != nil {
+		return nil, err
+	}
+
+	return &draft, nil
+}
+
+// SaveResponseDraft creates or replaces the data of a user's draft response to a form
+func (s *Service) SaveResponseDraft(ctx context.Context, draft models.ResponseDraft) (*models.ResponseDraft, error) {
+	now := time.Now()
+	filter := bson.M{"formID": draft.FormID, "userID": draft.UserID}
+	update := bson.M{
+		"$set":         bson.M{"data": draft.Data, "updatedAt": now},
+		"$setOnInsert": bson.M{"createdAt": now},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved models.ResponseDraft
+	if err := s.Database.Collection("response_drafts").FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// DeleteResponseDraft deletes a draft response by its ID
+func (s *Service) DeleteResponseDraft(ctx context.Context, draftID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("response_drafts").DeleteOne(ctx, bson.M{"_id": draftID})
+}
+
+// CreateFormInvite creates an invite link for a restricted form
+func (s *Service) CreateFormInvite(ctx context.Context, invite models.FormInvite) (*mongo.InsertOneResult, error) {
+	invite.Uses = 0
+	invite.RevokedAt = time.Time{}
+	invite.CreatedAt = time.Now()
+	return s.Database.Collection("form_invites").InsertOne(ctx, invite)
+}
+
+// GetFormInvite retrieves a form invite by its ID
+func (s *Service) GetFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*models.FormInvite, error) {
+	var invite models.FormInvite
+
+	err := s.Database.Collection("form_invites").FindOne(ctx, bson.M{"_id": inviteID}).Decode(&invite)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invite, nil
+}
+
+// ListFormInvites lists a form's invites, newest first
+func (s *Service) ListFormInvites(ctx context.Context, formID primitive.ObjectID) ([]models.FormInvite, error) {
+	var invites []models.FormInvite
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := s.Database.Collection("form_invites").Find(ctx, bson.M{"formID": formID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var invite models.FormInvite
+		if err := cursor.Decode(&invite); err != nil {
+			return nil, err
+		}
+
+		invites = append(invites, invite)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If invites is null then return an empty slice instead
+	if invites == nil {
+		return []models.FormInvite{}, nil
+	}
+
+	return invites, nil
+}
+
+// RevokeFormInvite stops an invite from granting access, responses already submitted with it are kept
+func (s *Service) RevokeFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": inviteID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": time.Now()}}
+	return s.Database.Collection("form_invites").UpdateOne(ctx, filter, update)
+}
+
+// UseFormInvite atomically counts a use of the invite, it returns false if the invite was revoked, expired or used up
+func (s *Service) UseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"_id":       inviteID,
+		"revokedAt": bson.M{"$exists": false},
+		"$and": []bson.M{
+			{"$or": []bson.M{{"expiresAt": bson.M{"$exists": false}}, {"expiresAt": bson.M{"$gt": time.Now()}}}},
+			{"$or": []bson.M{{"maxUses": 0}, {"$expr": bson.M{"$lt": []string{"$uses", "$maxUses"}}}}},
+		},
+	}
+
+	result, err := s.Database.Collection("form_invites").UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"uses": 1}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// ReleaseFormInvite gives back a use of the invite when the response it was used for couldn't be saved
+func (s *Service) ReleaseFormInvite(ctx context.Context, inviteID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	filter := bson.M{"_id": inviteID, "uses": bson.M{"$gt": 0}}
+	return s.Database.Collection("form_invites").UpdateOne(ctx, filter, bson.M{"$inc": bson.M{"uses": -1}})
+}
+
+// SavePendingResponse creates or replaces the anonymous response waiting on the email's confirmation for the form.
+// Replacing it restarts the expiry and asks for another confirmation email, which is sent once the cooldown since the
+// last one has passed. The link in an earlier email confirms the new data.
+func (s *Service) SavePendingResponse(ctx context.Context, pending models.PendingResponse) (*models.PendingResponse, error) {
+	now := time.Now()
+	filter := bson.M{"formID": pending.FormID, "email": strings.ToLower(pending.Email)}
+	update := bson.M{
+		"$set":         bson.M{"data": pending.Data, "expiresAt": now.Add(models.PendingResponseTTL), "resendRequested": true},
+		"$setOnInsert": bson.M{"createdAt": now, "requestIP": pending.RequestIP},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var saved models.PendingResponse
+	if err := s.Database.Collection("pending_responses").FindOneAndUpdate(ctx, filter, update, opts).Decode(&saved); err != nil {
+		return nil, err
+	}
+
+	return &saved, nil
+}
+
+// CountPendingResponses counts the unexpired pending responses matching the filter
+func (s *Service) CountPendingResponses(ctx context.Context, filter bson.M) (int64, error) {
+	filter["expiresAt"] = bson.M{"$gt": time.Now()}
+	return s.Database.Collection("pending_responses").CountDocuments(ctx, filter)
+}
+
+// ClaimPendingResponseConfirmation marks the oldest unexpired pending response waiting on a confirmation email as sent
+// and returns it, mongo.ErrNoDocuments is returned when there's nothing to send. A pending response waits on an email
+// when it hasn't had one yet, or when it was submitted again after the cooldown, up to MaxPendingResponseConfirmations.
+func (s *Service) ClaimPendingResponseConfirmation(ctx context.Context) (*models.PendingResponse, error) {
+	now := time.Now()
+	filter := bson.M{
+		"expiresAt":         bson.M{"$gt": now},
+		"confirmationsSent": bson.M{"$not": bson.M{"$gte": models.MaxPendingResponseConfirmations}},
+		"$or": []bson.M{
+			{"confirmationSentAt": bson.M{"$exists": false}},
+			{"resendRequested": true, "confirmationSentAt": bson.M{"$lte": now.Add(-models.PendingResponseResendCooldown)}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{"confirmationSentAt": now, "resendRequested": false},
+		"$inc": bson.M{"confirmationsSent": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var pending models.PendingResponse
+	if err := s.Database.Collection("pending_responses").FindOneAndUpdate(ctx, filter, update, opts).Decode(&pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// ConfirmPendingResponse claims an unexpired pending response so only one confirmation saves it,
+// mongo.ErrNoDocuments is returned when it doesn't exist, expired or is already being confirmed
+func (s *Service) ConfirmPendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*models.PendingResponse, error) {
+	now := time.Now()
+	filter := bson.M{"_id": pendingID, "confirmedAt": bson.M{"$exists": false}, "expiresAt": bson.M{"$gt": now}}
+	update := bson.M{"$set": bson.M{"confirmedAt": now}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var pending models.PendingResponse
+	if err := s.Database.Collection("pending_responses").FindOneAndUpdate(ctx, filter, update, opts).Decode(&pending); err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+// ReleasePendingResponse lets a pending response be confirmed again after saving it failed
+func (s *Service) ReleasePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return s.Database.Collection("pending_responses").UpdateByID(ctx, pendingID, bson.M{"$unset": bson.M{"confirmedAt": ""}})
+}
+
+// DeletePendingResponse deletes a pending response once it has been saved as a response
+func (s *Service) DeletePendingResponse(ctx context.Context, pendingID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("pending_responses").DeleteOne(ctx, bson.M{"_id": pendingID})
+}
+
+// DeleteExpiredPendingResponses deletes the pending responses that were never confirmed
+func (s *Service) DeleteExpiredPendingResponses(ctx context.Context) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("pending_responses").DeleteMany(ctx, bson.M{"expiresAt": bson.M{"$lte": time.Now()}})
+}
+
+// UnlinkedAnonymousFilter matches the anonymous responses confirmed with the email that aren't linked to an account yet
+func UnlinkedAnonymousFilter(email string) bson.M {
+	return bson.M{"anonymousEmail": strings.ToLower(email), "userID": primitive.NilObjectID}
+}
+
+// LinkAnonymousResponses gives the user the anonymous responses confirmed with their email that aren't linked to an account yet.
+// Only call it once the user has shown they own the email, registering with an address doesn't prove it.
+func (s *Service) LinkAnonymousResponses(ctx context.Context, email string, userID primitive.ObjectID) (*mongo.UpdateResult, error) {
+	return s.Database.Collection("responses").UpdateMany(ctx, UnlinkedAnonymousFilter(email), bson.M{"$set": bson.M{"userID": userID}})
+}
+
+// ClaimAnonymousLinkRequest clears a user's request for the link confirming their anonymous responses and returns the user,
+// mongo.ErrNoDocuments is returned when there's nothing to send
+func (s *Service) ClaimAnonymousLinkRequest(ctx context.Context) (*models.User, error) {
+	filter := bson.M{"anonymousLinkRequested": true}
+	update := bson.M{"$unset": bson.M{"anonymousLinkRequested": ""}}
+
+	var user models.User
+	if err := s.Database.Collection("users").FindOneAndUpdate(ctx, filter, update).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetReviewRubric retrieves the form's review rubric, mongo.ErrNoDocuments is returned when the form doesn't have one
+func (s *Service) GetReviewRubric(ctx context.Context, formID primitive.ObjectID) (*models.ReviewRubric, error) {
+	var rubric models.ReviewRubric
+	if err := s.Database.Collection("review_rubrics").FindOne(ctx, bson.M{"formID": formID}).Decode(&rubric); err != nil {
+		return nil, err
+	}
+	return &rubric, nil
+}
+
+// SaveReviewRubric creates or replaces the form's review rubric
+func (s *Service) SaveReviewRubric(ctx context.Context, rubric models.ReviewRubric) (*mongo.UpdateResult, error) {
+	rubric.ID = primitive.NilObjectID
+	rubric.UpdatedAt = time.Now()
+	opts := options.Update().SetUpsert(true)
+	return s.Database.Collection("review_rubrics").UpdateOne(ctx, bson.M{"formID": rubric.FormID}, bson.M{"$set": rubric}, opts)
+}
+
+// CreateReviews assigns responses to reviewers, a reviewer already assigned to a response is skipped
+func (s *Service) CreateReviews(ctx context.Context, reviews []models.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(reviews))
+	for _, review := range reviews {
+		review.ID = primitive.NilObjectID
+		review.AssignedAt = now
+		filter := bson.M{"responseID": review.ResponseID, "reviewerID": review.ReviewerID}
+		writes = append(writes, mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$setOnInsert": review}).SetUpsert(true))
+	}
+
+	_, err := s.Database.Collection("reviews").BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// ListReviews lists the reviews matching the filter, oldest assignment first
+func (s *Service) ListReviews(ctx context.Context, filter bson.M) ([]models.Review, error) {
+	var reviews []models.Review
+
+	opts := options.Find().SetSort(bson.D{{Key: "assignedAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.Database.Collection("reviews").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var review models.Review
+		if err := cursor.Decode(&review); err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, review)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If reviews is null then return an empty slice instead
+	if reviews == nil {
+		return []models.Review{}, nil
+	}
+
+	return reviews, nil
+}
+
+// GetReview retrieves the reviewer's review of a response, mongo.ErrNoDocuments is returned when they weren't assigned it
+func (s *Service) GetReview(ctx context.Context, responseID primitive.ObjectID, reviewerID primitive.ObjectID) (*models.Review, error) {
+	var review models.Review
+	filter := bson.M{"responseID": responseID, "reviewerID": reviewerID}
+	if err := s.Database.Collection("reviews").FindOne(ctx, filter).Decode(&review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// SubmitReview saves a review's scores and comment, submitting it again replaces them
+func (s *Service) SubmitReview(ctx context.Context, review models.Review) (*mongo.UpdateResult, error) {
+	update := bson.M{"$set": bson.M{
+		"scores":      review.Scores,
+		"comment":     review.Comment,
+		"score":       review.Score,
+		"submittedAt": review.SubmittedAt,
+	}}
+	return s.Database.Collection("reviews").UpdateByID(ctx, review.ID, update)
+}
+
+// DeleteReview removes a review assignment
+func (s *Service) DeleteReview(ctx context.Context, reviewID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("reviews").DeleteOne(ctx, bson.M{"_id": reviewID})
+}
+
+// SummarizeResponseReviews averages the response's submitted reviews and saves the summary on the response,
+// the summary is removed when no reviews are left. The new summary is returned, nil without reviews.
+func (s *Service) SummarizeResponseReviews(ctx context.Context, responseID primitive.ObjectID) (*models.ResponseReviewSummary, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"responseID": responseID, "submittedAt": bson.M{"$exists": true}}}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "score": bson.M{"$avg": "$score"}, "count": bson.M{"$sum": 1}}}},
+	}
+
+	cursor, err := s.Database.Collection("reviews").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.ResponseReviewSummary
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+
+	if len(results) == 0 {
+		_, err := s.Database.Collection("responses").UpdateByID(ctx, responseID, bson.M{"$unset": bson.M{"review": ""}})
+		return nil, err
+	}
+
+	summary := results[0]
+	summary.UpdatedAt = time.Now()
+	if _, err := s.Database.Collection("responses").UpdateByID(ctx, responseID, bson.M{"$set": bson.M{"review": summary}}); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// RecordResponseChange adds a change to a response's history
+func (s *Service) RecordResponseChange(ctx context.Context, change models.ResponseChange) (*mongo.InsertOneResult, error) {
+	change.ID = primitive.NilObjectID
+	change.ChangedAt = time.Now()
+	return s.Database.Collection("response_history").InsertOne(ctx, change)
+}
+
+// ListResponseChanges lists a response's history, newest first
+func (s *Service) ListResponseChanges(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseChange, error) {
+	var changes []models.ResponseChange
+
+	opts := options.Find().SetSort(bson.D{{Key: "changedAt", Value: -1}, {Key: "_id", Value: -1}})
+	cursor, err := s.Database.Collection("response_history").Find(ctx, bson.M{"responseID": responseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var change models.ResponseChange
+		if err := cursor.Decode(&change); err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If changes is null then return an empty slice instead
+	if changes == nil {
+		return []models.ResponseChange{}, nil
+	}
+
+	return changes, nil
+}
+
+// GetResponseChange retrieves a change from a response's history by its ID
+func (s *Service) GetResponseChange(ctx context.Context, changeID primitive.ObjectID) (*models.ResponseChange, error) {
+	var change models.ResponseChange
+	if err := s.Database.Collection("response_history").FindOne(ctx, bson.M{"_id": changeID}).Decode(&change); err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListResponseTags lists the tags in use on a form's responses in order
+func (s *Service) ListResponseTags(ctx context.Context, formID primitive.ObjectID) ([]string, error) {
+	values, err := s.Database.Collection("responses").Distinct(ctx, "tags", bson.M{"formID": formID, "isDeleted": bson.M{"$ne": true}})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(values))
+	for _, value := range values {
+		if tag, ok := value.(string); ok {
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+// CreateResponseNote adds a note to a response
+func (s *Service) CreateResponseNote(ctx context.Context, note models.ResponseNote) (*mongo.InsertOneResult, error) {
+	note.CreatedAt = time.Now()
+	note.UpdatedAt = note.CreatedAt
+	return s.Database.Collection("response_notes").InsertOne(ctx, note)
+}
+
+// ListResponseNotes lists a response's notes, oldest first
+func (s *Service) ListResponseNotes(ctx context.Context, responseID primitive.ObjectID) ([]models.ResponseNote, error) {
+	var notes []models.ResponseNote
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := s.Database.Collection("response_notes").Find(ctx, bson.M{"responseID": responseID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var note models.ResponseNote
+		if err := cursor.Decode(&note); err != nil {
+			return nil, err
+		}
+
+		notes = append(notes, note)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	// If notes is null then return an empty slice instead
+	if notes == nil {
+		return []models.ResponseNote{}, nil
+	}
+
+	return notes, nil
+}
+
+// GetResponseNote retrieves a note by its ID
+func (s *Service) GetResponseNote(ctx context.Context, noteID primitive.ObjectID) (*models.ResponseNote, error) {
+	var note models.ResponseNote
+	if err := s.Database.Collection("response_notes").FindOne(ctx, bson.M{"_id": noteID}).Decode(&note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// UpdateResponseNote changes the text of a note
+func (s *Service) UpdateResponseNote(ctx context.Context, noteID primitive.ObjectID, body string) (*mongo.UpdateResult, error) {
+	update := bson.M{"$set": bson.M{"body": body, "updatedAt": time.Now()}}
+	return s.Database.Collection("response_notes").UpdateOne(ctx, bson.M{"_id": noteID}, update)
+}
+
+// DeleteResponseNote deletes a note
+func (s *Service) DeleteResponseNote(ctx context.Context, noteID primitive.ObjectID) (*mongo.DeleteResult, error) {
+	return s.Database.Collection("response_notes").DeleteOne(ctx, bson.M{"_id": noteID})
+}
@@ -0,0 +1,406 @@
+package mongodb
+
+import (
+	"context"
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// The tests here run against the driver's mock deployment, each command gets the next mock response in order
+
+var (
+	matchedOne  = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1})
+	matchedNone = mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0})
+)
+
+// found is the response to a FindOne, or a CountDocuments when the document is {n: count}
+func found(ns string, docs ...bson.D) bson.D {
+	return mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, docs...)
+}
+
+// findAndModified is the response to a FindOneAndUpdate, value is nil when nothing matched
+func findAndModified(value interface{}) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: value})
+}
+
+// commands lists the commands sent to a collection, eg: "update form_submission_counts"
+func commands(mt *mtest.T) []string {
+	var names []string
+	for _, started := range mt.GetAllStartedEvents() {
+		names = append(names, started.CommandName+" "+commandCollection(started))
+	}
+	return names
+}
+
+func commandCollection(started *event.CommandStartedEvent) string {
+	collection, _ := started.Command.Lookup(started.CommandName).StringValueOK()
+	return collection
+}
+
+// commandDoc returns a document field of the nth command sent, eg: the sort of a findAndModify
+func commandDoc(mt *mtest.T, n int, key string) bson.Raw {
+	return mt.GetAllStartedEvents()[n].Command.Lookup(key).Document()
+}
+
+// firstInCommand returns the first document of an array field of the nth command sent, eg: the update of an update command
+func firstInCommand(mt *mtest.T, n int, key string) bson.Raw {
+	return mt.GetAllStartedEvents()[n].Command.Lookup(key).Array().Index(0).Value().Document()
+}
+
+func newTestService(mt *mtest.T) *Service {
+	return &Service{Client: mt.Client, Database: mt.DB}
+}
+
+func TestReserveFormSubmission(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	ctx := context.Background()
+	response := models.FormResponse{UserID: primitive.NewObjectID()}
+
+	mt.Run("duplicate claim", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID()}
+		mt.AddMockResponses(mtest.CreateWriteErrorsResponse(mtest.WriteError{Index: 0, Code: 11000, Message: "duplicate key"}))
+
+		_, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		assert.Equal(t, ErrAlreadySubmitted, err)
+		assert.Equal(t, []string{"insert form_submitters"}, commands(mt))
+	})
+
+	mt.Run("response from before claims", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID()}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			found("test.responses", bson.D{{Key: "n", Value: 1}}),
+		)
+
+		_, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		assert.Equal(t, ErrAlreadySubmitted, err)
+
+		// The claim is kept since the user has submitted
+		assert.Equal(t, []string{"insert form_submitters", "aggregate responses"}, commands(mt))
+	})
+
+	mt.Run("seeds the counter of old forms", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID(), AllowMultipleSubmissions: true, MaxSubmissions: 5}
+		mt.AddMockResponses(
+			matchedNone,
+			found("test.form_submission_counts"),
+			found("test.responses", bson.D{{Key: "n", Value: 3}}),
+			found("test.responses", bson.D{{Key: "n", Value: 0}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+			matchedOne,
+		)
+
+		position, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		require.NoError(t, err)
+		assert.Zero(t, position)
+		assert.Equal(t, []string{
+			"update form_submission_counts",
+			"find form_submission_counts",
+			"aggregate responses",
+			"aggregate responses",
+			"update form_submission_counts",
+			"update form_submission_counts",
+		}, commands(mt))
+
+		// Waitlisted and released responses don't hold a place so they aren't counted
+		match := firstInCommand(mt, 2, "pipeline").Lookup("$match").Document()
+		assert.Contains(t, match.Lookup("status").String(), `"$nin"`)
+		assert.Contains(t, match.Lookup("status").String(), string(models.ResponseWaitlisted))
+		assert.Contains(t, match.Lookup("status").String(), string(models.ResponseReleased))
+
+		upsert := firstInCommand(mt, 4, "updates")
+		assert.Equal(t, int64(3), upsert.Lookup("u", "$setOnInsert", "count").AsInt64())
+		assert.Equal(t, int64(0), upsert.Lookup("u", "$set", "waitlisted").AsInt64())
+		assert.True(t, upsert.Lookup("upsert").Boolean())
+	})
+
+	mt.Run("waitlists new submissions while anyone is waiting", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID(), AllowMultipleSubmissions: true, MaxSubmissions: 5, EnableWaitlist: true}
+		mt.AddMockResponses(
+			matchedNone,
+			found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 4}, {Key: "waitlisted", Value: 2}}),
+			findAndModified(bson.D{{Key: "_id", Value: form.ID}, {Key: "waitlistNext", Value: 3}, {Key: "waitlisted", Value: 3}}),
+		)
+
+		position, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		require.NoError(t, err)
+		assert.Equal(t, 3, position)
+
+		// A place is only taken while nobody is waitlisted, joining the waitlist counts it
+		filter := firstInCommand(mt, 0, "updates").Lookup("q")
+		assert.Contains(t, filter.Document().Lookup("waitlisted").String(), `"$lte"`)
+		assert.Equal(t, int32(1), commandDoc(mt, 2, "update").Lookup("$inc", "waitlisted").Int32())
+	})
+
+	mt.Run("seeds the waitlist count of old counters", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID(), AllowMultipleSubmissions: true, MaxSubmissions: 5, EnableWaitlist: true}
+		mt.AddMockResponses(
+			matchedNone,
+			found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 4}}),
+			found("test.responses", bson.D{{Key: "n", Value: 4}}),
+			found("test.responses", bson.D{{Key: "n", Value: 1}}),
+			matchedOne,
+			matchedNone,
+			found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 4}, {Key: "waitlisted", Value: 1}}),
+			findAndModified(bson.D{{Key: "_id", Value: form.ID}, {Key: "waitlistNext", Value: 2}}),
+		)
+
+		position, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		require.NoError(t, err)
+		assert.Equal(t, 2, position)
+
+		// Only a counter without a waitlist count is seeded
+		seed := firstInCommand(mt, 4, "updates")
+		assert.Equal(t, int64(1), seed.Lookup("u", "$set", "waitlisted").AsInt64())
+		assert.Contains(t, seed.Lookup("q", "waitlisted").String(), `"$exists"`)
+	})
+
+	mt.Run("full form releases the claim", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID(), MaxSubmissions: 1}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(),
+			found("test.responses", bson.D{{Key: "n", Value: 0}}),
+			matchedNone,
+			found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 1}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		_, err := newTestService(mt).ReserveFormSubmission(ctx, form, response)
+		assert.Equal(t, ErrFormFull, err)
+		assert.Equal(t, []string{
+			"insert form_submitters",
+			"aggregate responses",
+			"update form_submission_counts",
+			"find form_submission_counts",
+			"delete form_submitters",
+		}, commands(mt))
+	})
+
+	mt.Run("full form with a waitlist", func(mt *mtest.T) {
+		form := models.FormStructure{ID: primitive.NewObjectID(), AllowMultipleSubmissions: true, MaxSubmissions: 1, EnableWaitlist: true}
+		full := found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 1}, {Key: "waitlisted", Value: 0}})
+		mt.AddMockResponses(
+			matchedNone, full, findAndModified(bson.D{{Key: "_id", Value: form.ID}, {Key: "waitlistNext", Value: 1}}),
+			matchedNone, full, findAndModified(bson.D{{Key: "_id", Value: form.ID}, {Key: "waitlistNext", Value: 2}}),
+		)
+
+		service := newTestService(mt)
+		first, err := service.ReserveFormSubmission(ctx, form, response)
+		require.NoError(t, err)
+		second, err := service.ReserveFormSubmission(ctx, form, response)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, []int{first, second})
+
+		// Positions are taken from the counter, so they only go up
+		assert.Equal(t, "findAndModify form_submission_counts", commands(mt)[2])
+		assert.Equal(t, int32(1), commandDoc(mt, 2, "update").Lookup("$inc", "waitlistNext").Int32())
+	})
+}
+
+func TestReleaseFormSubmission(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	response := models.FormResponse{FormID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+
+	mt.Run("held a place", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			findAndModified(bson.D{{Key: "_id", Value: response.FormID}, {Key: "count", Value: 3}, {Key: "waitlisted", Value: 0}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		promoted, err := newTestService(mt).ReleaseFormSubmission(context.Background(), response, true)
+		require.NoError(t, err)
+		assert.Nil(t, promoted)
+		assert.Equal(t, []string{"findAndModify form_submission_counts", "delete form_submitters"}, commands(mt))
+
+		// The place goes to the waitlist or back to the counter in one update, and the counter never goes below zero
+		update := firstInCommand(mt, 0, "update")
+		assert.Contains(t, update.Lookup("$set", "count").String(), `"$cond"`)
+		assert.Contains(t, update.Lookup("$set", "count").String(), `"$max"`)
+
+		deleted := firstInCommand(mt, 1, "deletes")
+		assert.Equal(t, formSubmitterID(response.FormID, response), deleted.Lookup("q", "_id").StringValue())
+	})
+
+	mt.Run("hands the place to the waitlist", func(mt *mtest.T) {
+		promotedID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			findAndModified(bson.D{{Key: "_id", Value: response.FormID}, {Key: "count", Value: 3}, {Key: "waitlisted", Value: 2}}),
+			findAndModified(bson.D{{Key: "_id", Value: promotedID}, {Key: "status", Value: models.ResponseSubmitted}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		promoted, err := newTestService(mt).ReleaseFormSubmission(context.Background(), response, true)
+		require.NoError(t, err)
+		require.NotNil(t, promoted)
+		assert.Equal(t, promotedID, promoted.ID)
+		assert.Equal(t, []string{"findAndModify form_submission_counts", "findAndModify responses", "delete form_submitters"}, commands(mt))
+
+		// The lowest waitlist position is promoted first
+		assert.Equal(t, int32(1), commandDoc(mt, 1, "sort").Lookup("waitlistPosition").Int32())
+	})
+
+	mt.Run("corrects the waitlist count when nobody is waiting", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			findAndModified(bson.D{{Key: "_id", Value: response.FormID}, {Key: "count", Value: 3}, {Key: "waitlisted", Value: 1}}),
+			findAndModified(nil),
+			found("test.responses", bson.D{{Key: "n", Value: 0}}),
+			matchedOne,
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}),
+		)
+
+		promoted, err := newTestService(mt).ReleaseFormSubmission(context.Background(), response, true)
+		require.NoError(t, err)
+		assert.Nil(t, promoted)
+
+		// The place handed to the waitlist is freed
+		update := firstInCommand(mt, 3, "updates").Lookup("u")
+		assert.Equal(t, int32(-1), update.Document().Lookup("$inc", "count").Int32())
+		assert.Equal(t, int64(0), update.Document().Lookup("$set", "waitlisted").AsInt64())
+	})
+
+	mt.Run("waitlisted", func(mt *mtest.T) {
+		mt.AddMockResponses(matchedOne, mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+
+		promoted, err := newTestService(mt).ReleaseFormSubmission(context.Background(), response, false)
+		require.NoError(t, err)
+		assert.Nil(t, promoted)
+		assert.Equal(t, []string{"update form_submission_counts", "delete form_submitters"}, commands(mt))
+		assert.Equal(t, int32(-1), firstInCommand(mt, 0, "updates").Lookup("u", "$inc", "waitlisted").Int32())
+	})
+}
+
+func TestReleaseResponsePlace(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	formID := primitive.NewObjectID()
+
+	mt.Run("held a place", func(mt *mtest.T) {
+		promotedID := primitive.NewObjectID()
+		mt.AddMockResponses(
+			findAndModified(bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "formID", Value: formID}, {Key: "status", Value: models.ResponseSubmitted}}),
+			findAndModified(bson.D{{Key: "_id", Value: formID}, {Key: "count", Value: 3}, {Key: "waitlisted", Value: 1}}),
+			findAndModified(bson.D{{Key: "_id", Value: promotedID}, {Key: "status", Value: models.ResponseSubmitted}}),
+		)
+
+		released, promoted, err := newTestService(mt).ReleaseResponsePlace(context.Background(), primitive.NewObjectID())
+		require.NoError(t, err)
+		require.NotNil(t, released)
+		require.NotNil(t, promoted)
+		assert.Equal(t, promotedID, promoted.ID)
+		assert.Equal(t, []string{"findAndModify responses", "findAndModify form_submission_counts", "findAndModify responses"}, commands(mt))
+	})
+
+	mt.Run("waitlisted", func(mt *mtest.T) {
+		mt.AddMockResponses(
+			findAndModified(bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "formID", Value: formID}, {Key: "status", Value: models.ResponseWaitlisted}}),
+			matchedOne,
+		)
+
+		released, promoted, err := newTestService(mt).ReleaseResponsePlace(context.Background(), primitive.NewObjectID())
+		require.NoError(t, err)
+		require.NotNil(t, released)
+		assert.Nil(t, promoted)
+		assert.Equal(t, []string{"findAndModify responses", "update form_submission_counts"}, commands(mt))
+		assert.Equal(t, int32(-1), firstInCommand(mt, 1, "updates").Lookup("u", "$inc", "waitlisted").Int32())
+	})
+
+	mt.Run("already released", func(mt *mtest.T) {
+		mt.AddMockResponses(findAndModified(nil))
+
+		released, promoted, err := newTestService(mt).ReleaseResponsePlace(context.Background(), primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Nil(t, released)
+		assert.Nil(t, promoted)
+	})
+}
+
+func TestReclaimResponsePlace(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	form := models.FormStructure{ID: primitive.NewObjectID(), MaxSubmissions: 1}
+	full := found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 1}})
+
+	mt.Run("takes a place", func(mt *mtest.T) {
+		mt.AddMockResponses(matchedOne, matchedOne)
+
+		status, position, err := newTestService(mt).ReclaimResponsePlace(context.Background(), form, primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Equal(t, models.ResponseSubmitted, status)
+		assert.Zero(t, position)
+	})
+
+	mt.Run("full form with a waitlist", func(mt *mtest.T) {
+		waitlisted := form
+		waitlisted.EnableWaitlist = true
+		full := found("test.form_submission_counts", bson.D{{Key: "_id", Value: form.ID}, {Key: "count", Value: 1}, {Key: "waitlisted", Value: 0}})
+		mt.AddMockResponses(matchedNone, full, findAndModified(bson.D{{Key: "_id", Value: form.ID}, {Key: "waitlistNext", Value: 4}}), matchedOne)
+
+		status, position, err := newTestService(mt).ReclaimResponsePlace(context.Background(), waitlisted, primitive.NewObjectID())
+		require.NoError(t, err)
+		assert.Equal(t, models.ResponseWaitlisted, status)
+		assert.Equal(t, 4, position)
+	})
+
+	mt.Run("full form", func(mt *mtest.T) {
+		mt.AddMockResponses(matchedNone, full)
+
+		_, _, err := newTestService(mt).ReclaimResponsePlace(context.Background(), form, primitive.NewObjectID())
+		assert.Equal(t, ErrFormFull, err)
+	})
+
+	mt.Run("gives the place back when the response isn't released", func(mt *mtest.T) {
+		mt.AddMockResponses(matchedOne, matchedNone, matchedOne)
+
+		_, _, err := newTestService(mt).ReclaimResponsePlace(context.Background(), form, primitive.NewObjectID())
+		assert.Error(t, err)
+		assert.Equal(t, []string{"update form_submission_counts", "update responses", "update form_submission_counts"}, commands(mt))
+		assert.Equal(t, int32(-1), firstInCommand(mt, 2, "updates").Lookup("u", "$inc", "count").Int32())
+	})
+}
+
+func TestEmailCampaignLease(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	campaignID, claimID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	mt.Run("claims scheduled or stale campaigns", func(mt *mtest.T) {
+		mt.AddMockResponses(findAndModified(bson.D{{Key: "_id", Value: campaignID}, {Key: "status", Value: models.EmailCampaignSending}, {Key: "claimID", Value: claimID}}))
+
+		campaign, err := newTestService(mt).ClaimScheduledEmailCampaign(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, claimID, campaign.ClaimID)
+
+		query := commandDoc(mt, 0, "query")
+		stale := query.Lookup("$or").Array().Index(1).Value().Document()
+		assert.Equal(t, string(models.EmailCampaignSending), stale.Lookup("status").StringValue())
+		assert.NotZero(t, stale.Lookup("heartbeatAt", "$not", "$gte").Time())
+
+		update := commandDoc(mt, 0, "update")
+		assert.NotEqual(t, primitive.NilObjectID, update.Lookup("$set", "claimID").ObjectID())
+		assert.NotZero(t, update.Lookup("$min", "startedAt").Time())
+	})
+
+	mt.Run("delivery renews the heartbeat", func(mt *mtest.T) {
+		mt.AddMockResponses(findAndModified(bson.D{{Key: "_id", Value: campaignID}, {Key: "status", Value: models.EmailCampaignSending}}))
+
+		_, err := newTestService(mt).RecordEmailCampaignDelivery(context.Background(), campaignID, claimID, " Alice@Example.com", nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, claimID, commandDoc(mt, 0, "query").Lookup("claimID").ObjectID())
+		update := commandDoc(mt, 0, "update")
+		assert.NotZero(t, update.Lookup("$set", "heartbeatAt").Time())
+		assert.Equal(t, "alice@example.com", update.Lookup("$addToSet", "attempted").StringValue())
+	})
+
+	mt.Run("another sender claimed it", func(mt *mtest.T) {
+		mt.AddMockResponses(findAndModified(nil))
+
+		_, err := newTestService(mt).RecordEmailCampaignDelivery(context.Background(), campaignID, claimID, "alice@example.com", nil)
+		assert.ErrorIs(t, err, mongo.ErrNoDocuments)
+	})
+}
@@ -4,6 +4,7 @@ package mongodb
 import (
 	"shared/models"
 	"shared/utils"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -44,6 +45,23 @@ func CanUserModifyEmailTemplate(c *gin.Context, m MongoService, u *models.User,
 	return CanUserModifyEvent(c, m, u, emailTemplate.EventID, nil)
 }
 
+// CanUserModifyEmailCampaign checks if the user provided can modify an email campaign.
+func CanUserModifyEmailCampaign(c *gin.Context, m MongoService, u *models.User, campaignID primitive.ObjectID, campaignObject *models.EmailCampaign) bool {
+	if u == nil {
+		return false
+	}
+
+	campaign := campaignObject
+	if campaign == nil {
+		ec, err := m.GetEmailCampaign(c, campaignID)
+		if err != nil {
+			return false
+		}
+		campaign = ec
+	}
+	return CanUserModifyEvent(c, m, u, campaign.EventID, nil)
+}
+
 // CanUserModifyForm checks if the user provided can modify a form.
 func CanUserModifyForm(c *gin.Context, m MongoService, u *models.User, formID primitive.ObjectID, formObject *models.FormStructure) bool {
 	if u == nil {
@@ -91,27 +109,101 @@ func CanUserModifyEvent(c *gin.Context, m MongoService, u *models.User, eventID
 	return found
 }
 
-func IsUserEmailInWhitelist(c *gin.Context, whitelist []models.FormAllowedSubmitter) (bool, string) {
+// CheckFormAccess works out which of the form's access rules lets the authenticated user submit it, open forms are granted
+// to everyone. Invite links pass their token in the ?invite= query parameter, checking an invite doesn't use it up.
+// When access is denied the grant is nil and the message explains why.
+func CheckFormAccess(c *gin.Context, m MongoService, form *models.FormStructure) (*models.FormAccessGrant, string) {
+	if !form.IsRestricted {
+		return &models.FormAccessGrant{Rule: models.FormAccessOpen}, ""
+	}
+
 	authenticatedUser, ok := utils.GetUserFromContext(c, true)
 	if !ok {
-		return false, "You must be logged in to view this form"
+		return nil, "You must be logged in to view this form"
+	}
+
+	emails := []string{authenticatedUser.Email}
+	if authenticatedUser.SchoolEmail != "" {
+		emails = append(emails, authenticatedUser.SchoolEmail)
 	}
 
 	hasExpired := false
-	for _, allowedSubmitter := range whitelist {
-		if allowedSubmitter.Email == authenticatedUser.Email || authenticatedUser.SchoolEmail == allowedSubmitter.Email {
+	for _, email := range emails {
+		for _, allowedSubmitter := range form.AllowedSubmitters {
+			if allowedSubmitter.Email != email {
+				continue
+			}
 			if allowedSubmitter.ExpiresAt.IsZero() || allowedSubmitter.ExpiresAt.After(time.Now()) {
-				return true, ""
-			} else {
-				hasExpired = true
+				return &models.FormAccessGrant{Rule: models.FormAccessEmail, Email: email}, ""
+			}
+			hasExpired = true
+		}
+
+		for _, allowedDomain := range form.AllowedDomains {
+			if !utils.EmailInDomain(email, allowedDomain.Domain, allowedDomain.AllowSubdomains) {
+				continue
+			}
+			if allowedDomain.ExpiresAt.IsZero() || allowedDomain.ExpiresAt.After(time.Now()) {
+				return &models.FormAccessGrant{Rule: models.FormAccessDomain, Email: email, Domain: allowedDomain.Domain}, ""
 			}
+			hasExpired = true
 		}
 	}
 
+	if token := c.Query("invite"); token != "" {
+		inviteID, err := utils.VerifyFormInviteToken(token)
+		if err != nil {
+			return nil, "This invite link is invalid"
+		}
+
+		invite, err := m.GetFormInvite(c, inviteID)
+		if err != nil || invite.FormID != form.ID {
+			return nil, "This invite link is invalid"
+		}
+
+		switch {
+		case !invite.RevokedAt.IsZero():
+			return nil, "This invite link has been revoked"
+		case !invite.ExpiresAt.IsZero() && invite.ExpiresAt.Before(time.Now()):
+			return nil, "This invite link has expired"
+		case invite.MaxUses > 0 && invite.Uses >= invite.MaxUses:
+			return nil, "This invite link has already been used"
+		case invite.Email != "" && !utils.StringInSlice(strings.ToLower(invite.Email), lowerAll(emails)):
+			return nil, "This invite link was sent to a different email address"
+		}
+
+		return &models.FormAccessGrant{Rule: models.FormAccessInvite, InviteID: invite.ID}, ""
+	}
+
 	if hasExpired {
-		// We're doing the check here incase the user has multiple emails in the whitelist and not all have expired
-		return false, "Your access to this form has expired"
+		// Reported last since another rule may still grant access
+		return nil, "Your access to this form has expired"
+	}
+
+	return nil, "You are not authorized to view this form"
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, len(values))
+	for i, value := range values {
+		lowered[i] = strings.ToLower(value)
+	}
+	return lowered
+}
+
+// CanUserModifyEmailComponent checks if the user provided can modify an email layout or partial.
+func CanUserModifyEmailComponent(c *gin.Context, m MongoService, u *models.User, componentID primitive.ObjectID, componentObject *models.EmailComponent) bool {
+	if u == nil {
+		return false
 	}
 
-	return false, "You are not authorized to view this form"
+	component := componentObject
+	if component == nil {
+		ec, err := m.GetEmailComponent(c, componentID)
+		if err != nil {
+			return false
+		}
+		component = ec
+	}
+	return CanUserModifyEvent(c, m, u, component.EventID, nil)
 }
@@ -0,0 +1,290 @@
+package selectors
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"shared/models"
+	"strings"
+	"time"
+)
+
+const (
+	// maxRemoteSize limits how much of a remote source is read
+	maxRemoteSize = 5 << 20
+
+	// MaxOptions limits how many options a source can have
+	MaxOptions = 20000
+
+	// defaultRefreshInterval is used for event URL sources that don't set their own
+	defaultRefreshInterval = 24 * time.Hour
+)
+
+// Formats a remote or uploaded list can be in
+const (
+	// FormatCSV lists take the first column of each row after the header row
+	FormatCSV = "csv"
+
+	// FormatLines lists have one option per line
+	FormatLines = "lines"
+
+	// FormatJSON lists are a JSON array of strings
+	FormatJSON = "json"
+)
+
+// ErrHostNotAllowed is returned for URLs that aren't https or point at a private, loopback or link-local address,
+// organizers choose the URLs so they mustn't be able to reach the cluster's internal services through them
+var ErrHostNotAllowed = errors.New("the URL must use https and point to a public host")
+
+// errUnreachable is recorded on a source in place of the network error, which can say what's on the internal network
+var errUnreachable = errors.New("the URL couldn't be fetched")
+
+// maxRemoteRedirects limits how many redirects are followed, every one is checked like the original URL
+const maxRemoteRedirects = 5
+
+// blockedNetworks are address ranges that aren't covered by the net.IP checks in isPublicIP
+var blockedNetworks = parseNetworks(
+	"0.0.0.0/8",     // this network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"240.0.0.0/4",   // reserved, including broadcast
+	"64:ff9b::/96",  // NAT64, maps to IPv4 addresses
+)
+
+// remoteClient is used to fetch remote sources, the timeout stops a slow server holding up the refresher.
+// Addresses are checked when connecting rather than when the URL is saved, so a redirect or a DNS record
+// that changes after the check can't reach an internal address either.
+var remoteClient = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		DialContext:         dialPublic,
+		TLSHandshakeTimeout: 10 * time.Second,
+		ForceAttemptHTTP2:   true,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRemoteRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRemoteRedirects)
+		}
+		return CheckRemoteURL(req.URL.String())
+	},
+}
+
+// fetchError wraps the network errors of a fetch, RefreshError hides them from organizers
+type fetchError struct {
+	err error
+}
+
+func (e *fetchError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fetchError) Unwrap() error {
+	return e.err
+}
+
+// CheckRemoteURL makes sure a source URL is https, the host's addresses are checked when it's fetched
+func CheckRemoteURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrHostNotAllowed
+	}
+
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !isPublicIP(ip) {
+		return ErrHostNotAllowed
+	}
+	return nil
+}
+
+// RefreshError is the error to record on a source for organizers to see. Network errors are replaced with
+// a generic one, status and format errors are kept so organizers can fix their list.
+func RefreshError(err error) error {
+	var fetchErr *fetchError
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, ErrHostNotAllowed):
+		return ErrHostNotAllowed
+	case errors.As(err, &fetchErr):
+		return errUnreachable
+	}
+	return err
+}
+
+// dialPublic connects to one of the host's addresses if all of them are public. The checked address is dialed
+// rather than the host name so the host can't resolve to a different address for the connection.
+func dialPublic(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return nil, ErrHostNotAllowed
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var dialErr error
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+	}
+	return nil, dialErr
+}
+
+// isPublicIP reports whether the address is on the public internet, loopback, private, link-local (which
+// includes cloud metadata endpoints like 169.254.169.254), multicast and reserved addresses aren't
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func parseNetworks(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// RemoteSource is a list fetched from a URL and cached in the sources collection
+type RemoteSource struct {
+	SourceName string
+	URL        string
+	Format     string
+	CacheFor   time.Duration
+}
+
+// NewEventRemoteSource creates the source for an event's URL selector source
+func NewEventRemoteSource(source models.SelectorSource) *RemoteSource {
+	cacheFor := time.Duration(source.RefreshEveryHours) * time.Hour
+	if cacheFor <= 0 {
+		cacheFor = defaultRefreshInterval
+	}
+
+	return &RemoteSource{
+		SourceName: source.SourceName,
+		URL:        source.URL,
+		Format:     source.Format,
+		CacheFor:   cacheFor,
+	}
+}
+
+func (s *RemoteSource) Name() string {
+	return s.SourceName
+}
+
+func (s *RemoteSource) MaxAge() time.Duration {
+	return s.CacheFor
+}
+
+func (s *RemoteSource) Fetch(ctx context.Context) ([]string, error) {
+	if err := CheckRemoteURL(s.URL); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, &fetchError{err: err}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request failed with status %d", resp.StatusCode)
+	}
+
+	return ParseOptions(io.LimitReader(resp.Body, maxRemoteSize), s.Format)
+}
+
+// ParseOptions reads a list in one of the formats, blank and duplicate options are dropped
+func ParseOptions(r io.Reader, format string) ([]string, error) {
+	var raw []string
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		// Skip the header row
+		for i, record := range records {
+			if i > 0 && len(record) > 0 {
+				raw = append(raw, record[0])
+			}
+		}
+
+	case FormatLines:
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			raw = append(raw, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+	case FormatJSON:
+		if err := json.NewDecoder(r).Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON, expected an array of strings: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+
+	seen := map[string]bool{}
+	options := []string{}
+	for _, option := range raw {
+		option = strings.TrimSpace(option)
+		if option == "" || seen[option] {
+			continue
+		}
+		seen[option] = true
+		options = append(options, option)
+	}
+
+	if len(options) > MaxOptions {
+		return nil, fmt.Errorf("lists can have at most %d options", MaxOptions)
+	}
+	if len(options) == 0 {
+		return nil, errors.New("the list has no options")
+	}
+
+	return options, nil
+}
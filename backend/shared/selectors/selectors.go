@@ -0,0 +1,108 @@
+package selectors
+
+import (
+	"context"
+	"embed"
+	"strings"
+	"time"
+)
+
+//go:embed data/*.txt
+var data embed.FS
+
+// Source is a list of options select fields can use with UseDefaultValuesFrom
+type Source interface {
+	Name() string
+
+	// MaxAge is how long fetched options are cached in the sources collection before they're refreshed,
+	// zero for sources that are held in memory and never cached
+	MaxAge() time.Duration
+
+	// Fetch loads the current options, remote sources go over the network so this should only be called
+	// by the background refresher
+	Fetch(ctx context.Context) ([]string, error)
+}
+
+// staticSource is a built-in list compiled into the binary
+type staticSource struct {
+	name    string
+	options []string
+}
+
+func (s staticSource) Name() string {
+	return s.name
+}
+
+func (s staticSource) MaxAge() time.Duration {
+	return 0
+}
+
+func (s staticSource) Fetch(ctx context.Context) ([]string, error) {
+	return s.options, nil
+}
+
+// builtins are the sources available to every event, event sources can't reuse their names
+var builtins = map[string]Source{
+	"countries":    staticSource{name: "countries", options: embeddedList("data/countries.txt")},
+	"timezones":    staticSource{name: "timezones", options: embeddedList("data/timezones.txt")},
+	"majors":       staticSource{name: "majors", options: embeddedList("data/majors.txt")},
+	"tshirt-sizes": staticSource{name: "tshirt-sizes", options: []string{"XS", "S", "M", "L", "XL", "2XL", "3XL"}},
+	"mlh-schools": &RemoteSource{
+		SourceName: "mlh-schools",
+		URL:        "https://raw.githubusercontent.com/MLH/mlh-policies/main/schools.csv",
+		Format:     FormatCSV,
+		CacheFor:   24 * time.Hour,
+	},
+}
+
+// Builtin returns the built-in source with the name
+func Builtin(name string) (Source, bool) {
+	source, ok := builtins[name]
+	return source, ok
+}
+
+// Builtins returns every built-in source
+func Builtins() []Source {
+	sources := make([]Source, 0, len(builtins))
+	for _, source := range builtins {
+		sources = append(sources, source)
+	}
+	return sources
+}
+
+func embeddedList(path string) []string {
+	content, err := data.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	return strings.Split(strings.TrimSpace(string(content)), "\n")
+}
+
+// Search returns the options containing the query, ignoring case. An empty query matches everything.
+func Search(options []string, query string) []string {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return options
+	}
+
+	matches := []string{}
+	for _, option := range options {
+		if strings.Contains(strings.ToLower(option), query) {
+			matches = append(matches, option)
+		}
+	}
+	return matches
+}
+
+// Page returns up to limit options starting at offset, a limit of zero returns the rest of the options
+func Page(options []string, offset int, limit int) []string {
+	if offset >= len(options) {
+		return []string{}
+	}
+	options = options[offset:]
+
+	if limit > 0 && limit < len(options) {
+		options = options[:limit]
+	}
+	return options
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrFileNotFound is returned when no file is stored under a key
+var ErrFileNotFound = errors.New("file not found")
+
+// FileStorage stores uploaded files by key
+type FileStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// NewFileStorage creates the storage backend selected by FILE_STORAGE, either "local" (the default) or "s3"
+func NewFileStorage() (FileStorage, error) {
+	switch backend := os.Getenv("FILE_STORAGE"); backend {
+	case "", "local":
+		path := os.Getenv("FILE_STORAGE_PATH")
+		if path == "" {
+			path = "uploads"
+		}
+		return NewLocalStorage(path)
+	case "s3":
+		return NewS3Storage(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown FILE_STORAGE %s, expected local or s3", backend)
+	}
+}
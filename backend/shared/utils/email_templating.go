@@ -0,0 +1,199 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"regexp"
+	"shared/models"
+	"strings"
+)
+
+// templatePlaceholderRegex matches the ${field_id} placeholders used in email templates
+var templatePlaceholderRegex = regexp.MustCompile(`\$\{\s*([^}]*?)\s*\}`)
+
+// templatePartialRegex matches a {{> partial_name}} include
+var templatePartialRegex = regexp.MustCompile(`\{\{>\s*([a-zA-Z0-9_-]+)\s*\}\}`)
+
+// layoutContentRegex matches the {{content}} slot a layout wraps the template body in
+var layoutContentRegex = regexp.MustCompile(`\{\{\s*content\s*\}\}`)
+
+// maxPartialDepth limits how deeply partials can include other partials, this also stops include cycles
+const maxPartialDepth = 5
+
+var (
+	// ErrLayoutMissingContent is returned when a layout has no {{content}} slot
+	ErrLayoutMissingContent = errors.New("layout must contain a {{content}} slot")
+
+	// ErrLayoutNotFound is returned when a template's layout is not one of the event's layouts
+	ErrLayoutNotFound = errors.New("the template's layout does not exist")
+
+	// ErrPartialDepthExceeded is returned when partials are nested too deeply or include each other
+	ErrPartialDepthExceeded = fmt.Errorf("partials can only be nested %d levels deep", maxPartialDepth)
+)
+
+// ExtractTemplatePlaceholders returns the unique placeholder keys referenced in a template, in order of first use
+func ExtractTemplatePlaceholders(template string) []string {
+	var keys []string
+	for _, match := range templatePlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		if !StringInSlice(match[1], keys) {
+			keys = append(keys, match[1])
+		}
+	}
+	return keys
+}
+
+// RenderEmailTemplate replaces every ${field_id} placeholder with the matching value from data.
+// Placeholders without a value are replaced with an empty string. Values are inserted as they are, which is
+// only safe for plain text bodies: use RenderEmailSubject for subjects and RenderEmailHTML for HTML bodies.
+func RenderEmailTemplate(template string, data map[string]interface{}) string {
+	return renderEmailTemplate(template, data, func(value string) string {
+		return value
+	})
+}
+
+// RenderEmailSubject renders a subject line, line breaks in values are replaced with spaces so an answer
+// can't add headers to the message
+func RenderEmailSubject(template string, data map[string]interface{}) string {
+	return renderEmailTemplate(template, data, func(value string) string {
+		return strings.Join(strings.FieldsFunc(value, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+	})
+}
+
+// RenderEmailHTML renders an HTML body, values are escaped so answers can't add markup or links to the email
+func RenderEmailHTML(template string, data map[string]interface{}) string {
+	return renderEmailTemplate(template, data, html.EscapeString)
+}
+
+// renderEmailTemplate replaces the placeholders with their values passed through format
+func renderEmailTemplate(template string, data map[string]interface{}, format func(string) string) string {
+	return templatePlaceholderRegex.ReplaceAllStringFunc(template, func(placeholder string) string {
+		key := templatePlaceholderRegex.FindStringSubmatch(placeholder)[1]
+		value, ok := data[key]
+		if !ok || value == nil {
+			return ""
+		}
+
+		switch v := value.(type) {
+		case string:
+			return format(v)
+		case []interface{}:
+			parts := make([]string, len(v))
+			for i, part := range v {
+				parts[i] = fmt.Sprintf("%v", part)
+			}
+			return format(strings.Join(parts, ", "))
+		default:
+			return format(fmt.Sprintf("%v", v))
+		}
+	})
+}
+
+// ExtractTemplatePartials returns the unique partial names included with {{> name}}, in order of first use
+func ExtractTemplatePartials(template string) []string {
+	var names []string
+	for _, match := range templatePartialRegex.FindAllStringSubmatch(template, -1) {
+		if !StringInSlice(match[1], names) {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+// LayoutHasContentSlot checks a layout has somewhere to put the template body
+func LayoutHasContentSlot(layout string) bool {
+	return layoutContentRegex.MatchString(layout)
+}
+
+// ComposeEmailBody expands the partials included in the body and wraps it in the layout, if one is given.
+// Field placeholders are left as is so the result can be passed to RenderEmailTemplate.
+func ComposeEmailBody(body string, layout string, partials map[string]string) (string, error) {
+	return composeEmailBody(body, layout, partials, func(string) {})
+}
+
+// composeEmailBody is ComposeEmailBody, calling included with the name of every partial it expands
+func composeEmailBody(body string, layout string, partials map[string]string, included func(name string)) (string, error) {
+	if layout != "" {
+		if !LayoutHasContentSlot(layout) {
+			return "", ErrLayoutMissingContent
+		}
+
+		// Replace with a function so $ in the body isn't treated as a regex group reference
+		body = layoutContentRegex.ReplaceAllStringFunc(layout, func(string) string {
+			return body
+		})
+	}
+
+	for depth := 0; templatePartialRegex.MatchString(body); depth++ {
+		if depth >= maxPartialDepth {
+			return "", ErrPartialDepthExceeded
+		}
+
+		var missing error
+		body = templatePartialRegex.ReplaceAllStringFunc(body, func(include string) string {
+			name := templatePartialRegex.FindStringSubmatch(include)[1]
+			partial, ok := partials[name]
+			if !ok {
+				missing = fmt.Errorf("partial %s does not exist", name)
+			}
+			included(name)
+			return partial
+		})
+
+		if missing != nil {
+			return "", missing
+		}
+	}
+
+	return body, nil
+}
+
+// ComposeEmailTemplateBody composes a template's body with its layout and the partials in components,
+// components should be every layout and partial of the template's event.
+func ComposeEmailTemplateBody(template models.EmailTemplate, components []models.EmailComponent) (string, error) {
+	body, _, err := ComposeEmailTemplate(template, components)
+	return body, err
+}
+
+// ComposeEmailTemplate composes a template's body like ComposeEmailTemplateBody, it also returns the versions
+// of the layout and partials the body was composed with so a sent email can be traced back to them.
+func ComposeEmailTemplate(template models.EmailTemplate, components []models.EmailComponent) (string, []models.EmailComponentRef, error) {
+	var layout models.EmailComponent
+	foundLayout := template.LayoutID.IsZero()
+	partials := map[string]string{}
+	partialComponents := map[string]models.EmailComponent{}
+	for _, component := range components {
+		switch component.Kind {
+		case models.EmailComponentPartial:
+			partials[component.Name] = component.Body
+			partialComponents[component.Name] = component
+		case models.EmailComponentLayout:
+			if component.ID == template.LayoutID {
+				layout = component
+				foundLayout = true
+			}
+		}
+	}
+
+	if !foundLayout {
+		return "", nil, ErrLayoutNotFound
+	}
+
+	used := []models.EmailComponentRef{}
+	if !template.LayoutID.IsZero() {
+		used = append(used, models.EmailComponentRef{ComponentID: layout.ID, Version: layout.Version})
+	}
+
+	seen := map[string]bool{}
+	body, err := composeEmailBody(template.Body, layout.Body, partials, func(name string) {
+		if partial, ok := partialComponents[name]; ok && !seen[name] {
+			seen[name] = true
+			used = append(used, models.EmailComponentRef{ComponentID: partial.ID, Version: partial.Version})
+		}
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return body, used, nil
+}
@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRenderEmailTemplate(t *testing.T) {
+	data := map[string]interface{}{
+		"name":   "Alice",
+		"age":    21,
+		"tracks": []interface{}{"beginner", "hardware"},
+	}
+
+	cases := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{"No placeholders", "Hello!", "Hello!"},
+		{"String value", "Hello ${name}!", "Hello Alice!"},
+		{"Whitespace in placeholder", "Hello ${ name }!", "Hello Alice!"},
+		{"Non string value", "You are ${age}", "You are 21"},
+		{"Array value", "Tracks: ${tracks}", "Tracks: beginner, hardware"},
+		{"Missing value", "Hello ${missing}!", "Hello !"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, RenderEmailTemplate(tc.template, data))
+		})
+	}
+}
+
+func TestRenderEmailSubject(t *testing.T) {
+	data := map[string]interface{}{"name": "Alice\r\nBcc: everyone@example.com\nX-Injected: 1"}
+
+	subject := RenderEmailSubject("Welcome ${name}", data)
+	assert.Equal(t, "Welcome Alice Bcc: everyone@example.com X-Injected: 1", subject)
+	assert.NotContains(t, subject, "\r")
+	assert.NotContains(t, subject, "\n")
+}
+
+func TestRenderEmailHTML(t *testing.T) {
+	data := map[string]interface{}{
+		"name":   `<a href="https://evil.example">Click</a>`,
+		"tracks": []interface{}{"<b>ai</b>", "web & mobile"},
+	}
+
+	assert.Equal(t,
+		"<p>Hi &lt;a href=&#34;https://evil.example&#34;&gt;Click&lt;/a&gt;</p>",
+		RenderEmailHTML("<p>Hi ${name}</p>", data),
+	)
+	assert.Equal(t, "&lt;b&gt;ai&lt;/b&gt;, web &amp; mobile", RenderEmailHTML("${tracks}", data))
+}
+
+func TestExtractTemplatePlaceholders(t *testing.T) {
+	keys := ExtractTemplatePlaceholders("Hi ${name}, your track is ${track}. Bye ${name}")
+	assert.Equal(t, []string{"name", "track"}, keys)
+	assert.Nil(t, ExtractTemplatePlaceholders("no placeholders"))
+}
+
+func TestComposeEmailBody(t *testing.T) {
+	partials := map[string]string{
+		"footer":  "<footer>{{> social}}</footer>",
+		"social":  "Follow us",
+		"loopA":   "{{> loopB}}",
+		"loopB":   "{{> loopA}}",
+		"dollars": "Costs $1",
+	}
+
+	cases := []struct {
+		name     string
+		body     string
+		layout   string
+		expected string
+		err      bool
+	}{
+		{"No layout or partials", "Hello ${name}", "", "Hello ${name}", false},
+		{"Layout", "Hello", "<main>{{ content }}</main>", "<main>Hello</main>", false},
+		{"Nested partials", "Hello{{> footer}}", "", "Hello<footer>Follow us</footer>", false},
+		{"Partial in layout", "Hello", "{{content}}{{> footer }}", "Hello<footer>Follow us</footer>", false},
+		{"Dollar signs are kept", "{{> dollars}} ${name}", "{{content}}", "Costs $1 ${name}", false},
+		{"Missing partial", "{{> header}}", "", "", true},
+		{"Partial cycle", "{{> loopA}}", "", "", true},
+		{"Layout without content slot", "Hello", "<main></main>", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			composed, err := ComposeEmailBody(tc.body, tc.layout, partials)
+			if tc.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expected, composed)
+		})
+	}
+}
+
+func TestComposeEmailTemplate(t *testing.T) {
+	layout := models.EmailComponent{ID: primitive.NewObjectID(), Kind: models.EmailComponentLayout, Name: "main", Body: "{{content}}{{> footer}}", Version: 3}
+	footer := models.EmailComponent{ID: primitive.NewObjectID(), Kind: models.EmailComponentPartial, Name: "footer", Body: "<footer>{{> social}}</footer>", Version: 2}
+	social := models.EmailComponent{ID: primitive.NewObjectID(), Kind: models.EmailComponentPartial, Name: "social", Body: "Follow us", Version: 1}
+	unused := models.EmailComponent{ID: primitive.NewObjectID(), Kind: models.EmailComponentPartial, Name: "unused", Body: "Unused", Version: 5}
+	components := []models.EmailComponent{unused, social, footer, layout}
+
+	body, used, err := ComposeEmailTemplate(models.EmailTemplate{Body: "Hi {{> social}}", LayoutID: layout.ID}, components)
+	assert.Nil(t, err)
+	assert.Equal(t, "Hi Follow us<footer>Follow us</footer>", body)
+	assert.Equal(t, []models.EmailComponentRef{
+		{ComponentID: layout.ID, Version: 3},
+		{ComponentID: social.ID, Version: 1},
+		{ComponentID: footer.ID, Version: 2},
+	}, used)
+
+	_, used, err = ComposeEmailTemplate(models.EmailTemplate{Body: "Hi"}, components)
+	assert.Nil(t, err)
+	assert.Empty(t, used)
+
+	_, _, err = ComposeEmailTemplate(models.EmailTemplate{Body: "Hi", LayoutID: primitive.NewObjectID()}, components)
+	assert.Equal(t, ErrLayoutNotFound, err)
+}
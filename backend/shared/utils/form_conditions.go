@@ -0,0 +1,206 @@
+package utils
+
+import (
+	"fmt"
+	"shared/models"
+)
+
+// ValidateFormStructure checks the form's sections and visibility conditions, every condition has to
+// reference another field on the form and no field's visibility can end up depending on itself
+func ValidateFormStructure(form models.FormStructure) []string {
+	var errors []string
+
+	sections := map[string]models.FormSection{}
+	for _, section := range form.Sections {
+		if _, ok := sections[section.Key]; ok {
+			errors = append(errors, fmt.Sprintf("Section %s is used more than once", section.Key))
+		}
+		sections[section.Key] = section
+	}
+
+	fields := map[string]models.FormField{}
+	for _, field := range form.Attrs {
+		if _, ok := fields[field.Key]; ok {
+			errors = append(errors, fmt.Sprintf("Field %s is used more than once", field.Key))
+		}
+		fields[field.Key] = field
+	}
+
+	for _, field := range form.Attrs {
+		if field.SectionKey != "" {
+			if _, ok := sections[field.SectionKey]; !ok {
+				errors = append(errors, fmt.Sprintf("%s is in section %s which does not exist", field.Question, field.SectionKey))
+			}
+		}
+
+		for _, condition := range field.VisibleIf {
+			if _, ok := fields[condition.FieldKey]; !ok {
+				errors = append(errors, fmt.Sprintf("%s depends on field %s which does not exist", field.Question, condition.FieldKey))
+			}
+		}
+
+		if err := validateFieldPrefill(field); err != "" {
+			errors = append(errors, err)
+		}
+	}
+
+	for _, section := range form.Sections {
+		for _, condition := range section.VisibleIf {
+			if _, ok := fields[condition.FieldKey]; !ok {
+				errors = append(errors, fmt.Sprintf("Section %s depends on field %s which does not exist", section.Title, condition.FieldKey))
+			}
+		}
+	}
+
+	// Depth first search for a field that its own visibility depends on
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var visit func(key string) bool
+	visit = func(key string) bool {
+		switch state[key] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+
+		state[key] = visiting
+		for _, dependency := range fieldDependencies(fields[key], sections) {
+			if _, ok := fields[dependency]; ok && visit(dependency) {
+				return true
+			}
+		}
+		state[key] = visited
+		return false
+	}
+
+	for _, field := range form.Attrs {
+		if state[field.Key] == unvisited && visit(field.Key) {
+			errors = append(errors, fmt.Sprintf("The visibility of %s depends on itself", field.Question))
+			break
+		}
+	}
+
+	// Anonymous respondents have no account to check access against or to upload files as
+	if form.AllowAnonymous {
+		if form.IsRestricted {
+			errors = append(errors, "Restricted forms can't allow anonymous responses")
+		}
+
+		for _, field := range form.Attrs {
+			if field.Type == models.FieldTypeFile {
+				errors = append(errors, fmt.Sprintf("%s is a file field, forms with file fields can't allow anonymous responses", field.Question))
+			}
+		}
+	}
+
+	return errors
+}
+
+// fieldDependencies returns the keys of the fields the field's visibility depends on, including through its section
+func fieldDependencies(field models.FormField, sections map[string]models.FormSection) []string {
+	var keys []string
+	for _, condition := range field.VisibleIf {
+		keys = append(keys, condition.FieldKey)
+	}
+	if section, ok := sections[field.SectionKey]; ok {
+		for _, condition := range section.VisibleIf {
+			keys = append(keys, condition.FieldKey)
+		}
+	}
+	return keys
+}
+
+// VisibleFormFields returns which fields are shown for the response data. A field is hidden if its section
+// or its own conditions don't match, and a hidden field's value never matches a condition.
+func VisibleFormFields(form *models.FormStructure, data map[string]interface{}) map[string]bool {
+	sections := map[string]models.FormSection{}
+	for _, section := range form.Sections {
+		sections[section.Key] = section
+	}
+
+	fields := map[string]models.FormField{}
+	for _, field := range form.Attrs {
+		fields[field.Key] = field
+	}
+
+	visible := map[string]bool{}
+	evaluating := map[string]bool{}
+	var isVisible func(key string) bool
+	isVisible = func(key string) bool {
+		if result, ok := visible[key]; ok {
+			return result
+		}
+
+		// Forms are checked for cycles when saved, this only guards against older forms
+		if evaluating[key] {
+			return false
+		}
+		evaluating[key] = true
+
+		field := fields[key]
+		conditions := field.VisibleIf
+		if section, ok := sections[field.SectionKey]; ok {
+			conditions = append(append([]models.FormFieldCondition{}, section.VisibleIf...), conditions...)
+		}
+
+		result := true
+		for _, condition := range conditions {
+			var value interface{}
+			if _, ok := fields[condition.FieldKey]; ok && isVisible(condition.FieldKey) {
+				value = data[condition.FieldKey]
+			}
+
+			if !formConditionMatches(condition, value) {
+				result = false
+				break
+			}
+		}
+
+		visible[key] = result
+		return result
+	}
+
+	for _, field := range form.Attrs {
+		isVisible(field.Key)
+	}
+
+	return visible
+}
+
+// formConditionMatches compares the condition against a value, lists match eq when they contain the value
+func formConditionMatches(condition models.FormFieldCondition, value interface{}) bool {
+	var matches bool
+	switch v := value.(type) {
+	case nil:
+		matches = condition.Value == ""
+	case []interface{}:
+		for _, item := range v {
+			if fmt.Sprintf("%v", item) == condition.Value {
+				matches = true
+				break
+			}
+		}
+	default:
+		matches = fmt.Sprintf("%v", v) == condition.Value
+	}
+
+	if condition.Comparison == models.ComparisonNeq {
+		return !matches
+	}
+	return matches
+}
+
+// DropHiddenFormFields removes the values of hidden fields from the response data so they aren't stored
+func DropHiddenFormFields(form *models.FormStructure, data map[string]interface{}) {
+	visible := VisibleFormFields(form, data)
+	for key, isVisible := range visible {
+		if !isVisible {
+			delete(data, key)
+		}
+	}
+}
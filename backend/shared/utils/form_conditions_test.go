@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFormStructure(t *testing.T) {
+	form := models.FormStructure{
+		Sections: []models.FormSection{{Key: "food", Title: "Food"}},
+		Attrs: []models.FormField{
+			{Key: "restrictions", Question: "Dietary restrictions", Type: models.FieldTypeRadio},
+			{Key: "details", Question: "Dietary details", Type: models.FieldTypeText, SectionKey: "food", VisibleIf: []models.FormFieldCondition{
+				{FieldKey: "restrictions", Comparison: models.ComparisonEq, Value: "Yes"},
+			}},
+		},
+	}
+	assert.Empty(t, ValidateFormStructure(form))
+
+	dangling := form
+	dangling.Attrs = []models.FormField{form.Attrs[1]}
+	assert.Equal(t, []string{"Dietary details depends on field restrictions which does not exist"}, ValidateFormStructure(dangling))
+
+	missingSection := form
+	missingSection.Sections = nil
+	assert.Equal(t, []string{"Dietary details is in section food which does not exist"}, ValidateFormStructure(missingSection))
+
+	// A cycle through the section's condition
+	cyclic := form
+	cyclic.Attrs = []models.FormField{
+		{Key: "restrictions", Question: "Dietary restrictions", Type: models.FieldTypeRadio, SectionKey: "food"},
+		form.Attrs[1],
+	}
+	cyclic.Sections = []models.FormSection{{Key: "food", Title: "Food", VisibleIf: []models.FormFieldCondition{
+		{FieldKey: "details", Comparison: models.ComparisonNeq, Value: ""},
+	}}}
+	assert.Len(t, ValidateFormStructure(cyclic), 1)
+
+	anonymous := form
+	anonymous.AllowAnonymous = true
+	assert.Empty(t, ValidateFormStructure(anonymous))
+
+	anonymous.IsRestricted = true
+	anonymous.Attrs = append([]models.FormField{{Key: "resume", Question: "Resume", Type: models.FieldTypeFile}}, form.Attrs...)
+	assert.Equal(t, []string{
+		"Restricted forms can't allow anonymous responses",
+		"Resume is a file field, forms with file fields can't allow anonymous responses",
+	}, ValidateFormStructure(anonymous))
+}
+
+func TestHiddenFormFields(t *testing.T) {
+	form := &models.FormStructure{
+		Attrs: []models.FormField{
+			{Key: "restrictions", Question: "Dietary restrictions", Type: models.FieldTypeMultiSelect, Options: []string{"Vegan", "Halal"}},
+			{Key: "vegan", Question: "Vegan details", Type: models.FieldTypeText, Required: true, VisibleIf: []models.FormFieldCondition{
+				{FieldKey: "restrictions", Comparison: models.ComparisonEq, Value: "Vegan"},
+			}},
+			{Key: "veganMore", Question: "More vegan details", Type: models.FieldTypeText, VisibleIf: []models.FormFieldCondition{
+				{FieldKey: "vegan", Comparison: models.ComparisonNeq, Value: ""},
+			}},
+		},
+	}
+
+	// Hidden fields are not required
+	assert.Empty(t, ValidateFormData(form, map[string]interface{}{"restrictions": []interface{}{"Halal"}}, false))
+	assert.Equal(t, map[string]string{"vegan": "This field is required"}, ValidateFormData(form, map[string]interface{}{"restrictions": []interface{}{"Vegan"}}, false))
+
+	// Hidden values are dropped, including fields that depend on a hidden field
+	data := map[string]interface{}{"restrictions": []interface{}{"Halal"}, "vegan": "No eggs", "veganMore": "Or milk"}
+	DropHiddenFormFields(form, data)
+	assert.Equal(t, map[string]interface{}{"restrictions": []interface{}{"Halal"}}, data)
+}
@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"shared/models"
+	"strings"
+	"time"
+)
+
+// validateFieldPrefill checks a field's prefill settings make sense, returning an error message or an empty string
+func validateFieldPrefill(field models.FormField) string {
+	prefill := field.Prefill
+	if prefill == nil {
+		return ""
+	}
+
+	switch {
+	case (prefill.ProfileAttribute == "") == (prefill.QueryParam == ""):
+		return fmt.Sprintf("%s must be prefilled from either a profile attribute or a query parameter", field.Question)
+	case field.IsInternal || field.Type == models.FieldTypeFile:
+		return fmt.Sprintf("%s can't be prefilled", field.Question)
+	case prefill.QueryParam != "" && (prefill.Locked || prefill.WriteBack):
+		return fmt.Sprintf("%s can only be locked or written back when it's prefilled from the profile", field.Question)
+	case prefill.Locked && prefill.WriteBack:
+		return fmt.Sprintf("%s can't be locked and written back to the profile", field.Question)
+	case prefill.WriteBack && (prefill.ProfileAttribute == models.ProfileEmail || prefill.ProfileAttribute == models.ProfileFullName):
+		return fmt.Sprintf("%s can't write back to the profile's %s", field.Question, prefill.ProfileAttribute)
+	case prefill.ProfileAttribute == models.ProfileBirthday && field.Type != models.FieldTypeDate:
+		return fmt.Sprintf("%s must be a date field to be prefilled with the birthday", field.Question)
+	}
+
+	return ""
+}
+
+// ProfileAttributeValue returns the user's value for a profile attribute as a form value, false when it's not set
+func ProfileAttributeValue(user *models.User, attribute models.ProfileAttribute) (string, bool) {
+	var value string
+	switch attribute {
+	case models.ProfileFirstName:
+		value = user.FirstName
+	case models.ProfileLastName:
+		value = user.LastName
+	case models.ProfileFullName:
+		value = strings.TrimSpace(user.FirstName + " " + user.LastName)
+	case models.ProfileEmail:
+		value = user.Email
+	case models.ProfileSchoolEmail:
+		value = user.SchoolEmail
+	case models.ProfileBirthday:
+		if !user.Birthday.IsZero() {
+			value = user.Birthday.Format(time.RFC3339)
+		}
+	}
+
+	return value, value != ""
+}
+
+// FormUsesProfile reports whether any of the form's fields are prefilled from or written back to the profile
+func FormUsesProfile(form *models.FormStructure) bool {
+	for _, field := range form.Attrs {
+		if field.Prefill != nil && field.Prefill.ProfileAttribute != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// PrefillFormData returns the initial value of each prefilled field from the user's profile and the query parameters.
+// user is nil for anonymous applicants. Values that wouldn't pass validation are left out.
+func PrefillFormData(form *models.FormStructure, user *models.User, query url.Values) map[string]interface{} {
+	data := map[string]interface{}{}
+	for _, field := range form.Attrs {
+		if field.Prefill == nil || field.IsInternal {
+			continue
+		}
+
+		var value string
+		var ok bool
+		if field.Prefill.ProfileAttribute != "" {
+			if user == nil {
+				continue
+			}
+			value, ok = ProfileAttributeValue(user, field.Prefill.ProfileAttribute)
+		} else {
+			value = query.Get(field.Prefill.QueryParam)
+			ok = value != ""
+		}
+
+		if !ok || validateFormValue(field, value) != "" {
+			continue
+		}
+		data[field.Key] = value
+	}
+
+	return data
+}
+
+// LockPrefilledFields replaces the values of locked profile fields with the user's profile values,
+// so an applicant can't submit something else. Fields whose profile value isn't set are left to the applicant.
+func LockPrefilledFields(form *models.FormStructure, data map[string]interface{}, user *models.User) {
+	for _, field := range form.Attrs {
+		if field.Prefill == nil || !field.Prefill.Locked || field.Prefill.ProfileAttribute == "" {
+			continue
+		}
+
+		if value, ok := ProfileAttributeValue(user, field.Prefill.ProfileAttribute); ok {
+			data[field.Key] = value
+		}
+	}
+}
+
+// ApplyProfileWriteBack copies the submitted values of write back fields onto the user's profile,
+// it returns true if anything changed. The data must already be validated.
+func ApplyProfileWriteBack(form *models.FormStructure, data map[string]interface{}, user *models.User) bool {
+	changed := false
+	for _, field := range form.Attrs {
+		if field.Prefill == nil || !field.Prefill.WriteBack {
+			continue
+		}
+
+		value, ok := data[field.Key].(string)
+		if !ok || value == "" {
+			continue
+		}
+
+		switch field.Prefill.ProfileAttribute {
+		case models.ProfileFirstName:
+			changed = changed || user.FirstName != value
+			user.FirstName = value
+		case models.ProfileLastName:
+			changed = changed || user.LastName != value
+			user.LastName = value
+		case models.ProfileSchoolEmail:
+			if formEmailRegex.MatchString(value) {
+				changed = changed || user.SchoolEmail != value
+				user.SchoolEmail = value
+			}
+		case models.ProfileBirthday:
+			if birthday, err := time.Parse(time.RFC3339, value); err == nil {
+				changed = changed || !user.Birthday.Equal(birthday)
+				user.Birthday = birthday
+			}
+		}
+	}
+
+	return changed
+}
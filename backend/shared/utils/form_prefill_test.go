@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"net/url"
+	"shared/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFieldPrefill(t *testing.T) {
+	name := models.FormField{Key: "name", Question: "Name", Type: models.FieldTypeText}
+	birthday := models.FormField{Key: "birthday", Question: "Birthday", Type: models.FieldTypeDate}
+
+	valid := []models.FormField{
+		name,
+		withPrefill(name, models.FieldPrefill{ProfileAttribute: models.ProfileFullName, Locked: true}),
+		withPrefill(name, models.FieldPrefill{QueryParam: "ref"}),
+		withPrefill(birthday, models.FieldPrefill{ProfileAttribute: models.ProfileBirthday, WriteBack: true}),
+	}
+	for _, field := range valid {
+		assert.Empty(t, validateFieldPrefill(field))
+	}
+
+	invalid := []models.FormField{
+		withPrefill(name, models.FieldPrefill{}),
+		withPrefill(name, models.FieldPrefill{ProfileAttribute: models.ProfileFirstName, QueryParam: "ref"}),
+		withPrefill(name, models.FieldPrefill{QueryParam: "ref", Locked: true}),
+		withPrefill(name, models.FieldPrefill{ProfileAttribute: models.ProfileFirstName, Locked: true, WriteBack: true}),
+		withPrefill(name, models.FieldPrefill{ProfileAttribute: models.ProfileEmail, WriteBack: true}),
+		withPrefill(name, models.FieldPrefill{ProfileAttribute: models.ProfileBirthday}),
+	}
+	for _, field := range invalid {
+		assert.NotEmpty(t, validateFieldPrefill(field), "%+v", field.Prefill)
+	}
+}
+
+func TestPrefillFormData(t *testing.T) {
+	form := &models.FormStructure{Attrs: []models.FormField{
+		withPrefill(models.FormField{Key: "name", Question: "Name", Type: models.FieldTypeText}, models.FieldPrefill{ProfileAttribute: models.ProfileFullName, Locked: true}),
+		withPrefill(models.FormField{Key: "birthday", Question: "Birthday", Type: models.FieldTypeDate}, models.FieldPrefill{ProfileAttribute: models.ProfileBirthday, WriteBack: true}),
+		withPrefill(models.FormField{Key: "school", Question: "School email", Type: models.FieldTypeText}, models.FieldPrefill{ProfileAttribute: models.ProfileSchoolEmail, WriteBack: true}),
+		withPrefill(models.FormField{Key: "source", Question: "How did you hear about us?", Type: models.FieldTypeSelect, Options: []string{"Twitter", "Friend"}}, models.FieldPrefill{QueryParam: "utm_source"}),
+	}}
+	user := &models.User{FirstName: "Ada", LastName: "Lovelace", Birthday: time.Date(2000, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	assert.Equal(t, map[string]interface{}{
+		"name":     "Ada Lovelace",
+		"birthday": "2000-01-02T00:00:00Z",
+		"source":   "Twitter",
+	}, PrefillFormData(form, user, url.Values{"utm_source": {"Twitter"}}))
+
+	// Anonymous applicants only get query prefill, and values that aren't valid are left out
+	assert.Empty(t, PrefillFormData(form, nil, url.Values{"utm_source": {"Newsletter"}}))
+
+	// Locked fields take the profile's value
+	data := map[string]interface{}{"name": "Someone else"}
+	LockPrefilledFields(form, data, user)
+	assert.Equal(t, "Ada Lovelace", data["name"])
+
+	// Written back values update the profile
+	data = map[string]interface{}{"birthday": "2001-03-04T00:00:00Z", "school": "ada@uni.edu"}
+	assert.True(t, ApplyProfileWriteBack(form, data, user))
+	assert.Equal(t, time.Date(2001, 3, 4, 0, 0, 0, 0, time.UTC), user.Birthday)
+	assert.Equal(t, "ada@uni.edu", user.SchoolEmail)
+	assert.False(t, ApplyProfileWriteBack(form, data, user))
+}
+
+func withPrefill(field models.FormField, prefill models.FieldPrefill) models.FormField {
+	field.Prefill = &prefill
+	return field
+}
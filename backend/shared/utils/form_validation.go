@@ -0,0 +1,344 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"shared/models"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// formEmailRegex is the same pattern the form builder checks email fields with
+var formEmailRegex = regexp.MustCompile(`(?i)^[A-Z0-9._%+-]+@[A-Z0-9.-]+\.[A-Z]{2,}$`)
+
+// ValidateFormData checks every submitted value against its field in the form and returns an error message per field key.
+// Organizers editing a response can set disabled and internal fields, applicants can't.
+// Hidden fields are skipped, use DropHiddenFormFields before storing the data.
+func ValidateFormData(form *models.FormStructure, data map[string]interface{}, asOrganizer bool) map[string]string {
+	errors := map[string]string{}
+
+	fields := map[string]models.FormField{}
+	for _, field := range form.Attrs {
+		fields[field.Key] = field
+	}
+
+	for key := range data {
+		if _, ok := fields[key]; !ok {
+			errors[key] = "Unknown field"
+		}
+	}
+
+	visible := VisibleFormFields(form, data)
+	for _, field := range form.Attrs {
+		if !visible[field.Key] {
+			continue
+		}
+
+		value, submitted := data[field.Key]
+
+		// Internal fields are hidden from applicants, so they can neither fill them in nor be required to
+		if field.IsInternal && !asOrganizer {
+			if submitted {
+				errors[field.Key] = "This field can only be set by organizers"
+			}
+			continue
+		}
+
+		if !submitted || isEmptyFormValue(value) {
+			if field.Required && !(field.Disabled && !asOrganizer) {
+				errors[field.Key] = "This field is required"
+			}
+			continue
+		}
+
+		if field.Disabled && !asOrganizer && !isDefaultFormValue(field, value) {
+			errors[field.Key] = "This field can't be changed"
+			continue
+		}
+
+		if err := validateFormValue(field, value); err != "" {
+			errors[field.Key] = err
+		}
+	}
+
+	return errors
+}
+
+// isEmptyFormValue returns true for the values the form builder sends for an unanswered field
+func isEmptyFormValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		// An address the applicant cleared still has its parts, just blank
+		for _, part := range v {
+			if !isEmptyFormValue(part) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isDefaultFormValue compares a submitted value with the field's default by the field's type,
+// an empty default is the type's zero value, eg: an unticked checkbox
+func isDefaultFormValue(field models.FormField, value interface{}) bool {
+	defaultValue := strings.TrimSpace(string(field.DefaultValue))
+
+	switch field.Type {
+	case models.FieldTypeCheckbox:
+		checked, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		if defaultValue == "" {
+			return !checked
+		}
+		expected, err := strconv.ParseBool(defaultValue)
+		return err == nil && checked == expected
+
+	case models.FieldTypeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return false
+		}
+		if defaultValue == "" {
+			return number == 0
+		}
+		expected, err := strconv.ParseFloat(defaultValue, 64)
+		return err == nil && number == expected
+
+	case models.FieldTypeDate, models.FieldTypeTimestamp:
+		// The same moment can be serialized with or without milliseconds or in another offset
+		text, ok := value.(string)
+		if !ok {
+			return false
+		}
+		submitted, err := time.Parse(time.RFC3339, text)
+		if err != nil {
+			return false
+		}
+		if expected, err := time.Parse(time.RFC3339, defaultValue); err == nil {
+			return submitted.Equal(expected)
+		}
+		return field.Type == models.FieldTypeDate && submitted.Format("2006-01-02") == defaultValue
+	}
+
+	return fmt.Sprintf("%v", value) == defaultValue
+}
+
+// validateFormValue checks a single non-empty value against its field, returning an empty string if it's valid
+func validateFormValue(field models.FormField, value interface{}) string {
+	validation := field.AdditionalValidation
+
+	switch field.Type {
+	case models.FieldTypeNumber:
+		number, ok := value.(float64)
+		if !ok {
+			return "Must be a number"
+		}
+		if validation.Min != 0 && number < float64(validation.Min) {
+			return fmt.Sprintf("Must be at least %d", validation.Min)
+		}
+		if validation.Max != 0 && number > float64(validation.Max) {
+			return fmt.Sprintf("Must be at most %d", validation.Max)
+		}
+
+	case models.FieldTypeText, models.FieldTypeTextArea, models.FieldTypeRichText, models.FieldTypeTelephone:
+		text, ok := value.(string)
+		if !ok {
+			return "Must be text"
+		}
+		if err := validateLength(utf8.RuneCountInString(text), validation, "characters"); err != "" {
+			return err
+		}
+		if field.Type == models.FieldTypeText {
+			return validateFormEmail(text, validation.IsEmail)
+		}
+
+	case models.FieldTypeColorPicker, models.FieldTypeCustomSelect:
+		if _, ok := value.(string); !ok {
+			return "Must be text"
+		}
+
+	case models.FieldTypeSelect, models.FieldTypeRadio:
+		option, ok := value.(string)
+		if !ok {
+			return "Must be a single option"
+		}
+		if !isAllowedOption(field, option) {
+			return fmt.Sprintf("%s is not one of the options", option)
+		}
+
+	case models.FieldTypeMultiSelect, models.FieldTypeCustomMultiSelect:
+		items, ok := value.([]interface{})
+		if !ok {
+			return "Must be a list of options"
+		}
+		for _, item := range items {
+			option, ok := item.(string)
+			if !ok {
+				return "Must be a list of options"
+			}
+			if field.Type == models.FieldTypeMultiSelect && !isAllowedOption(field, option) {
+				return fmt.Sprintf("%s is not one of the options", option)
+			}
+		}
+		if err := validateLength(len(items), validation, "options"); err != "" {
+			return err
+		}
+
+	case models.FieldTypeCheckbox:
+		checked, ok := value.(bool)
+		if !ok {
+			return "Must be true or false"
+		}
+		// A required checkbox is one that has to be ticked, eg: agreeing to a code of conduct
+		if field.Required && !checked {
+			return "This field is required"
+		}
+
+	case models.FieldTypeDate, models.FieldTypeTimestamp:
+		text, ok := value.(string)
+		if !ok {
+			return "Must be a date"
+		}
+		if _, err := time.Parse(time.RFC3339, text); err != nil {
+			return "Must be a date"
+		}
+
+	case models.FieldTypeFile:
+		// The upload itself is checked against the field when it's uploaded and attached
+		fileID, ok := value.(string)
+		if !ok || !primitive.IsValidObjectID(fileID) {
+			return "Must be an uploaded file"
+		}
+
+	case models.FieldTypeAddress:
+		address, ok := value.(map[string]interface{})
+		if !ok {
+			return "Must be an address"
+		}
+		for _, part := range address {
+			if _, ok := part.(string); !ok && part != nil {
+				return "Must be an address"
+			}
+		}
+
+	default:
+		return fmt.Sprintf("Unsupported field type %s", field.Type)
+	}
+
+	return ""
+}
+
+// validateLength applies Min and Max to a length, zero means no limit
+func validateLength(length int, validation models.FieldValidation, unit string) string {
+	if validation.Min != 0 && length < validation.Min {
+		return fmt.Sprintf("Must be at least %d %s", validation.Min, unit)
+	}
+	if validation.Max != 0 && length > validation.Max {
+		return fmt.Sprintf("Must be at most %d %s", validation.Max, unit)
+	}
+	return ""
+}
+
+// isAllowedOption checks the option is one of the field's options.
+// Fields whose options come from a selector source are not checked since the source can change.
+func isAllowedOption(field models.FormField, option string) bool {
+	if field.AdditionalOptions.UseDefaultValuesFrom != "" || len(field.Options) == 0 {
+		return true
+	}
+	return StringInSlice(option, field.Options)
+}
+
+// EmailInDomain reports whether the email address is at the domain, or one of its subdomains if allowed
+func EmailInDomain(email string, domain string, allowSubdomains bool) bool {
+	email = strings.ToLower(email)
+	domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+
+	at := strings.LastIndex(email, "@")
+	if at < 0 || domain == "" {
+		return false
+	}
+
+	emailDomain := email[at+1:]
+	return emailDomain == domain || (allowSubdomains && strings.HasSuffix(emailDomain, "."+domain))
+}
+
+// validateFormEmail applies the same email rules as the form builder
+func validateFormEmail(email string, options models.EmailValidationOptions) string {
+	if options.IsEmail && !formEmailRegex.MatchString(email) {
+		return "Invalid email address"
+	}
+
+	lowerEmail := strings.ToLower(email)
+	if len(options.RequireDomain) > 0 {
+		allowed := false
+		for _, required := range options.RequireDomain {
+			if EmailInDomain(email, required, options.AllowSubdomains) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return "Disallowed domain, allowed domains: " + strings.Join(options.RequireDomain, ", ")
+		}
+	}
+
+	if len(options.AllowTLDs) > 0 {
+		allowed := false
+		for _, tld := range options.AllowTLDs {
+			if strings.HasSuffix(lowerEmail, "."+strings.ToLower(strings.TrimPrefix(tld, "."))) {
+				allowed = true
+				break
+			}
+		}
+
+		if !allowed {
+			return "Disallowed top-level domain, allowed top-level domains: " + strings.Join(options.AllowTLDs, ", ")
+		}
+	}
+
+	return ""
+}
+
+// FormDataErrorMessage joins field errors into a single message, using each field's question
+func FormDataErrorMessage(form *models.FormStructure, errors map[string]string) string {
+	var messages []string
+	for _, field := range form.Attrs {
+		if err, ok := errors[field.Key]; ok {
+			messages = append(messages, field.Question+": "+err)
+		}
+	}
+
+	var unknown []string
+	for key, err := range errors {
+		if !formHasField(form, key) {
+			unknown = append(unknown, key+": "+err)
+		}
+	}
+	sort.Strings(unknown)
+
+	return strings.Join(append(messages, unknown...), "\n")
+}
+
+func formHasField(form *models.FormStructure, key string) bool {
+	for _, field := range form.Attrs {
+		if field.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFormData(t *testing.T) {
+	form := &models.FormStructure{
+		Attrs: []models.FormField{
+			{Key: "name", Question: "Name", Type: models.FieldTypeText, Required: true, AdditionalValidation: models.FieldValidation{Max: 10}},
+			{Key: "email", Question: "Email", Type: models.FieldTypeText, AdditionalValidation: models.FieldValidation{IsEmail: models.EmailValidationOptions{
+				IsEmail: true, RequireDomain: []string{"school.edu"}, AllowSubdomains: true,
+			}}},
+			{Key: "age", Question: "Age", Type: models.FieldTypeNumber, AdditionalValidation: models.FieldValidation{Min: 18}},
+			{Key: "shirt", Question: "Shirt", Type: models.FieldTypeSelect, Options: []string{"S", "M", "L"}},
+			{Key: "tracks", Question: "Tracks", Type: models.FieldTypeMultiSelect, Options: []string{"web", "hardware"}, AdditionalValidation: models.FieldValidation{Max: 1}},
+			{Key: "coc", Question: "Code of conduct", Type: models.FieldTypeCheckbox, Required: true},
+			{Key: "dob", Question: "Birthday", Type: models.FieldTypeDate},
+			{Key: "source", Question: "Source", Type: models.FieldTypeText, Disabled: true, DefaultValue: "web"},
+			{Key: "newsletter", Question: "Newsletter", Type: models.FieldTypeCheckbox, Disabled: true},
+			{Key: "cohort", Question: "Cohort", Type: models.FieldTypeNumber, Disabled: true, DefaultValue: "2"},
+			{Key: "start", Question: "Start", Type: models.FieldTypeTimestamp, Disabled: true, DefaultValue: "2026-03-02T09:30:00Z"},
+			{Key: "accepted", Question: "Accepted", Type: models.FieldTypeCheckbox, Required: true, IsInternal: true},
+			{Key: "address", Question: "Address", Type: models.FieldTypeAddress, Required: true},
+		},
+	}
+
+	valid := func() map[string]interface{} {
+		return map[string]interface{}{
+			"name":   "Alice",
+			"email":  "alice@cs.school.edu",
+			"age":    float64(21),
+			"shirt":  "M",
+			"tracks": []interface{}{"web"},
+			"coc":    true,
+			"dob":    "2003-04-05T00:00:00.000Z",
+			"source": "web",
+			"address": map[string]interface{}{
+				"streetAddress": "1 Main St", "city": "Springfield", "region": "", "zipCode": "", "country": "US",
+			},
+
+			// Disabled fields match their defaults even when they're serialized differently
+			"newsletter": false,
+			"cohort":     float64(2),
+			"start":      "2026-03-02T04:30:00.000-05:00",
+		}
+	}
+
+	assert.Empty(t, ValidateFormData(form, valid(), false))
+
+	cases := []struct {
+		name     string
+		key      string
+		value    interface{}
+		expected string
+	}{
+		{"Missing required", "name", "", "This field is required"},
+		{"Too long", "name", "Alice Alison", "Must be at most 10 characters"},
+		{"Wrong type", "name", float64(1), "Must be text"},
+		{"Invalid email", "email", "alice", "Invalid email address"},
+		{"Wrong email domain", "email", "alice@gmail.com", "Disallowed domain, allowed domains: school.edu"},
+		{"Number too small", "age", float64(17), "Must be at least 18"},
+		{"Unknown option", "shirt", "XL", "XL is not one of the options"},
+		{"Too many options", "tracks", []interface{}{"web", "hardware"}, "Must be at most 1 options"},
+		{"Unticked required checkbox", "coc", false, "This field is required"},
+		{"Invalid date", "dob", "yesterday", "Must be a date"},
+		{"Changed disabled field", "source", "email", "This field can't be changed"},
+		{"Ticked disabled checkbox", "newsletter", true, "This field can't be changed"},
+		{"Changed disabled number", "cohort", float64(3), "This field can't be changed"},
+		{"Changed disabled timestamp", "start", "2026-03-02T09:31:00Z", "This field can't be changed"},
+		{"Disabled checkbox as text", "newsletter", "false", "This field can't be changed"},
+		{"Internal field", "accepted", true, "This field can only be set by organizers"},
+		{"Blank address", "address", map[string]interface{}{"streetAddress": "", "city": " ", "region": "", "zipCode": "", "country": ""}, "This field is required"},
+		{"Address part not text", "address", map[string]interface{}{"city": float64(1)}, "Must be an address"},
+		{"Unknown field", "score", float64(10), "Unknown field"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data := valid()
+			data[tc.key] = tc.value
+			assert.Equal(t, map[string]string{tc.key: tc.expected}, ValidateFormData(form, data, false))
+		})
+	}
+
+	t.Run("Organizers can change disabled and internal fields", func(t *testing.T) {
+		data := valid()
+		data["source"] = "email"
+		data["accepted"] = true
+		assert.Empty(t, ValidateFormData(form, data, true))
+
+		// Internal fields are only required when an organizer saves the response
+		delete(data, "accepted")
+		assert.Equal(t, map[string]string{"accepted": "This field is required"}, ValidateFormData(form, data, true))
+	})
+}
+
+func TestIsDefaultFormValue(t *testing.T) {
+	date := models.FormField{Type: models.FieldTypeDate, DefaultValue: "2026-03-02"}
+	assert.True(t, isDefaultFormValue(date, "2026-03-02T00:00:00.000Z"))
+	assert.False(t, isDefaultFormValue(date, "2026-03-03T00:00:00.000Z"))
+
+	text := models.FormField{Type: models.FieldTypeText}
+	assert.True(t, isDefaultFormValue(text, ""))
+	assert.False(t, isDefaultFormValue(text, "web"))
+}
+
+func TestEmailInDomain(t *testing.T) {
+	assert.True(t, EmailInDomain("Ada@University.edu", "university.edu", false))
+	assert.True(t, EmailInDomain("ada@university.edu", "@university.edu", false))
+	assert.False(t, EmailInDomain("ada@cs.university.edu", "university.edu", false))
+	assert.True(t, EmailInDomain("ada@cs.university.edu", "university.edu", true))
+	assert.False(t, EmailInDomain("ada@notuniversity.edu", "university.edu", true))
+	assert.False(t, EmailInDomain("not-an-email", "university.edu", true))
+}
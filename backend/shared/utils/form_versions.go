@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/json"
+	"shared/models"
+)
+
+// formSchema is the part of a form that's copied into each version
+type formSchema struct {
+	Attrs    []models.FormField   `json:"attrs"`
+	Sections []models.FormSection `json:"sections"`
+}
+
+// FormSchemaEqual reports whether two forms have the same fields and sections, in the same order
+func FormSchemaEqual(a models.FormStructure, b models.FormStructure) bool {
+	return formSchemaJSON(a.Attrs, a.Sections) == formSchemaJSON(b.Attrs, b.Sections)
+}
+
+// formSchemaJSON serializes a schema so nil and empty slices compare equal
+func formSchemaJSON(attrs []models.FormField, sections []models.FormSection) string {
+	schema := formSchema{Attrs: attrs, Sections: sections}
+	if len(schema.Attrs) == 0 {
+		schema.Attrs = nil
+	}
+	if len(schema.Sections) == 0 {
+		schema.Sections = nil
+	}
+
+	encoded, _ := json.Marshal(schema)
+	return string(encoded)
+}
+
+// MergeRemovedFormFields returns the latest fields followed by the fields that only exist in older versions,
+// using the newest definition of each removed field. The keys of the removed fields are also returned.
+func MergeRemovedFormFields(latest []models.FormField, versions []models.FormVersion) ([]models.FormField, map[string]bool) {
+	fields := append([]models.FormField{}, latest...)
+	seen := map[string]bool{}
+	for _, attr := range latest {
+		seen[attr.Key] = true
+	}
+
+	// Newest version first so a removed field is described the way it was last seen
+	removed := map[string]bool{}
+	for i := len(versions) - 1; i >= 0; i-- {
+		for _, attr := range versions[i].Attrs {
+			if seen[attr.Key] {
+				continue
+			}
+
+			seen[attr.Key] = true
+			removed[attr.Key] = true
+			fields = append(fields, attr)
+		}
+	}
+
+	return fields, removed
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormSchemaEqual(t *testing.T) {
+	a := models.FormStructure{Name: "Apply", Attrs: []models.FormField{{Key: "name", Question: "Name", Type: models.FieldTypeText}}}
+	b := models.FormStructure{Name: "Renamed", Attrs: []models.FormField{{Key: "name", Question: "Name", Type: models.FieldTypeText}}, Sections: []models.FormSection{}}
+	assert.True(t, FormSchemaEqual(a, b), "only the fields and sections are compared")
+
+	b.Attrs[0].Type = models.FieldTypeTextArea
+	assert.False(t, FormSchemaEqual(a, b))
+
+	assert.True(t, FormSchemaEqual(models.FormStructure{}, models.FormStructure{Attrs: []models.FormField{}}))
+}
+
+func TestMergeRemovedFormFields(t *testing.T) {
+	latest := []models.FormField{{Key: "name", Question: "Full name"}}
+	versions := []models.FormVersion{
+		{Version: 1, Attrs: []models.FormField{{Key: "name", Question: "Name"}, {Key: "age", Question: "Age"}}},
+		{Version: 2, Attrs: []models.FormField{{Key: "name", Question: "Name"}, {Key: "age", Question: "Your age"}, {Key: "school", Question: "School"}}},
+	}
+
+	fields, removed := MergeRemovedFormFields(latest, versions)
+
+	assert.Equal(t, []string{"name", "age", "school"}, []string{fields[0].Key, fields[1].Key, fields[2].Key})
+	assert.Equal(t, "Full name", fields[0].Question)
+	assert.Equal(t, "Your age", fields[1].Question, "removed fields use their newest definition")
+	assert.Equal(t, map[string]bool{"age": true, "school": true}, removed)
+}
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"reflect"
+	"shared/models"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DiffResponses lists the fields that differ between two versions of a response: its answers, ordered by field key,
+// followed by its decision, tags, capacity status and whether it's withdrawn
+func DiffResponses(before models.FormResponse, after models.FormResponse) []models.ResponseFieldChange {
+	changes := []models.ResponseFieldChange{}
+
+	keys := map[string]bool{}
+	for key := range before.Data {
+		keys[key] = true
+	}
+	for key := range after.Data {
+		keys[key] = true
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	for _, key := range sorted {
+		beforeValue, afterValue := normalizeResponseValue(before.Data[key]), normalizeResponseValue(after.Data[key])
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes = append(changes, models.ResponseFieldChange{Field: key, Before: beforeValue, After: afterValue})
+		}
+	}
+
+	if before.CurrentDecision() != after.CurrentDecision() {
+		changes = append(changes, models.ResponseFieldChange{
+			Field:  models.DecisionFieldID,
+			Before: string(before.CurrentDecision()),
+			After:  string(after.CurrentDecision()),
+		})
+	}
+
+	if !reflect.DeepEqual(responseTags(before), responseTags(after)) {
+		changes = append(changes, models.ResponseFieldChange{Field: models.TagsFieldID, Before: responseTags(before), After: responseTags(after)})
+	}
+
+	if responseStatus(before) != responseStatus(after) {
+		changes = append(changes, models.ResponseFieldChange{
+			Field:  models.ResponseStatusFieldID,
+			Before: string(responseStatus(before)),
+			After:  string(responseStatus(after)),
+		})
+	}
+
+	if before.IsDeleted != after.IsDeleted {
+		changes = append(changes, models.ResponseFieldChange{Field: models.ResponseWithdrawnFieldID, Before: before.IsDeleted, After: after.IsDeleted})
+	}
+
+	return changes
+}
+
+// responseStatus is the response's capacity status, responses without one are submitted
+func responseStatus(response models.FormResponse) models.ResponseStatus {
+	if response.Status == "" {
+		return models.ResponseSubmitted
+	}
+	return response.Status
+}
+
+// responseTags is the response's tags in order, an empty list when it has none
+func responseTags(response models.FormResponse) []string {
+	tags := append([]string{}, response.Tags...)
+	sort.Strings(tags)
+	return tags
+}
+
+// normalizeResponseValue converts answers read from the database to the types they're bound to from JSON,
+// so an unchanged list or address isn't reported as changed
+func normalizeResponseValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case primitive.A:
+		return normalizeResponseValue([]interface{}(value))
+	case []interface{}:
+		list := make([]interface{}, len(value))
+		for i, item := range value {
+			list[i] = normalizeResponseValue(item)
+		}
+		return list
+	case primitive.D:
+		return normalizeResponseValue(value.Map())
+	case primitive.M:
+		return normalizeResponseValue(map[string]interface{}(value))
+	case map[string]interface{}:
+		document := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			document[key] = normalizeResponseValue(item)
+		}
+		return document
+	case int32:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case int:
+		return float64(value)
+	}
+	return value
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"shared/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiffResponses(t *testing.T) {
+	before := models.FormResponse{Data: map[string]interface{}{
+		"tracks":  primitive.A{"ai", "web"},
+		"address": primitive.D{{Key: "city", Value: "Toronto"}},
+		"age":     int32(20),
+		"name":    "Ada",
+		"notes":   "Strong",
+	}}
+	after := models.FormResponse{
+		Data: map[string]interface{}{
+			"tracks":  []interface{}{"ai", "web"},
+			"address": map[string]interface{}{"city": "Toronto"},
+			"age":     20.0,
+			"name":    "Ada L",
+			"github":  "ada",
+		},
+		Decision: models.DecisionAccepted,
+	}
+
+	assert.Equal(t, []models.ResponseFieldChange{
+		{Field: "github", Before: nil, After: "ada"},
+		{Field: "name", Before: "Ada", After: "Ada L"},
+		{Field: "notes", Before: "Strong", After: nil},
+		{Field: models.DecisionFieldID, Before: "pending", After: "accepted"},
+	}, DiffResponses(before, after))
+
+	promoted := models.FormResponse{Status: models.ResponseSubmitted, IsDeleted: true}
+	assert.Equal(t, []models.ResponseFieldChange{
+		{Field: models.ResponseStatusFieldID, Before: "waitlisted", After: "submitted"},
+		{Field: models.ResponseWithdrawnFieldID, Before: false, After: true},
+	}, DiffResponses(models.FormResponse{Status: models.ResponseWaitlisted}, promoted))
+
+	// Tags are compared as a set
+	tagged := models.FormResponse{Tags: []string{"sponsor-pick", "needs-travel"}}
+	assert.Empty(t, DiffResponses(tagged, models.FormResponse{Tags: []string{"needs-travel", "sponsor-pick"}}))
+	assert.Equal(t, []models.ResponseFieldChange{
+		{Field: models.TagsFieldID, Before: []string{"needs-travel", "sponsor-pick"}, After: []string{"needs-travel"}},
+	}, DiffResponses(tagged, models.FormResponse{Tags: []string{"needs-travel"}}))
+
+	assert.Empty(t, DiffResponses(before, before))
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidSignedToken is returned when a signed token is malformed or its signature does not match
+var ErrInvalidSignedToken = errors.New("invalid signed token")
+
+// SignPayload signs a payload for a specific purpose, so a token issued for one purpose can't be used for another.
+// These tokens are for links we email out, they are not login tokens and never go through VerifyJWT.
+func SignPayload(purpose string, payload []byte) string {
+	encodedPayload := base64.RawURLEncoding.EncodeToString(payload)
+	return encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signature(purpose, encodedPayload))
+}
+
+// VerifySignedPayload checks a token produced by SignPayload and returns its payload
+func VerifySignedPayload(purpose string, token string) ([]byte, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, ErrInvalidSignedToken
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(sig, signature(purpose, encodedPayload)) {
+		return nil, ErrInvalidSignedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, ErrInvalidSignedToken
+	}
+
+	return payload, nil
+}
+
+func signature(purpose string, encodedPayload string) []byte {
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte(purpose + ":" + encodedPayload))
+	return mac.Sum(nil)
+}
+
+const unsubscribeTokenPurpose = "unsubscribe"
+
+type unsubscribePayload struct {
+	Email   string `json:"e"`
+	EventID string `json:"v"`
+}
+
+// GenerateUnsubscribeToken creates the token embedded in an email's unsubscribe link
+func GenerateUnsubscribeToken(email string, eventID primitive.ObjectID) (string, error) {
+	payload, err := json.Marshal(unsubscribePayload{Email: email, EventID: eventID.Hex()})
+	if err != nil {
+		return "", err
+	}
+	return SignPayload(unsubscribeTokenPurpose, payload), nil
+}
+
+// VerifyUnsubscribeToken returns the email and event an unsubscribe token was issued for
+func VerifyUnsubscribeToken(token string) (string, primitive.ObjectID, error) {
+	payload, err := VerifySignedPayload(unsubscribeTokenPurpose, token)
+	if err != nil {
+		return "", primitive.NilObjectID, err
+	}
+
+	var data unsubscribePayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return "", primitive.NilObjectID, ErrInvalidSignedToken
+	}
+
+	eventID, err := primitive.ObjectIDFromHex(data.EventID)
+	if err != nil || data.Email == "" {
+		return "", primitive.NilObjectID, ErrInvalidSignedToken
+	}
+
+	return data.Email, eventID, nil
+}
+
+const formInviteTokenPurpose = "form-invite"
+
+// FormInviteLinkPlaceholder is the email template placeholder the AllowFormAccess action fills with an invite link
+const FormInviteLinkPlaceholder = "invite_link"
+
+// GenerateFormInviteToken creates the token embedded in a form invite link
+func GenerateFormInviteToken(inviteID primitive.ObjectID) string {
+	return SignPayload(formInviteTokenPurpose, inviteID[:])
+}
+
+// VerifyFormInviteToken returns the ID of the invite a token was issued for
+func VerifyFormInviteToken(token string) (primitive.ObjectID, error) {
+	return verifyObjectIDToken(formInviteTokenPurpose, token)
+}
+
+const pendingResponseTokenPurpose = "pending-response"
+
+// ResponseConfirmLinkPlaceholder is the email template placeholder filled with the link confirming an anonymous response
+const ResponseConfirmLinkPlaceholder = "confirm_link"
+
+// GeneratePendingResponseToken creates the token embedded in the link confirming an anonymous response
+func GeneratePendingResponseToken(pendingID primitive.ObjectID) string {
+	return SignPayload(pendingResponseTokenPurpose, pendingID[:])
+}
+
+// VerifyPendingResponseToken returns the ID of the pending response a confirmation token was issued for
+func VerifyPendingResponseToken(token string) (primitive.ObjectID, error) {
+	return verifyObjectIDToken(pendingResponseTokenPurpose, token)
+}
+
+const anonymousLinkTokenPurpose = "anonymous-link"
+
+type anonymousLinkPayload struct {
+	UserID string `json:"u"`
+	Email  string `json:"e"`
+}
+
+// GenerateAnonymousLinkToken creates the token embedded in the link confirming the anonymous responses sent with
+// the email belong to the user
+func GenerateAnonymousLinkToken(userID primitive.ObjectID, email string) (string, error) {
+	payload, err := json.Marshal(anonymousLinkPayload{UserID: userID.Hex(), Email: email})
+	if err != nil {
+		return "", err
+	}
+	return SignPayload(anonymousLinkTokenPurpose, payload), nil
+}
+
+// VerifyAnonymousLinkToken returns the user and email an anonymous link token was issued for
+func VerifyAnonymousLinkToken(token string) (primitive.ObjectID, string, error) {
+	payload, err := VerifySignedPayload(anonymousLinkTokenPurpose, token)
+	if err != nil {
+		return primitive.NilObjectID, "", err
+	}
+
+	var data anonymousLinkPayload
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return primitive.NilObjectID, "", ErrInvalidSignedToken
+	}
+
+	userID, err := primitive.ObjectIDFromHex(data.UserID)
+	if err != nil || data.Email == "" {
+		return primitive.NilObjectID, "", ErrInvalidSignedToken
+	}
+
+	return userID, data.Email, nil
+}
+
+// verifyObjectIDToken verifies a token whose payload is a single ObjectID
+func verifyObjectIDToken(purpose string, token string) (primitive.ObjectID, error) {
+	payload, err := VerifySignedPayload(purpose, token)
+	if err != nil {
+		return primitive.NilObjectID, err
+	}
+
+	var id primitive.ObjectID
+	if len(payload) != len(id) {
+		return primitive.NilObjectID, ErrInvalidSignedToken
+	}
+	copy(id[:], payload)
+
+	return id, nil
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSignedPayload(t *testing.T) {
+	token := SignPayload("testing", []byte("hello"))
+
+	payload, err := VerifySignedPayload("testing", token)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello"), payload)
+
+	// A token can't be used for a different purpose
+	_, err = VerifySignedPayload("other", token)
+	assert.Equal(t, ErrInvalidSignedToken, err)
+
+	// Tampering with the payload invalidates the signature
+	tampered := SignPayload("testing", []byte("world"))[:6] + token[6:]
+	_, err = VerifySignedPayload("testing", tampered)
+	assert.Equal(t, ErrInvalidSignedToken, err)
+
+	_, err = VerifySignedPayload("testing", "not-a-token")
+	assert.Equal(t, ErrInvalidSignedToken, err)
+}
+
+func TestUnsubscribeToken(t *testing.T) {
+	eventID := primitive.NewObjectID()
+
+	token, err := GenerateUnsubscribeToken("alice@example.com", eventID)
+	assert.Nil(t, err)
+
+	email, tokenEventID, err := VerifyUnsubscribeToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, "alice@example.com", email)
+	assert.Equal(t, eventID, tokenEventID)
+
+	_, _, err = VerifyUnsubscribeToken(token + "x")
+	assert.NotNil(t, err)
+}
+
+func TestFormInviteToken(t *testing.T) {
+	inviteID := primitive.NewObjectID()
+
+	tokenInviteID, err := VerifyFormInviteToken(GenerateFormInviteToken(inviteID))
+	assert.Nil(t, err)
+	assert.Equal(t, inviteID, tokenInviteID)
+
+	// Tokens for other purposes aren't invites even if their payload is the right size
+	_, err = VerifyFormInviteToken(SignPayload(unsubscribeTokenPurpose, inviteID[:]))
+	assert.Equal(t, ErrInvalidSignedToken, err)
+}
+
+func TestPendingResponseToken(t *testing.T) {
+	pendingID := primitive.NewObjectID()
+
+	tokenPendingID, err := VerifyPendingResponseToken(GeneratePendingResponseToken(pendingID))
+	assert.Nil(t, err)
+	assert.Equal(t, pendingID, tokenPendingID)
+
+	// An invite token can't confirm a response
+	_, err = VerifyPendingResponseToken(GenerateFormInviteToken(pendingID))
+	assert.Equal(t, ErrInvalidSignedToken, err)
+}
+
+func TestAnonymousLinkToken(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	token, err := GenerateAnonymousLinkToken(userID, "alice@example.com")
+	assert.Nil(t, err)
+
+	tokenUserID, email, err := VerifyAnonymousLinkToken(token)
+	assert.Nil(t, err)
+	assert.Equal(t, userID, tokenUserID)
+	assert.Equal(t, "alice@example.com", email)
+
+	// An unsubscribe token for the same address can't link responses
+	unsubscribeToken, err := GenerateUnsubscribeToken("alice@example.com", userID)
+	assert.Nil(t, err)
+	_, _, err = VerifyAnonymousLinkToken(unsubscribeToken)
+	assert.Equal(t, ErrInvalidSignedToken, err)
+}
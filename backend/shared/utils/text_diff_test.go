@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffLines(t *testing.T) {
+	diff := DiffLines("Hello\nWelcome to the event\nThanks", "Hello\nWelcome to the hackathon\nThanks\nBye")
+	assert.Equal(t, []DiffLine{
+		{Op: DiffEqual, Text: "Hello"},
+		{Op: DiffDelete, Text: "Welcome to the event"},
+		{Op: DiffInsert, Text: "Welcome to the hackathon"},
+		{Op: DiffEqual, Text: "Thanks"},
+		{Op: DiffInsert, Text: "Bye"},
+	}, diff)
+
+	assert.Equal(t, []DiffLine{{Op: DiffEqual, Text: "Same"}}, DiffLines("Same", "Same"))
+}
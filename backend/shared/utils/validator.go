@@ -154,7 +154,7 @@ func requireExistsIf(fl validator.FieldLevel) bool {
 func validateEventType(fl validator.FieldLevel) bool {
 	if event, ok := fl.Field().Interface().(models.PipelineEvent); ok {
 		switch event.Type {
-		case "FormSubmission", "FieldChange":
+		case "FormSubmission", "FieldChange", "WaitlistPromotion", "ReviewScored":
 			return true
 		default:
 			return false
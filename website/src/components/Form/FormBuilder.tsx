@@ -104,7 +104,7 @@ const FormBuilder: React.FC<FormBuilderProps> = ({
 
           // Check if the options have already been fetched
           if (!fetchedOptions[defaultValuesFrom]) {
-            getSelectorOptions(defaultValuesFrom)
+            getSelectorOptions(defaultValuesFrom, formStructure.eventID)
               .then((options) => {
                 setFetchedOptions((prevOptions) => ({
                   ...prevOptions,
@@ -2,9 +2,13 @@ import { FormStructure } from "@/types/models/Form";
 import api from "./AxiosInterceptor";
 import { AxiosResponse } from "axios";
 
-export const getSelectorOptions = async (name: string): Promise<string[]> => {
+export const getSelectorOptions = async (
+  name: string,
+  eventId?: string
+): Promise<string[]> => {
   const response = await api.get<string[]>(
-    `/forms/default_selector_values/${name}`
+    `/forms/default_selector_values/${name}`,
+    { params: { event_id: eventId } }
   );
   return response.data;
 };
@@ -3,15 +3,18 @@ import api from "./AxiosInterceptor";
 import { AxiosResponse } from "axios";
 import { FormResponse } from "@/types/models/Response";
 
+// query takes filter[<field key>]=<operator>:<value>, search, sort, order, limit and cursor
 export const GetResponses = async (
   formID: string,
+  query?: Record<string, string>,
 ): Promise<
   AxiosResponse<{
     responses: Record<string, any>;
     columnOrder: string[];
+    nextCursor: string;
   }>
 > => {
-  return api.get(`/forms/${formID}/responses`);
+  return api.get(`/forms/${formID}/responses`, { params: query });
 };
 
 export const SubmitResponse = async (
@@ -29,10 +32,99 @@ export const UpdateResponse = async (
   return api.put(`/forms/${formID}/responses/${responseID}`, data);
 }
 
+// query takes the same filters as GetResponses, plus columns, tz, schema and version
 export const DownloadResponses = async (
     formID: string,
+    format: "csv" | "xlsx" | "json" | "ndjson" = "csv",
+    query?: Record<string, string>,
     ): Promise<AxiosResponse<Blob>> => {
-    return api.get(`/forms/${formID}/responses/csv`, {
+    return api.get(`/forms/${formID}/responses/export`, {
+        params: { ...query, format },
         responseType: "blob",
     });
-}
\ No newline at end of file
+}
+export const GetResponseAnalytics = async (
+    formID: string,
+    query?: Record<string, string>,
+    ): Promise<AxiosResponse> => {
+    return api.get(`/forms/${formID}/responses/analytics`, { params: query });
+}
+
+export type Decision = "pending" | "accepted" | "rejected" | "waitlisted" | "confirmed" | "declined";
+
+export const SetDecision = async (
+    formID: string,
+    responseID: string,
+    decision: Decision,
+    ): Promise<AxiosResponse<{ id: string; decision: Decision; changed: boolean }>> => {
+    return api.put(`/forms/${formID}/responses/${responseID}/decision`, { decision });
+}
+
+// BulkDecide resolves with the NDJSON progress lines once every response has been decided
+export const BulkDecide = async (
+    formID: string,
+    request: { decision: Decision; responseIDs?: string[]; query?: Record<string, string>; limit?: number },
+    ): Promise<AxiosResponse<string>> => {
+    return api.post(`/forms/${formID}/responses/decisions`, request, { responseType: "text" });
+}
+
+export const GetResponseHistory = async (
+    formID: string,
+    responseID: string,
+    ): Promise<AxiosResponse> => {
+    return api.get(`/forms/${formID}/responses/${responseID}/history`);
+}
+
+export const RestoreResponse = async (
+    formID: string,
+    responseID: string,
+    changeID: string,
+    ): Promise<AxiosResponse<{ id: string; data: Record<string, any> }>> => {
+    return api.post(`/forms/${formID}/responses/${responseID}/history/${changeID}/restore`);
+}
+
+export const GetResponseTags = async (
+    formID: string,
+    ): Promise<AxiosResponse<{ tags: string[] }>> => {
+    return api.get(`/forms/${formID}/responses/tags`);
+}
+
+export const SetResponseTags = async (
+    formID: string,
+    responseID: string,
+    tags: string[],
+    ): Promise<AxiosResponse<{ id: string; tags: string[] }>> => {
+    return api.put(`/forms/${formID}/responses/${responseID}/tags`, { tags });
+}
+
+export const GetResponseNotes = async (
+    formID: string,
+    responseID: string,
+    ): Promise<AxiosResponse> => {
+    return api.get(`/forms/${formID}/responses/${responseID}/notes`);
+}
+
+export const AddResponseNote = async (
+    formID: string,
+    responseID: string,
+    body: string,
+    ): Promise<AxiosResponse<{ id: string }>> => {
+    return api.post(`/forms/${formID}/responses/${responseID}/notes`, { body });
+}
+
+export const UpdateResponseNote = async (
+    formID: string,
+    responseID: string,
+    noteID: string,
+    body: string,
+    ): Promise<AxiosResponse<{ id: string }>> => {
+    return api.put(`/forms/${formID}/responses/${responseID}/notes/${noteID}`, { body });
+}
+
+export const DeleteResponseNote = async (
+    formID: string,
+    responseID: string,
+    noteID: string,
+    ): Promise<AxiosResponse<{ id: string }>> => {
+    return api.delete(`/forms/${formID}/responses/${responseID}/notes/${noteID}`);
+}
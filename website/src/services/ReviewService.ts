@@ -0,0 +1,43 @@
+import api from "./AxiosInterceptor";
+import { AxiosResponse } from "axios";
+import { Review, ReviewRubric } from "@/types/models/Review";
+
+export const GetReviewRubric = async (
+  formID: string
+): Promise<AxiosResponse<{ rubric: ReviewRubric }>> => {
+  return api.get(`/forms/${formID}/reviews/rubric`);
+};
+
+export const SaveReviewRubric = async (
+  formID: string,
+  rubric: ReviewRubric
+): Promise<AxiosResponse<{ rubric: ReviewRubric }>> => {
+  return api.put(`/forms/${formID}/reviews/rubric`, rubric);
+};
+
+export const AssignReviews = async (
+  formID: string,
+  assignment: {
+    reviewers: string[];
+    strategy: "roundRobin" | "loadBalanced";
+    reviewsPerResponse: number;
+    responseIDs?: string[];
+  }
+): Promise<AxiosResponse<{ assigned: number; shortResponses: number }>> => {
+  return api.post(`/forms/${formID}/reviews/assignments`, assignment);
+};
+
+export const GetReviewQueue = async (
+  formID: string,
+  pending = false
+): Promise<AxiosResponse> => {
+  return api.get(`/forms/${formID}/reviews/queue`, { params: { pending } });
+};
+
+export const SubmitReview = async (
+  formID: string,
+  responseID: string,
+  review: { scores: Record<string, number>; comment?: string }
+): Promise<AxiosResponse<{ review: Review }>> => {
+  return api.put(`/forms/${formID}/reviews/responses/${responseID}`, review);
+};
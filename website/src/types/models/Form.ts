@@ -18,7 +18,7 @@ export type AdditionalOptions = {
   defaultTimezone?: string;
   showTimezone?: boolean;
   isPassword?: boolean;
-  useDefaultValuesFrom?: "mlh-schools" | undefined;
+  useDefaultValuesFrom?: string; // a built-in source like "mlh-schools" or one of the event's sources
   ifInputHTMLKeepHTML?: boolean; // for richtext
 };
 
@@ -4,4 +4,6 @@ export type FormResponse = {
     data: Record<string, any>;
     createdAt: Date;
     userID?: string;
+    decision?: "pending" | "accepted" | "rejected" | "waitlisted" | "confirmed" | "declined";
+    tags?: string[];
 }
\ No newline at end of file
@@ -0,0 +1,28 @@
+export type RubricCriterion = {
+    key: string;
+    label: string;
+    description?: string;
+    weight: number;
+    maxScore: number;
+}
+
+export type ReviewRubric = {
+    id?: string;
+    formID?: string;
+    criteria: RubricCriterion[];
+    blindMode?: boolean;
+    blindFields?: string[];
+    blindShowFiles?: boolean;
+}
+
+export type Review = {
+    id: string;
+    formID: string;
+    responseID: string;
+    reviewerID: string;
+    assignedAt: Date;
+    scores?: Record<string, number>;
+    comment?: string;
+    score: number;
+    submittedAt?: Date;
+}